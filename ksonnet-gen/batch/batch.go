@@ -0,0 +1,194 @@
+// Package batch generates the libs of several Kubernetes versions in one
+// run, as listed in a manifest.
+package batch
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/go-openapi/spec"
+	"github.com/ksonnet/ksonnet-lib/ksonnet-gen/ksonnet"
+	"github.com/ksonnet/ksonnet-lib/ksonnet-gen/kubespec"
+	"github.com/ksonnet/ksonnet-lib/ksonnet-gen/kubeversion"
+	"github.com/pkg/errors"
+)
+
+// Run generates the libs in a manifest and writes them to their output
+// directories. A lib which fails doesn't stop the others; its error is in
+// the report.
+//
+// The libs are generated concurrently. Each lib is generated with the version
+// data of its target, or with the version data of the kubeversion package if
+// it doesn't have any, so the version data of the package isn't changed.
+func Run(m *Manifest) (*Report, error) {
+	parallelism := m.Parallelism
+	if parallelism < 1 {
+		parallelism = runtime.NumCPU()
+	}
+
+	versionData, err := loadVersionData(m.Libs)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	cache := newSpecCache()
+	r := &Report{Results: make([]Result, len(m.Libs))}
+
+	sem := make(chan struct{}, parallelism)
+	var wg sync.WaitGroup
+
+	for i := range m.Libs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			t := m.Libs[i]
+			r.Results[i] = generate(t, versionData[t.VersionData], cache)
+		}(i)
+	}
+
+	wg.Wait()
+
+	r.Duration = time.Since(start)
+	return r, nil
+}
+
+// loadVersionData reads the version data of the targets, keyed by their
+// directory. Each directory is read once. Targets without version data use
+// the version data of the kubeversion package.
+func loadVersionData(targets []Target) (map[string]*kubeversion.Data, error) {
+	current, err := kubeversion.Current()
+	if err != nil {
+		return nil, err
+	}
+
+	out := map[string]*kubeversion.Data{"": current}
+	for _, t := range targets {
+		if _, ok := out[t.VersionData]; ok {
+			continue
+		}
+
+		d, err := kubeversion.ReadDir(t.VersionData)
+		if err != nil {
+			return nil, errors.Wrapf(err, "load version data %s", t.VersionData)
+		}
+		out[t.VersionData] = d
+	}
+
+	return out, nil
+}
+
+func generate(t Target, versionData *kubeversion.Data, cache *specCache) Result {
+	start := time.Now()
+	result := Result{Output: t.Output, Source: t.Source}
+
+	lib, err := generateLib(t, versionData, cache)
+	if err != nil {
+		result.Err = err
+	} else {
+		result.Version = lib.Version
+		result.Types = lib.Types
+		result.Warnings = lib.Warnings
+	}
+
+	result.Duration = time.Since(start)
+	return result
+}
+
+func generateLib(t Target, versionData *kubeversion.Data, cache *specCache) (*ksonnet.Lib, error) {
+	apiSpec, checksum, err := cache.load(t.Source)
+	if err != nil {
+		return nil, err
+	}
+
+	deprecated := true
+	if t.DeprecatedIdentifiers != nil {
+		deprecated = *t.DeprecatedIdentifiers
+	}
+
+	var patterns []*ksonnet.NamePattern
+	for _, pattern := range t.NamePatterns {
+		p, err := ksonnet.ParseNamePattern(pattern)
+		if err != nil {
+			return nil, err
+		}
+		patterns = append(patterns, p)
+	}
+
+	opts := []ksonnet.CatalogOpt{
+		ksonnet.CatalogOptDeprecatedIdentifiers(deprecated),
+		ksonnet.CatalogOptNamePatterns(patterns...),
+		ksonnet.CatalogOptVersionData(versionData),
+		ksonnet.CatalogOptJSONSchemas(t.JSONSchema),
+		ksonnet.CatalogOptTypeScript(t.TypeScript),
+	}
+	if len(t.Roots) > 0 {
+		opts = append(opts, ksonnet.CatalogOptGeneric(t.Roots...))
+	}
+
+	lib, err := ksonnet.GenerateLibFromSpec(apiSpec, checksum, opts...)
+	if err != nil {
+		return nil, errors.Wrap(err, "generate lib")
+	}
+
+	if err := os.MkdirAll(t.Output, 0755); err != nil {
+		return nil, errors.Wrapf(err, "create %s", t.Output)
+	}
+
+	if lib.JSONSchemas != nil {
+		if err := os.MkdirAll(filepath.Join(t.Output, "schemas"), 0755); err != nil {
+			return nil, errors.Wrapf(err, "create %s", filepath.Join(t.Output, "schemas"))
+		}
+	}
+
+	for name, content := range lib.Files() {
+		if err := ioutil.WriteFile(filepath.Join(t.Output, name), content, 0644); err != nil {
+			return nil, errors.Wrapf(err, "write %s", name)
+		}
+	}
+
+	return lib, nil
+}
+
+// specCache imports each swagger spec once, so libs generated from the same
+// spec share it.
+type specCache struct {
+	mu      sync.Mutex
+	entries map[string]*specEntry
+}
+
+type specEntry struct {
+	once     sync.Once
+	apiSpec  *spec.Swagger
+	checksum string
+	err      error
+}
+
+func newSpecCache() *specCache {
+	return &specCache{entries: make(map[string]*specEntry)}
+}
+
+func (c *specCache) load(source string) (*spec.Swagger, string, error) {
+	c.mu.Lock()
+	e, ok := c.entries[source]
+	if !ok {
+		e = &specEntry{}
+		c.entries[source] = e
+	}
+	c.mu.Unlock()
+
+	e.once.Do(func() {
+		e.apiSpec, e.checksum, e.err = kubespec.Import(source)
+		e.err = errors.Wrapf(e.err, "import %s", source)
+	})
+
+	return e.apiSpec, e.checksum, e.err
+}
@@ -0,0 +1,193 @@
+package batch
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ksonnet/ksonnet-lib/ksonnet-gen/ksonnet"
+	"github.com/ksonnet/ksonnet-lib/ksonnet-gen/kubeversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadManifest(t *testing.T) {
+	m, err := LoadManifest("testdata/manifest.json")
+	require.NoError(t, err)
+
+	deprecated := false
+	expected := &Manifest{
+		Parallelism: 2,
+		Libs: []Target{
+			{
+				Output: "testdata/out/ksonnet.beta.3",
+				Source: "../ksonnet/testdata/swagger-1.8.json",
+			},
+			{
+				Output:                "/tmp/ksonnet.beta.4",
+				Source:                "https://example.com/swagger.json",
+				VersionData:           "testdata/versions",
+				DeprecatedIdentifiers: &deprecated,
+			},
+		},
+	}
+	assert.Equal(t, expected, m)
+
+	_, err = LoadManifest("testdata/missing.json")
+	require.Error(t, err)
+}
+
+func TestRun(t *testing.T) {
+	dir, err := ioutil.TempDir("", "batch")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	deprecated := false
+	m := &Manifest{
+		Parallelism: 2,
+		Libs: []Target{
+			{Output: filepath.Join(dir, "1.8"), Source: "../ksonnet/testdata/swagger-1.8.json"},
+			{Output: filepath.Join(dir, "1.9"), Source: "testdata/swagger-1.9.json"},
+			{
+				Output:                filepath.Join(dir, "1.8-current"),
+				Source:                "../ksonnet/testdata/swagger-1.8.json",
+				DeprecatedIdentifiers: &deprecated,
+			},
+			{Output: filepath.Join(dir, "1.9-data"), Source: "testdata/swagger-1.9.json", VersionData: "testdata/versions"},
+			{Output: filepath.Join(dir, "missing"), Source: "testdata/missing.json"},
+		},
+	}
+
+	r, err := Run(m)
+	require.NoError(t, err)
+	require.Len(t, r.Results, 5)
+
+	for _, result := range r.Results[:4] {
+		require.NoError(t, result.Err, result.Output)
+		assert.NotZero(t, result.Types, result.Output)
+
+		k8s, err := ioutil.ReadFile(filepath.Join(result.Output, "k8s.libsonnet"))
+		require.NoError(t, err)
+		k, err := ioutil.ReadFile(filepath.Join(result.Output, "k.libsonnet"))
+		require.NoError(t, err)
+
+		p, _, err := ksonnet.ParseProvenance(k8s)
+		require.NoError(t, err)
+		assert.Equal(t, result.Version, p.KubernetesVersion)
+		assert.NotEmpty(t, k)
+	}
+
+	assert.Equal(t, "1.8.0", r.Results[0].Version)
+	assert.Equal(t, "1.9.0", r.Results[1].Version)
+	assert.Equal(t, r.Results[0].Types, r.Results[2].Types)
+
+	// only the lib generated without version data for 1.9 warns about it.
+	assert.Contains(t, r.Results[1].Warnings, "there is no version data for Kubernetes 1.9.0")
+	assert.Empty(t, r.Results[3].Warnings)
+
+	failed := r.Failed()
+	require.Len(t, failed, 1)
+	assert.Equal(t, m.Libs[4].Output, failed[0].Output)
+
+	s := r.String()
+	assert.Contains(t, s, "generated 4 of 5 libs")
+	assert.Contains(t, s, "error: "+m.Libs[4].Output)
+
+	// the version data of the package isn't changed.
+	_, err = kubeversion.KSource("v1.9.0")
+	require.Error(t, err)
+}
+
+func TestRun_package_version_data(t *testing.T) {
+	dir, err := ioutil.TempDir("", "batch")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	require.NoError(t, kubeversion.LoadDir("testdata/versions"))
+	defer kubeversion.LoadEmbedded()
+
+	m := &Manifest{
+		Libs: []Target{
+			{Output: dir, Source: "testdata/swagger-1.9.json"},
+		},
+	}
+
+	r, err := Run(m)
+	require.NoError(t, err)
+	require.NoError(t, r.Results[0].Err)
+
+	// the lib uses the version data loaded by the caller, which is kept.
+	assert.Empty(t, r.Results[0].Warnings)
+	_, err = kubeversion.KSource("v1.9.0")
+	require.NoError(t, err)
+}
+
+func TestRun_generic(t *testing.T) {
+	dir, err := ioutil.TempDir("", "batch")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	m := &Manifest{
+		Libs: []Target{
+			{
+				Output:     dir,
+				Source:     "../ksonnet/testdata/generic.json",
+				Roots:      []string{"Pet"},
+				JSONSchema: true,
+				TypeScript: true,
+			},
+		},
+	}
+
+	r, err := Run(m)
+	require.NoError(t, err)
+	require.NoError(t, r.Results[0].Err)
+	assert.Equal(t, "2.1.0", r.Results[0].Version)
+	assert.Equal(t, 1, r.Results[0].Types)
+
+	_, err = os.Stat(filepath.Join(dir, "lib.libsonnet"))
+	require.NoError(t, err)
+	_, err = os.Stat(filepath.Join(dir, "k.libsonnet"))
+	require.True(t, os.IsNotExist(err))
+
+	for _, name := range []string{"lib.d.ts", "schemas/Pet.json", "schemas/Category.json"} {
+		_, err = os.Stat(filepath.Join(dir, name))
+		require.NoError(t, err, name)
+	}
+}
+
+func Test_loadVersionData(t *testing.T) {
+	targets := []Target{
+		{Output: "a"},
+		{Output: "b", VersionData: "testdata/versions"},
+		{Output: "c", VersionData: "testdata/versions"},
+	}
+
+	data, err := loadVersionData(targets)
+	require.NoError(t, err)
+	require.Len(t, data, 2)
+
+	_, err = data["testdata/versions"].KSource("v1.9.0")
+	require.NoError(t, err)
+	_, err = data[""].KSource("v1.9.0")
+	require.Error(t, err)
+
+	_, err = loadVersionData([]Target{{Output: "a", VersionData: "testdata/missing"}})
+	require.Error(t, err)
+}
+
+func Test_specCache(t *testing.T) {
+	c := newSpecCache()
+
+	a, checksum, err := c.load("testdata/swagger-1.9.json")
+	require.NoError(t, err)
+	b, _, err := c.load("testdata/swagger-1.9.json")
+	require.NoError(t, err)
+
+	assert.True(t, a == b, "expected the spec to be imported once")
+	assert.Len(t, checksum, 64)
+
+	_, _, err = c.load("testdata/missing.json")
+	require.Error(t, err)
+}
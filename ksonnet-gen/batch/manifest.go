@@ -0,0 +1,90 @@
+package batch
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Manifest lists the libs generated in a batch.
+type Manifest struct {
+	// Parallelism is the maximum number of libs generated at the same time.
+	// It defaults to the number of CPUs.
+	Parallelism int `json:"parallelism,omitempty"`
+	// Libs are the libs to generate.
+	Libs []Target `json:"libs"`
+}
+
+// Target is a lib generated in a batch.
+type Target struct {
+	// Output is the directory k8s.libsonnet and k.libsonnet are written to.
+	Output string `json:"output"`
+	// Source is the path or URL of the Kubernetes swagger spec.
+	Source string `json:"source"`
+	// VersionData is a directory of version data files which override the
+	// embedded ones.
+	VersionData string `json:"versionData,omitempty"`
+	// DeprecatedIdentifiers controls whether the identifiers of earlier
+	// ksonnet-lib releases are also generated. It defaults to true.
+	DeprecatedIdentifiers *bool `json:"deprecatedIdentifiers,omitempty"`
+	// NamePatterns describe the definition names outside the Kubernetes
+	// naming scheme. (see ksonnet.NamePattern)
+	NamePatterns []string `json:"namePatterns,omitempty"`
+	// Roots are the root definitions of a swagger API which isn't
+	// Kubernetes. (see ksonnet.CatalogOptGeneric) Its lib is written to
+	// lib.libsonnet.
+	Roots []string `json:"roots,omitempty"`
+	// JSONSchema controls whether a JSON Schema of each type and field is
+	// written to the schemas directory of the output directory.
+	JSONSchema bool `json:"jsonSchema,omitempty"`
+	// TypeScript controls whether TypeScript declarations of the types and
+	// fields are written to k8s.d.ts, or lib.d.ts for a generic API.
+	TypeScript bool `json:"typeScript,omitempty"`
+}
+
+// LoadManifest loads a JSON manifest. Relative paths in the manifest are
+// relative to the directory of the manifest.
+func LoadManifest(path string) (*Manifest, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "read manifest %s", path)
+	}
+
+	var m Manifest
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, errors.Wrapf(err, "parse manifest %s", path)
+	}
+
+	dir := filepath.Dir(path)
+	for i := range m.Libs {
+		t := &m.Libs[i]
+		if t.Output == "" || t.Source == "" {
+			return nil, errors.Errorf("lib %d of manifest %s needs an output and a source", i, path)
+		}
+
+		t.Output = resolve(dir, t.Output)
+		if !isURL(t.Source) {
+			t.Source = resolve(dir, t.Source)
+		}
+		if t.VersionData != "" {
+			t.VersionData = resolve(dir, t.VersionData)
+		}
+	}
+
+	return &m, nil
+}
+
+func resolve(dir, path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+
+	return filepath.Join(dir, path)
+}
+
+func isURL(source string) bool {
+	return strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://")
+}
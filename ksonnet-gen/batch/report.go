@@ -0,0 +1,79 @@
+package batch
+
+import (
+	"bytes"
+	"fmt"
+	"text/tabwriter"
+	"time"
+)
+
+// Result is the outcome of generating a lib.
+type Result struct {
+	Output string
+	Source string
+	// Version is the Kubernetes version of the lib.
+	Version string
+	// Types is the number of types in the lib.
+	Types int
+	// Warnings are the problems found while generating the lib.
+	Warnings []string
+	// Duration is the time it took to generate the lib, including the time
+	// waiting for its swagger spec to be imported.
+	Duration time.Duration
+	// Err is the error which stopped the lib being generated.
+	Err error
+}
+
+// Report is the outcome of a batch.
+type Report struct {
+	// Results are the results of the libs, in the order of the manifest.
+	Results []Result
+	// Duration is the time it took to generate all of the libs.
+	Duration time.Duration
+}
+
+// Failed returns the results of the libs which weren't generated.
+func (r *Report) Failed() []Result {
+	var failed []Result
+	for _, result := range r.Results {
+		if result.Err != nil {
+			failed = append(failed, result)
+		}
+	}
+
+	return failed
+}
+
+// String formats the report as a table of the libs, followed by their
+// warnings and errors.
+func (r *Report) String() string {
+	var buf bytes.Buffer
+
+	w := tabwriter.NewWriter(&buf, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "OUTPUT\tVERSION\tTYPES\tWARNINGS\tTIME")
+	for _, result := range r.Results {
+		version := result.Version
+		if result.Err != nil {
+			version = "failed"
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%s\n", result.Output, version, result.Types,
+			len(result.Warnings), result.Duration.Round(time.Millisecond))
+	}
+	w.Flush()
+
+	fmt.Fprintf(&buf, "generated %d of %d libs in %s\n", len(r.Results)-len(r.Failed()),
+		len(r.Results), r.Duration.Round(time.Millisecond))
+
+	for _, result := range r.Results {
+		for _, warning := range result.Warnings {
+			fmt.Fprintf(&buf, "warning: %s: %s\n", result.Output, warning)
+		}
+	}
+
+	for _, result := range r.Failed() {
+		fmt.Fprintf(&buf, "error: %s: %v\n", result.Output, result.Err)
+	}
+
+	return buf.String()
+}
@@ -0,0 +1,449 @@
+// Package detect determines the Kubernetes versions a bundle of rendered
+// manifests can be deployed to, by checking the kinds, apiVersions and fields
+// the manifests use against the catalogs of several swagger specs.
+package detect
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/blang/semver"
+	"github.com/go-openapi/spec"
+	"github.com/go-openapi/swag"
+	"github.com/ksonnet/ksonnet-lib/ksonnet-gen/ksonnet"
+	"github.com/ksonnet/ksonnet-lib/ksonnet-gen/kubespec"
+	"github.com/ksonnet/ksonnet-lib/ksonnet-gen/render"
+	"github.com/pkg/errors"
+)
+
+// Manifest is a Kubernetes object of a manifest bundle.
+type Manifest struct {
+	// Source is the path of the file the object is in, relative to the
+	// bundle, followed by the number of its document if the file has more
+	// than one. e.g. web.yaml#2
+	Source     string
+	APIVersion string
+	Kind       string
+	Name       string
+	Object     map[string]interface{}
+}
+
+// LoadDir loads the manifests in the .json, .yaml and .yml files of a
+// directory and its subdirectories, in the order of their paths. YAML files
+// can have several documents. Lists are flattened into their items.
+func LoadDir(dir string) ([]Manifest, error) {
+	var manifests []Manifest
+
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		switch filepath.Ext(path) {
+		case ".json", ".yaml", ".yml":
+		default:
+			return nil
+		}
+
+		if info.IsDir() {
+			return nil
+		}
+
+		source, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+
+		m, err := loadFile(path, filepath.ToSlash(source))
+		if err != nil {
+			return err
+		}
+
+		manifests = append(manifests, m...)
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "load manifests from %s", dir)
+	}
+
+	return manifests, nil
+}
+
+func loadFile(path, source string) ([]Manifest, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	docs := [][]byte{b}
+	if filepath.Ext(path) != ".json" {
+		docs = splitDocuments(b)
+	}
+
+	var manifests []Manifest
+	for i, doc := range docs {
+		docSource := source
+		if len(docs) > 1 {
+			docSource = fmt.Sprintf("%s#%d", source, i+1)
+		}
+
+		v, err := decode(doc, filepath.Ext(path) == ".json")
+		if err != nil {
+			return nil, errors.Wrapf(err, "parse %s", docSource)
+		}
+
+		objects, err := render.Flatten(v)
+		if err != nil {
+			return nil, errors.Wrapf(err, "read objects of %s", docSource)
+		}
+
+		for _, object := range objects {
+			m := Manifest{
+				Source:     docSource,
+				APIVersion: object["apiVersion"].(string),
+				Kind:       object["kind"].(string),
+				Object:     object,
+			}
+			if metadata, ok := object["metadata"].(map[string]interface{}); ok {
+				m.Name, _ = metadata["name"].(string)
+			}
+
+			manifests = append(manifests, m)
+		}
+	}
+
+	return manifests, nil
+}
+
+// splitDocuments splits a YAML file into its documents.
+func splitDocuments(b []byte) [][]byte {
+	var docs [][]byte
+	var doc []byte
+
+	for _, line := range bytes.SplitAfter(b, []byte("\n")) {
+		if bytes.Equal(bytes.TrimRight(line, " \t\r\n"), []byte("---")) {
+			docs = append(docs, doc)
+			doc = nil
+			continue
+		}
+
+		doc = append(doc, line...)
+	}
+
+	return append(docs, doc)
+}
+
+// decode decodes a JSON or YAML document. Empty documents are nil.
+func decode(doc []byte, isJSON bool) (interface{}, error) {
+	if !isJSON {
+		yamlDoc, err := swag.BytesToYAMLDoc(doc)
+		if err != nil {
+			return nil, err
+		}
+
+		if doc, err = swag.YAMLToJSON(yamlDoc); err != nil {
+			return nil, err
+		}
+	}
+
+	var v interface{}
+	if err := json.Unmarshal(doc, &v); err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+// Catalog is the catalog of a Kubernetes version which manifests are checked
+// against.
+type Catalog struct {
+	// Source is the path of the swagger spec of the catalog.
+	Source string
+
+	version     semver.Version
+	definitions spec.Definitions
+	// resources are the definitions of the types of the catalog, by their
+	// apiVersion and kind.
+	resources map[string]string
+}
+
+// LoadCatalog loads the catalog of a swagger spec.
+func LoadCatalog(path string) (*Catalog, error) {
+	apiSpec, _, err := kubespec.Import(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "import %s", path)
+	}
+
+	return NewCatalog(path, apiSpec)
+}
+
+// NewCatalog creates the catalog of a swagger spec loaded from source.
+func NewCatalog(source string, apiSpec *spec.Swagger) (*Catalog, error) {
+	c, err := ksonnet.NewCatalog(apiSpec)
+	if err != nil {
+		return nil, errors.Wrapf(err, "create catalog of %s", source)
+	}
+
+	version, err := semver.Parse(c.Version())
+	if err != nil {
+		return nil, errors.Wrapf(err, "parse version of %s", source)
+	}
+
+	types, err := c.Types()
+	if err != nil {
+		return nil, errors.Wrapf(err, "retrieve types of %s", source)
+	}
+
+	resources := make(map[string]string)
+	for _, ty := range types {
+		key := resourceKey(apiVersion(ty.QualifiedGroup(), ty.Version()), ty.Kind())
+		if _, ok := resources[key]; !ok {
+			resources[key] = ty.Identifier()
+		}
+	}
+
+	return &Catalog{
+		Source:      source,
+		version:     version,
+		definitions: apiSpec.Definitions,
+		resources:   resources,
+	}, nil
+}
+
+// Version returns the Kubernetes version of the catalog. e.g. 1.8.0
+func (c *Catalog) Version() string {
+	return c.version.String()
+}
+
+func apiVersion(group, version string) string {
+	if group == "" {
+		return version
+	}
+
+	return group + "/" + version
+}
+
+func resourceKey(apiVersion, kind string) string {
+	return apiVersion + "|" + kind
+}
+
+// Problem is something a manifest uses which a catalog doesn't contain.
+type Problem struct {
+	Source     string
+	APIVersion string
+	Kind       string
+	Name       string
+	// Field is the path of the field which isn't in the catalog. e.g.
+	// spec.template.spec.containers.resources. It is blank if the catalog
+	// doesn't contain the apiVersion and kind.
+	Field string
+}
+
+func (p Problem) String() string {
+	object := strings.TrimSpace(fmt.Sprintf("%s %s %s", p.APIVersion, p.Kind, p.Name))
+	if p.Field == "" {
+		return fmt.Sprintf("%s: %s is not in the version", p.Source, object)
+	}
+
+	return fmt.Sprintf("%s: %s uses %s, which is not in the version", p.Source, object, p.Field)
+}
+
+// Result is the result of checking manifests against a catalog.
+type Result struct {
+	Version  string
+	Source   string
+	Problems []Problem
+}
+
+// Compatible returns true if the catalog contains everything the manifests
+// use.
+func (r *Result) Compatible() bool {
+	return len(r.Problems) == 0
+}
+
+// Check checks that a catalog contains the apiVersion and kind of each
+// manifest, and every field it uses. Fields are checked against the
+// properties of the swagger definitions, following references, the items of
+// arrays and the values of maps. Fields of objects whose definition doesn't
+// describe their properties aren't checked.
+func (c *Catalog) Check(manifests []Manifest) Result {
+	r := Result{Version: c.Version(), Source: c.Source}
+
+	for _, m := range manifests {
+		problem := Problem{Source: m.Source, APIVersion: m.APIVersion, Kind: m.Kind, Name: m.Name}
+
+		id, ok := c.resources[resourceKey(m.APIVersion, m.Kind)]
+		if !ok {
+			r.Problems = append(r.Problems, problem)
+			continue
+		}
+
+		missing := make(map[string]bool)
+		c.missingFields("", c.definitions[id], m.Object, missing)
+
+		var fields []string
+		for field := range missing {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+
+		for _, field := range fields {
+			problem.Field = field
+			r.Problems = append(r.Problems, problem)
+		}
+	}
+
+	return r
+}
+
+// missingFields adds the paths of the fields of a value which aren't in its
+// schema to missing.
+func (c *Catalog) missingFields(path string, schema spec.Schema, value interface{}, missing map[string]bool) {
+	schema = c.resolve(schema)
+
+	switch t := value.(type) {
+	case map[string]interface{}:
+		if len(schema.Properties) > 0 {
+			for name, v := range t {
+				fieldPath := name
+				if path != "" {
+					fieldPath = path + "." + name
+				}
+
+				prop, ok := schema.Properties[name]
+				if !ok {
+					missing[fieldPath] = true
+					continue
+				}
+
+				c.missingFields(fieldPath, prop, v, missing)
+			}
+
+			return
+		}
+
+		if ap := schema.AdditionalProperties; ap != nil && ap.Schema != nil {
+			for _, v := range t {
+				c.missingFields(path, *ap.Schema, v, missing)
+			}
+		}
+	case []interface{}:
+		if schema.Items != nil && schema.Items.Schema != nil {
+			for _, item := range t {
+				c.missingFields(path, *schema.Items.Schema, item, missing)
+			}
+		}
+	}
+}
+
+// resolve follows the references of a schema to the definition they refer
+// to. References to definitions which aren't in the catalog aren't followed.
+func (c *Catalog) resolve(schema spec.Schema) spec.Schema {
+	seen := make(map[string]bool)
+	for {
+		ref := strings.TrimPrefix(schema.Ref.String(), "#/definitions/")
+		if ref == "" || seen[ref] {
+			return schema
+		}
+		seen[ref] = true
+
+		definition, ok := c.definitions[ref]
+		if !ok {
+			return schema
+		}
+
+		schema = definition
+	}
+}
+
+// Matrix is the compatibility of manifests with several Kubernetes
+// versions.
+type Matrix struct {
+	// Results are the results of the catalogs, sorted by their version.
+	Results []Result
+	// Min is the first version which is compatible with the manifests, and
+	// Max is the last compatible version before something the manifests use
+	// was removed. They are blank if no version is compatible.
+	Min string
+	Max string
+}
+
+// Detect checks manifests against catalogs, and finds the range of versions
+// the manifests can be deployed to.
+func Detect(catalogs []*Catalog, manifests []Manifest) *Matrix {
+	sorted := make([]*Catalog, len(catalogs))
+	copy(sorted, catalogs)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].version.LT(sorted[j].version)
+	})
+
+	m := &Matrix{}
+	for _, c := range sorted {
+		m.Results = append(m.Results, c.Check(manifests))
+	}
+
+	for _, r := range m.Results {
+		if !r.Compatible() {
+			if m.Min != "" {
+				break
+			}
+			continue
+		}
+
+		if m.Min == "" {
+			m.Min = r.Version
+		}
+		m.Max = r.Version
+	}
+
+	return m
+}
+
+// WriteMatrix writes a table of the versions of a matrix and whether they
+// are compatible, the range of compatible versions, and the problems of the
+// incompatible versions.
+func WriteMatrix(w io.Writer, m *Matrix) error {
+	var buf bytes.Buffer
+
+	tw := tabwriter.NewWriter(&buf, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "VERSION\tSOURCE\tCOMPATIBLE")
+	for _, r := range m.Results {
+		compatible := "yes"
+		if !r.Compatible() {
+			compatible = "no"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", r.Version, r.Source, compatible)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	buf.WriteString("\n")
+	if m.Min == "" {
+		buf.WriteString("No version is compatible.\n")
+	} else {
+		fmt.Fprintf(&buf, "MIN VERSION: %s\nMAX VERSION: %s\n", m.Min, m.Max)
+	}
+
+	for _, r := range m.Results {
+		if r.Compatible() {
+			continue
+		}
+
+		fmt.Fprintf(&buf, "\nPROBLEMS OF %s:\n", r.Version)
+		for _, p := range r.Problems {
+			fmt.Fprintf(&buf, "   %s\n", p)
+		}
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
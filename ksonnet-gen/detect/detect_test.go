@@ -0,0 +1,213 @@
+package detect
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/ksonnet/ksonnet-lib/ksonnet-gen/kubespec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const swagger18 = "../ksonnet/testdata/swagger-1.8.json"
+
+// versionCatalog returns the catalog of the 1.8 spec with its version
+// changed, and with properties removed from definitions.
+func versionCatalog(t *testing.T, version string, removed map[string][]string) *Catalog {
+	b, err := ioutil.ReadFile(swagger18)
+	require.NoError(t, err)
+
+	var doc map[string]interface{}
+	require.NoError(t, json.Unmarshal(b, &doc))
+
+	doc["info"].(map[string]interface{})["version"] = version
+
+	definitions := doc["definitions"].(map[string]interface{})
+	for name, props := range removed {
+		properties := definitions[name].(map[string]interface{})["properties"].(map[string]interface{})
+		for _, prop := range props {
+			delete(properties, prop)
+		}
+	}
+
+	b, err = json.Marshal(doc)
+	require.NoError(t, err)
+
+	apiSpec, err := kubespec.CreateAPISpec(b)
+	require.NoError(t, err)
+
+	c, err := NewCatalog("swagger-"+version+".json", apiSpec)
+	require.NoError(t, err)
+
+	return c
+}
+
+func TestLoadDir(t *testing.T) {
+	manifests, err := LoadDir("testdata/bundle")
+	require.NoError(t, err)
+
+	var got []Manifest
+	for _, m := range manifests {
+		assert.NotEmpty(t, m.Object)
+		m.Object = nil
+		got = append(got, m)
+	}
+
+	expected := []Manifest{
+		{Source: "app/web.yaml#1", APIVersion: "apps/v1beta2", Kind: "Deployment", Name: "web"},
+		{Source: "app/web.yaml#2", APIVersion: "v1", Kind: "Service", Name: "web"},
+		{Source: "config.json", APIVersion: "v1", Kind: "ConfigMap", Name: "web"},
+		{Source: "config.json", APIVersion: "rbac.authorization.k8s.io/v1", Kind: "Role", Name: "web"},
+	}
+	assert.Equal(t, expected, got)
+
+	_, err = LoadDir("testdata/invalid")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "value.yaml#2")
+
+	_, err = LoadDir("testdata/missing")
+	require.Error(t, err)
+}
+
+func Test_splitDocuments(t *testing.T) {
+	docs := splitDocuments([]byte("---\na: 1\n--- \nb: 2\n---\n"))
+
+	expected := [][]byte{nil, []byte("a: 1\n"), []byte("b: 2\n"), nil}
+	assert.Equal(t, expected, docs)
+}
+
+func TestCatalog_Check(t *testing.T) {
+	c, err := LoadCatalog(swagger18)
+	require.NoError(t, err)
+	require.Equal(t, "1.8.0", c.Version())
+
+	manifests, err := LoadDir("testdata/bundle")
+	require.NoError(t, err)
+
+	r := c.Check(manifests)
+	assert.Equal(t, "1.8.0", r.Version)
+	assert.Equal(t, swagger18, r.Source)
+	assert.True(t, r.Compatible(), "%v", r.Problems)
+
+	containers := []interface{}{
+		map[string]interface{}{"name": "web", "image": "nginx", "restartPolicy": "Always"},
+	}
+	unknown := []Manifest{
+		{
+			Source:     "web.yaml",
+			APIVersion: "apps/v1",
+			Kind:       "Deployment",
+			Name:       "web",
+			Object:     map[string]interface{}{},
+		},
+		{
+			Source:     "web.yaml",
+			APIVersion: "apps/v1beta2",
+			Kind:       "Deployment",
+			Name:       "web",
+			Object: map[string]interface{}{
+				"metadata": map[string]interface{}{"name": "web", "owner": "me"},
+				"spec": map[string]interface{}{
+					"template": map[string]interface{}{
+						"spec": map[string]interface{}{"containers": containers},
+					},
+				},
+			},
+		},
+	}
+
+	r = c.Check(unknown)
+	assert.False(t, r.Compatible())
+
+	expected := []Problem{
+		{Source: "web.yaml", APIVersion: "apps/v1", Kind: "Deployment", Name: "web"},
+		{Source: "web.yaml", APIVersion: "apps/v1beta2", Kind: "Deployment", Name: "web", Field: "metadata.owner"},
+		{Source: "web.yaml", APIVersion: "apps/v1beta2", Kind: "Deployment", Name: "web", Field: "spec.template.spec.containers.restartPolicy"},
+	}
+	assert.Equal(t, expected, r.Problems)
+}
+
+func TestDetect(t *testing.T) {
+	manifests, err := LoadDir("testdata/bundle")
+	require.NoError(t, err)
+
+	catalogs := []*Catalog{
+		// progressDeadlineSeconds is removed in 1.10, and the Role's rules
+		// don't have verbs in 1.7.
+		versionCatalog(t, "v1.10.0", map[string][]string{
+			"io.k8s.api.apps.v1beta2.DeploymentSpec": {"progressDeadlineSeconds"},
+		}),
+		versionCatalog(t, "v1.7.0", map[string][]string{
+			"io.k8s.api.rbac.v1.PolicyRule": {"verbs"},
+		}),
+		versionCatalog(t, "v1.9.0", nil),
+		versionCatalog(t, "v1.11.0", nil),
+		versionCatalog(t, "v1.8.0", nil),
+	}
+
+	m := Detect(catalogs, manifests)
+
+	var versions []string
+	var compatible []bool
+	for _, r := range m.Results {
+		versions = append(versions, r.Version)
+		compatible = append(compatible, r.Compatible())
+	}
+	assert.Equal(t, []string{"1.7.0", "1.8.0", "1.9.0", "1.10.0", "1.11.0"}, versions)
+	assert.Equal(t, []bool{false, true, true, false, true}, compatible)
+
+	// 1.11 is compatible again, but isn't in the range since 1.10 isn't.
+	assert.Equal(t, "1.8.0", m.Min)
+	assert.Equal(t, "1.9.0", m.Max)
+
+	expected := []Problem{
+		{Source: "config.json", APIVersion: "rbac.authorization.k8s.io/v1", Kind: "Role", Name: "web", Field: "rules.verbs"},
+	}
+	assert.Equal(t, expected, m.Results[0].Problems)
+
+	m = Detect(catalogs[:2], manifests)
+	assert.Empty(t, m.Min)
+	assert.Empty(t, m.Max)
+}
+
+func TestWriteMatrix(t *testing.T) {
+	m := &Matrix{
+		Results: []Result{
+			{
+				Version: "1.7.0",
+				Source:  "specs/swagger-1.7.json",
+				Problems: []Problem{
+					{Source: "web.yaml", APIVersion: "apps/v1beta2", Kind: "Deployment", Name: "web"},
+					{Source: "config.json", APIVersion: "v1", Kind: "ConfigMap", Field: "binaryData"},
+				},
+			},
+			{Version: "1.8.0", Source: "specs/swagger-1.8.json"},
+		},
+		Min: "1.8.0",
+		Max: "1.8.0",
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteMatrix(&buf, m))
+
+	expected := `VERSION  SOURCE                  COMPATIBLE
+1.7.0    specs/swagger-1.7.json  no
+1.8.0    specs/swagger-1.8.json  yes
+
+MIN VERSION: 1.8.0
+MAX VERSION: 1.8.0
+
+PROBLEMS OF 1.7.0:
+   web.yaml: apps/v1beta2 Deployment web is not in the version
+   config.json: v1 ConfigMap uses binaryData, which is not in the version
+`
+	assert.Equal(t, expected, buf.String())
+
+	m.Min, m.Max = "", ""
+	buf.Reset()
+	require.NoError(t, WriteMatrix(&buf, m))
+	assert.True(t, strings.Contains(buf.String(), "\nNo version is compatible.\n"))
+}
@@ -0,0 +1,293 @@
+// Package edit changes the values of object fields in jsonnet source. The
+// edits are spliced into the source, so the rest of the file, including its
+// comments and formatting, isn't changed.
+package edit
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"github.com/google/go-jsonnet/ast"
+	"github.com/google/go-jsonnet/parser"
+	nm "github.com/ksonnet/ksonnet-lib/ksonnet-gen/nodemaker"
+	"github.com/ksonnet/ksonnet-lib/ksonnet-gen/printer"
+	"github.com/pkg/errors"
+)
+
+// ParsePath splits a dotted path (e.g. components.web.replicas) into its
+// field names.
+func ParsePath(s string) []string {
+	return strings.Split(s, ".")
+}
+
+// ParseValue converts a command line value to a value for Set. JSON values
+// are decoded, and anything else is a string. JSON null is nil, which Set
+// sets as null.
+func ParseValue(s string) interface{} {
+	var v interface{}
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		return s
+	}
+
+	return v
+}
+
+// Set replaces the value of the object field at path. The field is located
+// through nested objects and local bindings.
+func Set(filename string, src []byte, path []string, value interface{}) ([]byte, error) {
+	f, err := locate(filename, src, path)
+	if err != nil {
+		return nil, err
+	}
+
+	noder, err := nm.ValueToNoder(value)
+	if err != nil {
+		return nil, errors.Wrapf(err, "convert value of %s", strings.Join(path, "."))
+	}
+
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, noder.Node()); err != nil {
+		return nil, errors.Wrapf(err, "print value of %s", strings.Join(path, "."))
+	}
+
+	loc := f.field.Expr2.Loc()
+	begin, end := offset(src, loc.Begin), offset(src, loc.End)
+
+	// indent the value like the line it is set on.
+	text := strings.TrimSuffix(buf.String(), "\n")
+	text = strings.Replace(text, "\n", "\n"+indentation(src, begin), -1)
+
+	return splice(src, begin, end, text), nil
+}
+
+// Delete deletes the object field at path. The field is located through
+// nested objects and local bindings. A field on lines of its own is deleted
+// with those lines, including its comments.
+func Delete(filename string, src []byte, path []string) ([]byte, error) {
+	f, err := locate(filename, src, path)
+	if err != nil {
+		return nil, err
+	}
+
+	end := offset(src, fieldEnd(f.field))
+
+	// sep is the end of the separator in front of the field.
+	sep := offset(src, f.object.Loc().Begin) + 1
+	if f.index > 0 {
+		sep = afterComma(src, offset(src, fieldEnd(f.object.Fields[f.index-1])))
+	}
+
+	if begin := lineEnd(src, sep); isNewline(src, begin) {
+		if last := lineEnd(src, afterComma(src, end)); isNewline(src, last) {
+			return splice(src, begin+1, last+1, ""), nil
+		}
+	}
+
+	// the field is deleted with the separator in front of it. The first field
+	// is deleted with the separator after it.
+	if f.index > 0 {
+		begin := offset(src, fieldEnd(f.object.Fields[f.index-1]))
+		return splice(src, begin, end, ""), nil
+	}
+
+	return splice(src, sep, afterComma(src, end), ""), nil
+}
+
+// located is an object field found by its path.
+type located struct {
+	object *ast.Object
+	field  ast.ObjectField
+	index  int
+}
+
+func locate(filename string, src []byte, path []string) (*located, error) {
+	tokens, err := parser.Lex(filename, string(src))
+	if err != nil {
+		return nil, errors.Wrapf(err, "lex %s", filename)
+	}
+
+	node, err := parser.Parse(tokens)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parse %s", filename)
+	}
+
+	f := find(node, path, scope{})
+	if f == nil {
+		return nil, errors.Errorf("%s doesn't have a field %s", filename, strings.Join(path, "."))
+	}
+
+	return f, nil
+}
+
+// scope is the local bindings visible to a node.
+type scope map[string]ast.Node
+
+func (s scope) with(name string, node ast.Node) scope {
+	out := scope{name: node}
+	for k, v := range s {
+		if k != name {
+			out[k] = v
+		}
+	}
+
+	return out
+}
+
+// find finds the field at path in node. Locals are followed to their body,
+// variables to the value they are bound to, indexes to the indexed field, and
+// `a + b` and `a { ... }` to b and then a, because the fields of b override
+// the fields of a.
+func find(node ast.Node, path []string, s scope) *located {
+	switch t := node.(type) {
+	case *ast.Local:
+		for _, bind := range t.Binds {
+			s = s.with(string(bind.Variable), bind.Body)
+		}
+		return find(t.Body, path, s)
+	case *ast.Var:
+		value, ok := s[string(t.Id)]
+		if !ok {
+			return nil
+		}
+		return find(value, path, s)
+	case *ast.Binary:
+		if t.Op != ast.BopPlus {
+			return nil
+		}
+		if f := find(t.Right, path, s); f != nil {
+			return f
+		}
+		return find(t.Left, path, s)
+	case *ast.ApplyBrace:
+		if f := find(t.Right, path, s); f != nil {
+			return f
+		}
+		return find(t.Left, path, s)
+	case *ast.Index:
+		name, ok := indexName(t)
+		if !ok {
+			return nil
+		}
+		return find(t.Target, append([]string{name}, path...), s)
+	case *ast.Object:
+		for _, field := range t.Fields {
+			if field.Kind == ast.ObjectLocal {
+				s = s.with(string(*field.Id), field.Expr2)
+			}
+		}
+
+		for i, field := range t.Fields {
+			name, ok := fieldName(field)
+			if !ok || name != path[0] {
+				continue
+			}
+
+			if len(path) == 1 {
+				return &located{object: t, field: field, index: i}
+			}
+
+			return find(field.Expr2, path[1:], s)
+		}
+	}
+
+	return nil
+}
+
+func fieldName(field ast.ObjectField) (string, bool) {
+	switch field.Kind {
+	case ast.ObjectFieldID:
+		return string(*field.Id), true
+	case ast.ObjectFieldStr:
+		if s, ok := field.Expr1.(*ast.LiteralString); ok {
+			return s.Value, true
+		}
+	}
+
+	return "", false
+}
+
+func indexName(index *ast.Index) (string, bool) {
+	if index.Id != nil {
+		return string(*index.Id), true
+	}
+
+	if s, ok := index.Index.(*ast.LiteralString); ok {
+		return s.Value, true
+	}
+
+	return "", false
+}
+
+// fieldEnd returns the end of a field, i.e. the end of its value, or the end
+// of its message if it is an assert.
+func fieldEnd(field ast.ObjectField) ast.Location {
+	if field.Expr3 != nil {
+		return field.Expr3.Loc().End
+	}
+
+	return field.Expr2.Loc().End
+}
+
+// offset converts a location to an offset in src. Lines and columns start
+// at 1.
+func offset(src []byte, loc ast.Location) int {
+	i := 0
+	for line := 1; line < loc.Line; line++ {
+		i += bytes.IndexByte(src[i:], '\n') + 1
+	}
+
+	return i + loc.Column - 1
+}
+
+// indentation returns the leading whitespace of the line containing offset i.
+func indentation(src []byte, i int) string {
+	start := bytes.LastIndexByte(src[:i], '\n') + 1
+	end := start
+	for end < len(src) && (src[end] == ' ' || src[end] == '\t') {
+		end++
+	}
+
+	return string(src[start:end])
+}
+
+// afterComma returns the offset after the comma following offset i, or i if
+// there isn't one.
+func afterComma(src []byte, i int) int {
+	j := i
+	for j < len(src) && strings.ContainsRune(" \t\r\n", rune(src[j])) {
+		j++
+	}
+
+	if j < len(src) && src[j] == ',' {
+		return j + 1
+	}
+
+	return i
+}
+
+// lineEnd skips the whitespace and line comment following offset i on its
+// line.
+func lineEnd(src []byte, i int) int {
+	for i < len(src) && (src[i] == ' ' || src[i] == '\t' || src[i] == '\r') {
+		i++
+	}
+
+	if bytes.HasPrefix(src[i:], []byte("//")) || bytes.HasPrefix(src[i:], []byte("#")) {
+		for i < len(src) && src[i] != '\n' {
+			i++
+		}
+	}
+
+	return i
+}
+
+func isNewline(src []byte, i int) bool {
+	return i < len(src) && src[i] == '\n'
+}
+
+func splice(src []byte, begin, end int, text string) []byte {
+	out := append([]byte{}, src[:begin]...)
+	out = append(out, text...)
+	return append(out, src[end:]...)
+}
@@ -0,0 +1,163 @@
+package edit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const source = `// Parameters of the components.
+local params = {
+  web: {
+    image: 'nginx:1.12', // the image
+    // the number of pods
+    replicas: 1,
+  },
+};
+
+{
+  components: {
+    web: params.web { port: 80 },
+    'db-server': params.web + { replicas: 2 },
+  },
+  local labels = { app: 'web' },
+  labels: labels,
+}
+`
+
+func TestSet(t *testing.T) {
+	cases := []struct {
+		name     string
+		path     string
+		value    interface{}
+		expected string
+		isErr    bool
+	}{
+		{
+			name:  "through a local",
+			path:  "components.web.replicas",
+			value: 3.0,
+			expected: `    // the number of pods
+    replicas: 3,`,
+		},
+		{
+			name:     "in a nested object",
+			path:     "components.web.port",
+			value:    "http",
+			expected: `    web: params.web { port: 'http' },`,
+		},
+		{
+			name:     "overriding field",
+			path:     "components.db-server.replicas",
+			value:    3.0,
+			expected: `    'db-server': params.web + { replicas: 3 },`,
+		},
+		{
+			name:     "through an object local",
+			path:     "labels.app",
+			value:    "db",
+			expected: `  local labels = { app: 'db' },`,
+		},
+		{
+			name:  "object",
+			path:  "components.web.image",
+			value: map[string]interface{}{"name": "nginx", "tag": "1.13"},
+			expected: `    image: {
+      name: 'nginx',
+      tag: '1.13',
+    }, // the image`,
+		},
+		{
+			name:     "null",
+			path:     "labels.app",
+			value:    nil,
+			expected: `  local labels = { app: null },`,
+		},
+		{
+			name:  "missing",
+			path:  "components.web.missing",
+			value: 1.0,
+			isErr: true,
+		},
+		{
+			name:  "invalid value",
+			path:  "components.web.replicas",
+			value: struct{}{},
+			isErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Set("source.jsonnet", []byte(source), ParsePath(tc.path), tc.value)
+			if tc.isErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Contains(t, string(got), tc.expected)
+			assert.Contains(t, string(got), "// Parameters of the components.")
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	cases := []struct {
+		name     string
+		path     string
+		expected string
+	}{
+		{
+			name: "last field",
+			path: "components.web.replicas",
+			expected: `  web: {
+    image: 'nginx:1.12', // the image
+  },`,
+		},
+		{
+			name: "first field",
+			path: "components.web.image",
+			expected: `  web: {
+    // the number of pods
+    replicas: 1,
+  },`,
+		},
+		{
+			name:     "only field",
+			path:     "components.web.port",
+			expected: `    web: params.web { },`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Delete("source.jsonnet", []byte(source), ParsePath(tc.path))
+			require.NoError(t, err)
+			assert.Contains(t, string(got), tc.expected)
+		})
+	}
+
+	got, err := Delete("inline.jsonnet", []byte("{ a: 1, b: { c: 2, d: 3 } }"), ParsePath("a"))
+	require.NoError(t, err)
+	assert.Equal(t, "{ b: { c: 2, d: 3 } }", string(got))
+
+	got, err = Delete("inline.jsonnet", []byte("{ a: 1, b: { c: 2, d: 3 } }"), ParsePath("b.d"))
+	require.NoError(t, err)
+	assert.Equal(t, "{ a: 1, b: { c: 2 } }", string(got))
+
+	_, err = Delete("source.jsonnet", []byte(source), ParsePath("missing"))
+	require.Error(t, err)
+
+	_, err = Delete("invalid.jsonnet", []byte("{"), ParsePath("missing"))
+	require.Error(t, err)
+}
+
+func TestParseValue(t *testing.T) {
+	assert.Equal(t, 3.0, ParseValue("3"))
+	assert.Equal(t, true, ParseValue("true"))
+	assert.Equal(t, "nginx:1.13", ParseValue("nginx:1.13"))
+	assert.Nil(t, ParseValue("null"))
+	assert.Equal(t, map[string]interface{}{"a": "b"}, ParseValue(`{"a": "b"}`))
+}
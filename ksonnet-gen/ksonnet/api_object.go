@@ -9,6 +9,10 @@ import (
 type APIObject struct {
 	resource       Object
 	renderFieldsFn renderFieldsFn
+	// lookup looks up the definitions the fields of the object refer to. If
+	// it is nil, they are looked up in the Catalog the object is rendered
+	// with.
+	lookup typeLookup
 }
 
 // NewAPIObject creates an instance of APIObject.
@@ -39,7 +43,8 @@ func (a *APIObject) Node(catalog *Catalog) (*nm.Object, error) {
 func (a *APIObject) initNode(catalog *Catalog) (*nm.Object, error) {
 	o := nm.NewObject()
 
-	if a.resource.IsType() {
+	// the types of a generic API don't have an apiVersion or kind.
+	if a.resource.IsType() && !catalog.generic {
 		kindObject := nm.OnelineObject()
 		kind := a.resource.Kind()
 		kindObject.Set(nm.InheritedKey("kind"), nm.NewStringDouble(kind))
@@ -50,17 +55,48 @@ func (a *APIObject) initNode(catalog *Catalog) (*nm.Object, error) {
 			nm.NewVar("kind"),
 		}
 
-		a.setConstructors(o, ctorBase, objectConstructor())
+		if err := a.setConstructors(catalog, o, ctorBase, objectConstructor()); err != nil {
+			return nil, err
+		}
 	} else {
-		a.setConstructors(o, nil, nm.OnelineObject())
+		if err := a.setConstructors(catalog, o, nil, nm.OnelineObject()); err != nil {
+			return nil, err
+		}
+	}
+
+	if a.resource.IsType() {
+		o.Set(nm.FunctionKey("overlay", []string{"patch"}, nm.KeyOptComment(overlayComment)),
+			overlayFunction(a.resource))
 	}
 
 	return o, nil
 }
 
-func (a *APIObject) setConstructors(parent *nm.Object, ctorBase []nm.Noder, defaultCtorBody nm.Noder) error {
-	desc := makeDescriptor(a.resource.Codebase(), a.resource.Group(), a.resource.Kind())
-	ctors := locateConstructors(desc)
+// overlayComment is the comment of the overlay function of a type.
+const overlayComment = "Overlays a patch on the object with strategic merge semantics, like " +
+	"`kubectl apply`. Lists of objects are merged by their merge key (e.g. the name of a " +
+	"container) instead of being appended, items with `$patch: 'delete'` are removed from " +
+	"them, and fields which are null in the patch are removed."
+
+// setConstructors sets the constructors of the object. Constructors in the
+// version data of the Catalog's Kubernetes version take precedence over the
+// built in custom constructors. Objects of generic APIs only have the default
+// constructor.
+func (a *APIObject) setConstructors(catalog *Catalog, parent *nm.Object, ctorBase []nm.Noder, defaultCtorBody nm.Noder) error {
+	if catalog.generic {
+		parent.Set(nm.FunctionKey("new", []string{}), defaultCtorBody)
+		return nil
+	}
+
+	ctors, ok, err := versionConstructors(catalog, a.resource.Identifier())
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		desc := makeDescriptor(a.resource.Codebase(), a.resource.Group(), a.resource.Kind())
+		ctors = locateConstructors(desc)
+	}
 
 	if len(ctors) > 0 {
 		for _, ctor := range ctors {
@@ -92,7 +128,12 @@ func apiObjectNode(catalog *Catalog, a *APIObject) (*nm.Object, error) {
 	if err != nil {
 		return nil, err
 	}
-	if err := a.renderFieldsFn(catalog, o, "", a.resource.Properties()); err != nil {
+	var tl typeLookup = catalog
+	if a.lookup != nil {
+		tl = a.lookup
+	}
+
+	if err := a.renderFieldsFn(tl, o, "", a.resource.Identifier(), a.resource.Properties()); err != nil {
 		return nil, err
 	}
 	return o, nil
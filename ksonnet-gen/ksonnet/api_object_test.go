@@ -77,7 +77,7 @@ func TestAPIObject_Node_fails_when_renderer_fails(t *testing.T) {
 	o1 := NewType("alpha", "desc", "codebase", "group", c1, nil)
 	ao := NewAPIObject(&o1)
 
-	ao.renderFieldsFn = func(typeLookup, *nm.Object, string, map[string]Property) error {
+	ao.renderFieldsFn = func(typeLookup, *nm.Object, string, string, map[string]Property) error {
 		return errors.New("failed")
 	}
 
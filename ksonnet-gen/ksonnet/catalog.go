@@ -1,10 +1,14 @@
 package ksonnet
 
 import (
+	"fmt"
+	"sort"
 	"strings"
+	"sync"
 
 	"github.com/blang/semver"
 	"github.com/go-openapi/spec"
+	"github.com/ksonnet/ksonnet-lib/ksonnet-gen/kubeversion"
 	"github.com/pkg/errors"
 )
 
@@ -42,17 +46,111 @@ func CatalogOptChecksum(checksum string) CatalogOpt {
 	}
 }
 
-// Catalog is a catalog definitions
+// CatalogOptDeprecatedIdentifiers is a Catalog option for rendering the
+// identifiers generated by the legacy emitter as deprecated aliases, when
+// they are spelled differently. (e.g. `withExternalIPs` for `withExternalIps`)
+func CatalogOptDeprecatedIdentifiers(deprecated bool) CatalogOpt {
+	return func(c *Catalog) {
+		c.deprecatedIdentifiers = deprecated
+	}
+}
+
+// CatalogOptFailOnCollisions is a Catalog option for failing to generate a
+// lib which has collisions, i.e. keys generated for more than one property or
+// kind, instead of resolving them.
+func CatalogOptFailOnCollisions(fail bool) CatalogOpt {
+	return func(c *Catalog) {
+		c.failOnCollisions = fail
+	}
+}
+
+// CatalogOptNamePatterns is a Catalog option for describing definition names
+// with name patterns. The patterns are tried in order, before the Kubernetes
+// naming scheme.
+func CatalogOptNamePatterns(patterns ...*NamePattern) CatalogOpt {
+	return func(c *Catalog) {
+		c.namePatterns = append(c.namePatterns, patterns...)
+	}
+}
+
+// CatalogOptJSONSchemas is a Catalog option for also generating a JSON Schema
+// of each type and field with the lib.
+func CatalogOptJSONSchemas(enabled bool) CatalogOpt {
+	return func(c *Catalog) {
+		c.jsonSchemas = enabled
+	}
+}
+
+// CatalogOptTypeScript is a Catalog option for also generating TypeScript
+// declarations of the types and fields with the lib.
+func CatalogOptTypeScript(enabled bool) CatalogOpt {
+	return func(c *Catalog) {
+		c.typeScript = enabled
+	}
+}
+
+// CatalogOptVersionData is a Catalog option for setting the version data the
+// lib of a Kubernetes API is generated with. It defaults to the data of the
+// kubeversion package when the Catalog is created.
+func CatalogOptVersionData(d *kubeversion.Data) CatalogOpt {
+	return func(c *Catalog) {
+		c.versionData = d
+	}
+}
+
+// Catalog is a catalog definitions. It is safe for concurrent use: its types,
+// fields and the other memos are extracted once, and only read afterwards.
 type Catalog struct {
 	apiSpec    *spec.Swagger
 	extractFn  ExtractFn
 	apiVersion semver.Version
+	version    string
 	paths      map[string]Component
 	checksum   string
-
-	// memos
-	typesCache  []Type
-	fieldsCache []Field
+	warnings   []string
+
+	versionData           *kubeversion.Data
+	identifierAliases     map[string]string
+	deprecatedIdentifiers bool
+
+	failOnCollisions bool
+	jsonSchemas      bool
+	typeScript       bool
+	collisionsMu     sync.Mutex
+	collisions       []Collision
+
+	namePatterns []*NamePattern
+	naming       Naming
+	customNaming bool
+
+	// a generic API isn't Kubernetes. Its types are the roots.
+	generic bool
+	roots   []string
+	// packages are the components of the definitions in a package, which
+	// describe the definitions of the package without a component.
+	packages map[string]Component
+
+	// memos, which are filled once by the first caller which needs them.
+	typesOnce        sync.Once
+	typesCache       []Type
+	typesErr         error
+	fieldsOnce       sync.Once
+	fieldsCache      []Field
+	fieldsErr        error
+	recursiveOnce    sync.Once
+	recursiveCache   map[string]bool
+	descendantsOnce  sync.Once
+	descendantsCache map[string]map[string]bool
+	descendantsErr   error
+	// the properties of a definition are extracted by whichever of the
+	// types and fields needs them first.
+	propertiesMu    sync.Mutex
+	propertiesCache map[string]map[string]Property
+
+	// indexes of the memoized types and fields
+	typesByID  map[string]int
+	typesByGVK map[string]int
+	fieldsByID map[string]int
 }
 
 // NewCatalog creates an instance of Catalog.
@@ -65,31 +163,82 @@ func NewCatalog(apiSpec *spec.Swagger, opts ...CatalogOpt) (*Catalog, error) {
 		return nil, errors.New("apiSpec Info is nil")
 	}
 
-	parts := strings.SplitN(apiSpec.Info.Version, ".", 3)
+	c := &Catalog{
+		apiSpec:   apiSpec,
+		extractFn: extractProperties,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	init := c.initKubernetes
+	if c.generic {
+		init = c.initGeneric
+	}
+	if err := init(); err != nil {
+		return nil, err
+	}
+
+	packages, err := c.packageComponents()
+	if err != nil {
+		return nil, errors.Wrap(err, "extract package components")
+	}
+	c.packages = packages
+
+	return c, nil
+}
+
+// initKubernetes initializes a Catalog of a Kubernetes API.
+func (c *Catalog) initKubernetes() error {
+	parts := strings.SplitN(c.apiSpec.Info.Version, ".", 3)
 	parts[0] = strings.TrimPrefix(parts[0], "v")
 	vers := strings.Join(parts, ".")
 	apiVersion, err := semver.Parse(vers)
 	if err != nil {
-		return nil, errors.Wrap(err, "invalid apiSpec version")
+		return errors.Wrap(err, "invalid apiSpec version")
 	}
 
-	paths, err := parsePaths(apiSpec)
+	paths, ambiguous, err := parsePaths(c.apiSpec)
 	if err != nil {
-		return nil, errors.Wrap(err, "parse apiSpec paths")
+		return errors.Wrap(err, "parse apiSpec paths")
 	}
 
-	c := &Catalog{
-		apiSpec:    apiSpec,
-		extractFn:  extractProperties,
-		apiVersion: apiVersion,
-		paths:      paths,
+	for _, ref := range ambiguous {
+		c.warnings = append(c.warnings,
+			fmt.Sprintf("%s is the body of requests for different kinds, so it isn't a component", ref))
 	}
 
-	for _, opt := range opts {
-		opt(c)
+	if c.versionData == nil {
+		d, err := kubeversion.Current()
+		if err != nil {
+			return err
+		}
+		c.versionData = d
 	}
 
-	return c, nil
+	if _, err := c.versionData.KSource(apiVersion.String()); err != nil {
+		if _, isUnknown := err.(*kubeversion.UnknownVersionError); !isUnknown {
+			return errors.Wrapf(err, "load version data for %s", apiVersion)
+		}
+		c.warnings = append(c.warnings,
+			fmt.Sprintf("there is no version data for Kubernetes %s", apiVersion))
+	}
+
+	identifierAliases, err := versionIdentifierAliases(c.versionData, apiVersion.String())
+	if err != nil {
+		return err
+	}
+
+	c.apiVersion = apiVersion
+	c.version = apiVersion.String()
+	c.paths = paths
+	c.identifierAliases = identifierAliases
+	if c.naming == nil {
+		c.naming = ParseDescription
+	}
+
+	return nil
 }
 
 // Checksum returns the checksum of the swagger schema.
@@ -97,35 +246,97 @@ func (c *Catalog) Checksum() string {
 	return c.checksum
 }
 
+// Warnings returns the problems found in the swagger schema which don't stop
+// a lib being generated from it.
+func (c *Catalog) Warnings() []string {
+	c.collisionsMu.Lock()
+	defer c.collisionsMu.Unlock()
+
+	warnings := append([]string{}, c.warnings...)
+	for _, collision := range c.collisions {
+		warnings = append(warnings, collision.String())
+	}
+
+	return warnings
+}
+
+// Collisions returns the collisions found while rendering the lib.
+func (c *Catalog) Collisions() []Collision {
+	c.collisionsMu.Lock()
+	defer c.collisionsMu.Unlock()
+
+	return append([]Collision(nil), c.collisions...)
+}
+
+// recordCollision records a collision found while rendering the lib. A
+// collision is recorded once, even if it is found again.
+func (c *Catalog) recordCollision(collision Collision) {
+	c.collisionsMu.Lock()
+	defer c.collisionsMu.Unlock()
+
+	for _, recorded := range c.collisions {
+		if recorded.String() == collision.String() {
+			return
+		}
+	}
+
+	c.collisions = append(c.collisions, collision)
+}
+
+// collisionError returns a CollisionError if the Catalog fails on
+// collisions and there are any.
+func (c *Catalog) collisionError() error {
+	collisions := c.Collisions()
+	if !c.failOnCollisions || len(collisions) == 0 {
+		return nil
+	}
+
+	return &CollisionError{Collisions: collisions}
+}
+
 // Version returns the Kubernetes API version represented by this Catalog.
+// The version of a generic API is the version of its spec.
 func (c *Catalog) Version() string {
-	return c.apiVersion.String()
+	return c.version
 }
 
-// Types returns a slice of all types.
+// Types returns a slice of all types. The types are extracted once, and the
+// slice is shared by the callers, so it must not be modified.
 func (c *Catalog) Types() ([]Type, error) {
-	if c.typesCache != nil {
-		return c.typesCache, nil
-	}
+	c.typesOnce.Do(func() {
+		c.typesCache, c.typesErr = c.extractTypes()
+	})
+
+	return c.typesCache, c.typesErr
+}
 
+// extractTypes extracts the types of the Catalog, and indexes them.
+func (c *Catalog) extractTypes() ([]Type, error) {
 	var resources []Type
 
 	for name, schema := range c.definitions() {
-		desc, err := ParseDescription(name)
+		desc, err := c.describe(name, schema)
 		if err != nil {
 			return nil, errors.Wrapf(err, "parse description for %s", name)
 		}
 
 		// If there is a path, we can update it as a first class object
 		// in the API. This makes this schema a type.
-		component, ok := c.paths[name]
+		component, ok, err := c.component(name, schema)
+		if err != nil {
+			return nil, errors.Wrapf(err, "extract component for %s", name)
+		}
+		if c.generic {
+			component = Component{Group: desc.QualifiedGroup, Version: desc.Version, Kind: desc.Kind}
+			ok = c.isRoot(name)
+		}
 		if !ok {
 			continue
 		}
 
-		props, err := c.extractFn(c, schema.Properties, schema.Required)
+		props, err := c.properties(name, schema)
 		if err != nil {
-			return nil, errors.Wrapf(err, "extract propererties from %s", name)
+			return nil, err
 		}
 
 		kind := NewType(name, schema.Description, desc.Codebase, desc.Group, component, props)
@@ -133,70 +344,256 @@ func (c *Catalog) Types() ([]Type, error) {
 		resources = append(resources, kind)
 	}
 
-	c.typesCache = resources
+	c.typesByID = make(map[string]int)
+	c.typesByGVK = make(map[string]int)
+	for i := range resources {
+		ty := &resources[i]
+		indexFirst(c.typesByID, ty.Identifier(), i)
+		indexFirst(c.typesByGVK, gvkKey(ty.Group(), ty.Version(), ty.Kind()), i)
+	}
 
 	return resources, nil
 }
 
-// Fields returns a slice of all fields.
+// Fields returns a slice of all fields. The fields are extracted once, and
+// the slice is shared by the callers, so it must not be modified.
 func (c *Catalog) Fields() ([]Field, error) {
-	if c.fieldsCache != nil {
-		return c.fieldsCache, nil
-	}
+	c.fieldsOnce.Do(func() {
+		c.fieldsCache, c.fieldsErr = c.extractFields()
+	})
+
+	return c.fieldsCache, c.fieldsErr
+}
 
+// extractFields extracts the fields of the Catalog, and indexes them.
+func (c *Catalog) extractFields() ([]Field, error) {
 	var types []Field
 
 	for name, schema := range c.definitions() {
-		desc, err := ParseDescription(name)
+		desc, err := c.describe(name, schema)
 		if err != nil {
 			return nil, errors.Wrapf(err, "parse description for %s", name)
 		}
 
 		// If there is a path, this should not be a hidden object. This
 		// makes this schema a field.
-		if _, ok := c.paths[name]; ok {
+		if _, ok := c.paths[name]; ok || c.isRoot(name) {
 			continue
 		}
 
-		props, err := c.extractFn(c, schema.Properties, schema.Required)
+		props, err := c.properties(name, schema)
 		if err != nil {
-			return nil, errors.Wrapf(err, "extract propererties from %s", name)
+			return nil, err
 		}
+
 		t := NewField(name, schema.Description, desc.Codebase, desc.Group, desc.Version, desc.Kind, props)
+		t.qualifiedGroup = desc.QualifiedGroup
 		types = append(types, *t)
 	}
 
-	c.fieldsCache = types
+	c.fieldsByID = make(map[string]int)
+	for i := range types {
+		indexFirst(c.fieldsByID, types[i].Identifier(), i)
+	}
+
 	return types, nil
 }
 
+// properties returns the properties of a definition. They are extracted once,
+// so a definition which is both a type and a field (e.g. a list) shares them.
+func (c *Catalog) properties(name string, schema spec.Schema) (map[string]Property, error) {
+	c.propertiesMu.Lock()
+	defer c.propertiesMu.Unlock()
+
+	if props, ok := c.propertiesCache[name]; ok {
+		return props, nil
+	}
+
+	props, err := c.extractFn(c, schema.Properties, schema.Required)
+	if err != nil {
+		return nil, errors.Wrapf(err, "extract propererties from %s", name)
+	}
+	c.identifyProperties(props)
+
+	if c.propertiesCache == nil {
+		c.propertiesCache = make(map[string]map[string]Property)
+	}
+	c.propertiesCache[name] = props
+
+	return props, nil
+}
+
+// indexFirst indexes the position of the first item with a key.
+func indexFirst(index map[string]int, key string, i int) {
+	if _, ok := index[key]; !ok {
+		index[key] = i
+	}
+}
+
+// gvkKey is the key of a group, version and kind in an index.
+func gvkKey(group, version, kind string) string {
+	return strings.Join([]string{group, version, kind}, "/")
+}
+
+// component returns the component of a type. Besides the definitions which
+// have a path, definitions with a group/version/kind extension are types, even
+// though they can't be updated. (e.g. lists)
+func (c *Catalog) component(name string, schema spec.Schema) (Component, bool, error) {
+	if component, ok := c.paths[name]; ok {
+		return component, true, nil
+	}
+
+	if _, ok := schema.Extensions[extensionGroupVersionKind]; !ok {
+		return Component{}, false, nil
+	}
+
+	component, err := NewComponent(schema)
+	if err != nil {
+		return Component{}, false, err
+	}
+
+	return *component, true, nil
+}
+
+// describe describes a definition name with the name patterns of the
+// Catalog, and then with its naming strategy. Names which neither describes
+// are described by the component of the definition, or by the component of
+// the other definitions in its package.
+func (c *Catalog) describe(name string, schema spec.Schema) (*Description, error) {
+	for _, p := range c.namePatterns {
+		if d := p.Describe(name); d != nil {
+			return d, nil
+		}
+	}
+
+	d, err := c.naming(name)
+	if err == nil {
+		return d, nil
+	}
+	if _, isUnknown := err.(*UnknownDefinitionError); !isUnknown {
+		return nil, err
+	}
+
+	component, ok, cErr := c.component(name, schema)
+	if cErr != nil {
+		return nil, errors.Wrapf(cErr, "extract component for %s", name)
+	}
+
+	kind := name[strings.LastIndex(name, ".")+1:]
+	if ok {
+		kind = component.Kind
+	} else if component, ok = c.packages[packageName(name)]; !ok {
+		return nil, err
+	}
+
+	return &Description{
+		Name:           name,
+		Version:        component.Version,
+		Kind:           kind,
+		Group:          GroupName(component.Group),
+		Codebase:       "api",
+		QualifiedGroup: component.Group,
+	}, nil
+}
+
+// describeRef describes the definition a property refers to.
+func (c *Catalog) describeRef(name string) (*Description, error) {
+	schema, ok := c.apiSpec.Definitions[name]
+	if !ok {
+		return nil, errors.Errorf("%s was not found", name)
+	}
+
+	return c.describe(name, schema)
+}
+
+// packageComponents returns the components of the packages of the
+// definitions. The first definition with a component, by name, sets the
+// component of its package.
+func (c *Catalog) packageComponents() (map[string]Component, error) {
+	var names []string
+	for name := range c.apiSpec.Definitions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	packages := make(map[string]Component)
+	for _, name := range names {
+		pkg := packageName(name)
+		if _, ok := packages[pkg]; ok {
+			continue
+		}
+
+		component, ok, err := c.component(name, c.apiSpec.Definitions[name])
+		if err != nil {
+			return nil, errors.Wrapf(err, "extract component for %s", name)
+		}
+		if ok && component.Version != "" {
+			packages[pkg] = component
+		}
+	}
+
+	return packages, nil
+}
+
+// packageName returns the package of a definition name, i.e. the name
+// without its kind.
+func packageName(name string) string {
+	i := strings.LastIndex(name, ".")
+	if i == -1 {
+		return ""
+	}
+
+	return name[:i]
+}
+
 func (c *Catalog) isFormatRef(name string) (bool, error) {
+	format, err := c.refFormat(name)
+	if err != nil {
+		return false, err
+	}
+
+	return format != "", nil
+}
+
+// refFormat returns the format of a definition. Definitions without a declared
+// format are looked up in formatDefinitions.
+func (c *Catalog) refFormat(name string) (string, error) {
 	schema, ok := c.apiSpec.Definitions[name]
 	if !ok {
-		return false, errors.Errorf("%s was not found", name)
+		return "", errors.Errorf("%s was not found", name)
 	}
 
 	if schema.Format != "" {
-		return true, nil
+		return schema.Format, nil
 	}
 
-	return false, nil
+	return formatDefinitions[name], nil
+}
+
+// isRecursiveRef returns true if the definition can reach itself by following
+// references.
+func (c *Catalog) isRecursiveRef(name string) bool {
+	c.recursiveOnce.Do(func() {
+		c.recursiveCache = findRecursiveDefinitions(c.apiSpec.Definitions)
+	})
+
+	return c.recursiveCache[name]
 }
 
 // Field returns a field by definition id. If the type cannot be found, it returns an error.
 func (c *Catalog) Field(name string) (*Field, error) {
-	types, err := c.Fields()
+	fields, err := c.Fields()
 	if err != nil {
 		return nil, err
 	}
 
-	for _, ty := range types {
-		if ty.Identifier() == name {
-			return &ty, nil
-		}
+	i, ok := c.fieldsByID[name]
+	if !ok {
+		return nil, errors.Errorf("%s was not found", name)
 	}
 
-	return nil, errors.Errorf("%s was not found", name)
+	f := fields[i]
+	return &f, nil
 }
 
 // Resource returns a resource by group, version, kind. If the field cannot be found,
@@ -207,16 +604,14 @@ func (c *Catalog) Resource(group, version, kind string) (*Type, error) {
 		return nil, err
 	}
 
-	for _, resource := range resources {
-		if group == resource.Group() &&
-			version == resource.Version() &&
-			kind == resource.Kind() {
-			return &resource, nil
-		}
+	i, ok := c.typesByGVK[gvkKey(group, version, kind)]
+	if !ok {
+		return nil, errors.Errorf("unable to find %s.%s.%s",
+			group, version, kind)
 	}
 
-	return nil, errors.Errorf("unable to find %s.%s.%s",
-		group, version, kind)
+	resource := resources[i]
+	return &resource, nil
 }
 
 // TypeByID returns a type by identifier.
@@ -226,13 +621,13 @@ func (c *Catalog) TypeByID(id string) (*Type, error) {
 		return nil, err
 	}
 
-	for _, resource := range resources {
-		if resource.Identifier() == id {
-			return &resource, nil
-		}
+	i, ok := c.typesByID[id]
+	if !ok {
+		return nil, errors.Errorf("unable to find type %q", id)
 	}
 
-	return nil, errors.Errorf("unable to find type %q", id)
+	resource := resources[i]
+	return &resource, nil
 }
 
 // TypesWithDescendant returns types who have the specified definition as a descendant.
@@ -243,23 +638,72 @@ func (c *Catalog) TypesWithDescendant(definition string) ([]Type, error) {
 		return nil, errors.Wrap(err, "retrieve types")
 	}
 
+	descendants, err := c.descendants()
+	if err != nil {
+		return nil, err
+	}
+
 	var out []Type
 	for _, ty := range types {
-
-		if strings.HasSuffix(ty.Kind(), "List") {
-			continue
+		if descendants[ty.Identifier()][definition] {
+			out = append(out, ty)
 		}
-		tf, err := c.descend(definition, ty.Properties())
-		if err != nil {
-			return nil, err
+	}
+
+	return out, nil
+}
+
+// DescendantPath returns the property path from a type to its nearest descendant
+// with the specified definition. e.g. `spec.template.spec` for the PodSpec of a
+// Deployment. Only object references are followed, because descendants in arrays
+// or maps can't be reached with a path. It returns nil if there is no such descendant.
+func (c *Catalog) DescendantPath(ty Type, definition string) ([]string, error) {
+	type node struct {
+		path  []string
+		props map[string]Property
+	}
+
+	seen := make(map[string]bool)
+	queue := []node{{props: ty.Properties()}}
+
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+
+		var names []string
+		for name := range cur.props {
+			names = append(names, name)
 		}
+		sort.Strings(names)
 
-		if tf {
-			out = append(out, ty)
+		for _, name := range names {
+			rf, ok := cur.props[name].(*ReferenceField)
+			if !ok {
+				continue
+			}
+
+			path := append(append([]string{}, cur.path...), name)
+
+			ref := rf.Ref()
+			if ref == definition {
+				return path, nil
+			}
+
+			if seen[ref] {
+				continue
+			}
+			seen[ref] = true
+
+			f, err := c.find(ref)
+			if err != nil {
+				return nil, errors.Wrapf(err, "find field %s", ref)
+			}
+
+			queue = append(queue, node{path: path, props: f.Properties()})
 		}
 	}
 
-	return out, nil
+	return nil, nil
 }
 
 func (c *Catalog) find(id string) (Object, error) {
@@ -276,37 +720,62 @@ func (c *Catalog) find(id string) (Object, error) {
 	return t, nil
 }
 
-func (c *Catalog) descend(definition string, m map[string]Property) (bool, error) {
+// descendants returns the definitions reachable from the properties of the
+// types by following references, by the identifiers of the types. List types
+// don't have descendants. They are found once, since every helper of the
+// extension looks for the types with a descendant.
+func (c *Catalog) descendants() (map[string]map[string]bool, error) {
+	c.descendantsOnce.Do(func() {
+		c.descendantsCache, c.descendantsErr = c.findDescendants()
+	})
 
-	for _, prop := range m {
-		if ref := prop.Ref(); ref != "" {
+	return c.descendantsCache, c.descendantsErr
+}
 
-			if ref == definition {
-				return true, nil
-			}
+func (c *Catalog) findDescendants() (map[string]map[string]bool, error) {
+	types, err := c.Types()
+	if err != nil {
+		return nil, errors.Wrap(err, "retrieve types")
+	}
 
-			// NOTE: if this is a reference to json schema, bail out because this is recursive.
-			if ref == "io.k8s.apiextensions-apiserver.pkg.apis.apiextensions.v1beta1.JSONSchemaProps" {
-				continue
-			}
+	out := make(map[string]map[string]bool)
+	for _, ty := range types {
+		if strings.HasSuffix(ty.Kind(), "List") {
+			continue
+		}
 
-			f, err := c.find(ref)
-			if err != nil {
-				return false, errors.Wrapf(err, "find field %s", ref)
-			}
+		descendants := make(map[string]bool)
+		if err := c.descend(ty.Properties(), descendants); err != nil {
+			return nil, err
+		}
+		out[ty.Identifier()] = descendants
+	}
 
-			tf, err := c.descend(definition, f.Properties())
-			if err != nil {
-				return false, err
-			}
+	return out, nil
+}
 
-			if tf {
-				return true, nil
-			}
+// descend adds the definitions reachable from the properties to seen.
+// Definitions which have already been seen are only walked once, so
+// recursive schemas terminate.
+func (c *Catalog) descend(m map[string]Property, seen map[string]bool) error {
+	for _, prop := range m {
+		ref := prop.Ref()
+		if ref == "" || seen[ref] {
+			continue
+		}
+		seen[ref] = true
+
+		f, err := c.find(ref)
+		if err != nil {
+			return errors.Wrapf(err, "find field %s", ref)
+		}
+
+		if err := c.descend(f.Properties(), seen); err != nil {
+			return err
 		}
 	}
 
-	return false, nil
+	return nil
 }
 
 func isValidDefinition(name string, ver semver.Version) bool {
@@ -324,6 +793,10 @@ func extractRef(schema spec.Schema) string {
 }
 
 func (c *Catalog) definitions() spec.Definitions {
+	if c.generic {
+		return c.reachableDefinitions()
+	}
+
 	out := spec.Definitions{}
 
 	for name, schema := range c.apiSpec.Definitions {
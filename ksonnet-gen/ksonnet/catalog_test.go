@@ -2,11 +2,17 @@ package ksonnet
 
 import (
 	"io/ioutil"
+	"os"
+	"path/filepath"
 	"sort"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/go-openapi/spec"
+	jsonnet "github.com/google/go-jsonnet"
 	"github.com/ksonnet/ksonnet-lib/ksonnet-gen/kubespec"
+	"github.com/ksonnet/ksonnet-lib/ksonnet-gen/kubeversion"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -37,20 +43,47 @@ func TestCatalog_nil_apiSpec(t *testing.T) {
 	require.Error(t, err)
 }
 
+// versionDataDir creates a directory of version data with a patch release of
+// 1.8 which aliases podIP to podIpAddress.
+func versionDataDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "versions")
+	require.NoError(t, err)
+
+	data := `{"version": "v1.8.1", "range": ">=1.8.0 <1.9.0", "inherits": "v1.8.0", "idAliases": {"podIP": "podIpAddress"}}`
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "v1.8.1.json"), []byte(data), 0644))
+
+	return dir
+}
+
+func TestCatalog_version_data(t *testing.T) {
+	dir := versionDataDir(t)
+	defer os.RemoveAll(dir)
+
+	d, err := kubeversion.ReadDir(dir)
+	require.NoError(t, err)
+
+	c := initCatalog(t, "swagger-1.8.json", CatalogOptVersionData(d))
+	require.Equal(t, "podIpAddress", c.identifierAliases["podIP"])
+	require.Equal(t, "clientIp", c.identifierAliases["clientIP"])
+
+	c = initCatalog(t, "swagger-1.8.json")
+	require.Equal(t, "podIp", c.identifierAliases["podIP"])
+}
+
 func TestCatalog_Types(t *testing.T) {
 	c := initCatalog(t, "swagger-1.8.json")
 
 	resources, err := c.Types()
 	require.NoError(t, err)
 
-	var found bool
+	found := make(map[string]bool)
 	for _, resource := range resources {
-		if resource.Identifier() == "io.k8s.api.apps.v1beta1.Deployment" {
-			found = true
-		}
+		found[resource.Identifier()] = true
 	}
 
-	require.True(t, found)
+	require.True(t, found["io.k8s.api.apps.v1beta1.Deployment"])
+	// lists don't have paths, but have a group, version and kind.
+	require.True(t, found["io.k8s.api.apps.v1beta1.DeploymentList"])
 }
 
 func TestCatalog_Resources_invalid_description(t *testing.T) {
@@ -222,6 +255,163 @@ func TestCatalog_TypesWithDescendant(t *testing.T) {
 	require.Equal(t, expected, names)
 }
 
+func TestCatalog_concurrent(t *testing.T) {
+	c := initCatalog(t, "swagger-1.8.json")
+
+	var wg sync.WaitGroup
+	errs := make([]error, 8)
+	counts := make([]int, 8)
+
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			types, err := c.Types()
+			if err != nil {
+				errs[i] = err
+				return
+			}
+
+			if _, err := c.Field("io.k8s.api.core.v1.PodSpec"); err != nil {
+				errs[i] = err
+				return
+			}
+
+			if _, err := c.Resource("apps", "v1beta2", "Deployment"); err != nil {
+				errs[i] = err
+				return
+			}
+
+			withPodSpec, err := c.TypesWithDescendant("io.k8s.api.core.v1.PodSpec")
+			if err != nil {
+				errs[i] = err
+				return
+			}
+
+			counts[i] = len(types) + len(withPodSpec)
+		}(i)
+	}
+	wg.Wait()
+
+	for i := range errs {
+		require.NoError(t, errs[i])
+		require.Equal(t, counts[0], counts[i])
+	}
+}
+
+func TestCatalog_TypesWithDescendant_memoized(t *testing.T) {
+	c := initCatalog(t, "swagger-1.8.json")
+
+	first, err := c.TypesWithDescendant("io.k8s.api.core.v1.PodSpec")
+	require.NoError(t, err)
+
+	pod, err := c.Resource("core", "v1", "Pod")
+	require.NoError(t, err)
+	require.True(t, c.descendantsCache[pod.Identifier()]["io.k8s.api.core.v1.Container"])
+
+	second, err := c.TypesWithDescendant("io.k8s.api.core.v1.PodSpec")
+	require.NoError(t, err)
+	require.Equal(t, first, second)
+}
+
+func TestCatalog_DescendantPath(t *testing.T) {
+	c := initCatalog(t, "swagger-1.8.json")
+
+	cases := []struct {
+		name       string
+		component  string
+		definition string
+		expected   []string
+	}{
+		{
+			name:       "pod",
+			component:  "core.v1.Pod",
+			definition: "io.k8s.api.core.v1.PodSpec",
+			expected:   []string{"spec"},
+		},
+		{
+			name:       "deployment",
+			component:  "apps.v1beta2.Deployment",
+			definition: "io.k8s.api.core.v1.PodSpec",
+			expected:   []string{"spec", "template", "spec"},
+		},
+		{
+			name:       "cron job",
+			component:  "batch.v1beta1.CronJob",
+			definition: "io.k8s.api.core.v1.PodSpec",
+			expected:   []string{"spec", "jobTemplate", "spec", "template", "spec"},
+		},
+		{
+			name:       "pod template",
+			component:  "core.v1.PodTemplate",
+			definition: "io.k8s.api.core.v1.PodSpec",
+			expected:   []string{"template", "spec"},
+		},
+		{
+			name:       "no descendant",
+			component:  "core.v1.Service",
+			definition: "io.k8s.api.core.v1.PodSpec",
+		},
+	}
+
+	types, err := c.Types()
+	require.NoError(t, err)
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, ty := range types {
+				// the body of requests for other kinds (e.g. meta.v1.Patch)
+				// can have their component too.
+				if ty.component.String() != tc.component || !strings.HasSuffix(ty.Identifier(), "."+ty.Kind()) {
+					continue
+				}
+
+				path, err := c.DescendantPath(ty, tc.definition)
+				require.NoError(t, err)
+				require.Equal(t, tc.expected, path)
+				return
+			}
+
+			t.Fatalf("type %s was not found", tc.component)
+		})
+	}
+}
+
+func TestCatalog_TypesWithDescendant_recursive(t *testing.T) {
+	c := initCatalog(t, "recursive.json")
+
+	cases := []struct {
+		name       string
+		definition string
+		expected   []string
+	}{
+		{
+			name:       "reachable through a cycle",
+			definition: "io.k8s.api.example.v1.Target",
+			expected:   []string{"example.v1.Root"},
+		},
+		{
+			name:       "not reachable",
+			definition: "io.k8s.api.example.v1.Missing",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			types, err := c.TypesWithDescendant(tc.definition)
+			require.NoError(t, err)
+
+			var names []string
+			for _, ty := range types {
+				names = append(names, ty.component.String())
+			}
+
+			require.Equal(t, tc.expected, names)
+		})
+	}
+}
+
 func TestCatalog_isFormatRef(t *testing.T) {
 	cases := []struct {
 		name        string
@@ -239,6 +429,10 @@ func TestCatalog_isFormatRef(t *testing.T) {
 			name:        "io.k8s.apimachinery.pkg.util.intstr.IntOrString",
 			isFormatRef: true,
 		},
+		{
+			name:        "io.k8s.apimachinery.pkg.api.resource.Quantity",
+			isFormatRef: true,
+		},
 	}
 
 	c := initCatalog(t, "swagger-1.8.json")
@@ -256,3 +450,121 @@ func TestCatalog_isFormatRef(t *testing.T) {
 		})
 	}
 }
+
+func TestCatalog_non_kubernetes_names(t *testing.T) {
+	c := initCatalog(t, "openshift.json")
+
+	ty, err := c.TypeByID("com.github.openshift.api.route.v1.Route")
+	require.NoError(t, err)
+	assert.Equal(t, "routeOpenshiftIo", ty.Group())
+	assert.Equal(t, "route.openshift.io", ty.QualifiedGroup())
+	assert.Equal(t, "v1", ty.Version())
+	assert.Equal(t, "Route", ty.Kind())
+
+	// RouteSpec doesn't have a group/version/kind extension, so it is
+	// described by the other definitions in its package.
+	f, err := c.Field("com.github.openshift.api.route.v1.RouteSpec")
+	require.NoError(t, err)
+	assert.Equal(t, "routeOpenshiftIo", f.Group())
+	assert.Equal(t, "route.openshift.io", f.QualifiedGroup())
+	assert.Equal(t, "v1", f.Version())
+	assert.Equal(t, "RouteSpec", f.Kind())
+	assert.Equal(t, "api", f.Codebase())
+
+	f, err = c.Field("io.k8s.api.apps.v1.DeploymentSpec")
+	require.NoError(t, err)
+	assert.Equal(t, "apps", f.Group())
+	assert.Equal(t, "apps", f.QualifiedGroup())
+}
+
+func TestCatalog_name_patterns(t *testing.T) {
+	c := initCatalog(t, "name-patterns.json")
+	_, err := c.Fields()
+	require.Error(t, err)
+
+	p, err := ParseNamePattern(`^io\.example\.(?P<group>\w+)\.(?P<version>v\w+)\.(?P<kind>\w+)$`)
+	require.NoError(t, err)
+
+	c = initCatalog(t, "name-patterns.json", CatalogOptNamePatterns(p))
+	f, err := c.Field("io.example.widgets.v1.Gadget")
+	require.NoError(t, err)
+	assert.Equal(t, "widgets", f.Group())
+	assert.Equal(t, "v1", f.Version())
+	assert.Equal(t, "Gadget", f.Kind())
+}
+
+func TestGenerateLib_non_kubernetes_names(t *testing.T) {
+	lib, err := GenerateLib("testdata/openshift.json")
+	require.NoError(t, err)
+
+	vm := jsonnet.MakeVM()
+	vm.Importer(&jsonnet.MemoryImporter{
+		Data: map[string]string{"k8s.libsonnet": string(lib.K8s)},
+	})
+
+	snippet := `
+local k8s = import 'k8s.libsonnet';
+local route = k8s.routeOpenshiftIo.v1.route;
+local dc = k8s.appsOpenshiftIo.v1.deploymentConfig;
+local deployment = k8s.apps.v1.deployment;
+
+[
+  route.new() + route.mixin.metadata.withName('web') +
+  route.mixin.spec.to.withName('web') + route.mixin.spec.to.withWeight(100),
+  dc.new() + dc.mixin.spec.withReplicas(2),
+  deployment.mixin.spec.withReplicas(3),
+]
+`
+
+	out, err := vm.EvaluateSnippet("snippet", snippet)
+	require.NoError(t, err)
+
+	expected := `[
+  {
+    "apiVersion": "route.openshift.io/v1",
+    "kind": "Route",
+    "metadata": {"name": "web"},
+    "spec": {"to": {"name": "web", "weight": 100}}
+  },
+  {
+    "apiVersion": "apps.openshift.io/v1",
+    "kind": "DeploymentConfig",
+    "spec": {"replicas": 2}
+  },
+  {
+    "spec": {"replicas": 3}
+  }
+]`
+	assert.JSONEq(t, expected, out)
+}
+
+func BenchmarkCatalog_Field(b *testing.B) {
+	c, err := NewCatalog(crdSpec(b, 100))
+	require.NoError(b, err)
+
+	fields, err := c.Fields()
+	require.NoError(b, err)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := c.Field(fields[i%len(fields)].Identifier()); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkCatalog_TypesWithDescendant(b *testing.B) {
+	c, err := NewCatalog(crdSpec(b, 100))
+	require.NoError(b, err)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := c.TypesWithDescendant("io.k8s.api.core.v1.PodSpec"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
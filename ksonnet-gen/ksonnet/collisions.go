@@ -0,0 +1,380 @@
+package ksonnet
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	nm "github.com/ksonnet/ksonnet-lib/ksonnet-gen/nodemaker"
+	"github.com/pkg/errors"
+)
+
+// generatedSource is the source of the keys of an object which aren't
+// generated for a property, e.g. constructors and mixin helpers.
+const generatedSource = "(generated)"
+
+// Collision is a key of a generated object which more than one property or
+// kind is rendered with.
+type Collision struct {
+	// Object is the definition or the group version the key is in.
+	// (e.g. io.k8s.api.core.v1.PodSpec or apps.v1beta1)
+	Object string
+	// Key is the name of the key.
+	Key string
+	// Sources are the properties or definitions rendered with the key.
+	Sources []string
+	// Kept is the source which kept the key. The other sources were renamed
+	// or not rendered.
+	Kept string
+	// Renamed are the keys the renamed sources are rendered with instead,
+	// by source. (e.g. withDollarRef2 for $ref)
+	Renamed map[string]string
+}
+
+func (c Collision) String() string {
+	s := fmt.Sprintf("%s: %s is generated for %s; it is kept for %s",
+		c.Object, c.Key, strings.Join(c.Sources, ", "), c.Kept)
+
+	var renamed []string
+	for _, source := range c.Sources {
+		if key, ok := c.Renamed[source]; ok {
+			renamed = append(renamed, fmt.Sprintf("%s for %s", key, source))
+		}
+	}
+	if len(renamed) > 0 {
+		s += fmt.Sprintf(", and renamed to %s", strings.Join(renamed, ", "))
+	}
+
+	return s
+}
+
+// CollisionError is returned when a Catalog fails on collisions and the lib
+// has them.
+type CollisionError struct {
+	Collisions []Collision
+}
+
+func (e *CollisionError) Error() string {
+	lines := []string{fmt.Sprintf("%d generated identifiers collide:", len(e.Collisions))}
+	for _, c := range e.Collisions {
+		lines = append(lines, "  "+c.String())
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// renderedField is a property rendered into an object of its own, so its
+// keys can be checked before they are added to the object it belongs in.
+type renderedField struct {
+	prop Property
+	dst  *nm.Object
+	keys *nm.Object
+	// skip are the keys which aren't added to dst.
+	skip map[string]bool
+	// base is the identifier the property was first rendered with.
+	base string
+	// tried are the identifiers the property was rendered with.
+	tried map[string]bool
+}
+
+func newRenderedField(prop Property, dst *nm.Object) *renderedField {
+	id, _ := propertyIdentifiers(prop)
+
+	return &renderedField{
+		prop:  prop,
+		dst:   dst,
+		keys:  nm.NewObject(),
+		skip:  make(map[string]bool),
+		base:  id,
+		tried: map[string]bool{id: true},
+	}
+}
+
+func (rf *renderedField) merge() {
+	for _, key := range rf.keys.Keys() {
+		if rf.skip[key.Name()] {
+			continue
+		}
+
+		rf.dst.Set(key, rf.keys.Get(key.Name()))
+	}
+}
+
+// names returns the names of the keys which are added to dst, in the order
+// they were rendered.
+func (rf *renderedField) names() []string {
+	var out []string
+	for _, key := range rf.keys.Keys() {
+		if !rf.skip[key.Name()] {
+			out = append(out, key.Name())
+		}
+	}
+
+	return out
+}
+
+// resolveCollisions finds the keys which more than one field, or a field and
+// the object it belongs in, are rendered with. The key is kept for a key
+// already in the object, then for the field whose name is its identifier,
+// and then for the first field. Type aliases and deprecated aliases which
+// collide are not rendered, and the other fields are rendered again with
+// another identifier, until there aren't any collisions. The collisions are
+// recorded once they are resolved, with the keys the renamed fields are
+// rendered with in the end.
+func resolveCollisions(tl typeLookup, definition string, fields []*renderedField,
+	reserved map[*nm.Object]map[string]bool, render func(*renderedField) error) error {
+
+	var found []*foundCollision
+
+	// every rename tries a new identifier, so this is only reached if the
+	// identifiers keep colliding.
+	for attempt := 0; attempt <= len(fields)+1; attempt++ {
+		var renames []*renderedField
+
+		for _, owners := range keyOwners(fields) {
+			key, dst := owners.key, owners.dst
+			if len(owners.fields) < 2 && !reserved[dst][key] {
+				continue
+			}
+
+			kept, renamed, skipped := settleKey(key, owners.fields, reserved[dst][key])
+			for _, rf := range skipped {
+				rf.skip[key] = true
+			}
+			renames = append(renames, renamed...)
+
+			c := Collision{Object: definition, Key: key, Kept: generatedSource}
+			if reserved[dst][key] {
+				c.Sources = append(c.Sources, generatedSource)
+			}
+			for _, rf := range owners.fields {
+				c.Sources = append(c.Sources, rf.prop.Name())
+			}
+			if kept != nil {
+				c.Kept = kept.prop.Name()
+			}
+
+			fc := &foundCollision{Collision: c, renamed: make(map[*renderedField]string)}
+			for _, rf := range renamed {
+				fc.renamed[rf], _ = propertyIdentifiers(rf.prop)
+			}
+			found = append(found, fc)
+		}
+
+		if len(renames) == 0 {
+			for _, fc := range found {
+				tl.recordCollision(fc.resolve())
+			}
+			return nil
+		}
+
+		for _, rf := range uniqueFields(renames) {
+			if err := renameField(rf); err != nil {
+				return err
+			}
+
+			if err := render(rf); err != nil {
+				return err
+			}
+		}
+	}
+
+	return errors.Errorf("identifiers of the properties of %s keep colliding", definition)
+}
+
+// foundCollision is a collision whose renamed fields may still be renamed
+// again.
+type foundCollision struct {
+	Collision
+	// renamed are the identifiers the renamed fields had when the collision
+	// was found.
+	renamed map[*renderedField]string
+}
+
+// resolve returns the collision with the keys the renamed fields are
+// rendered with now.
+func (fc *foundCollision) resolve() Collision {
+	c := fc.Collision
+	if len(fc.renamed) == 0 {
+		return c
+	}
+
+	c.Renamed = make(map[string]string)
+	for rf, id := range fc.renamed {
+		resolved, _ := propertyIdentifiers(rf.prop)
+		c.Renamed[rf.prop.Name()] = renamedKey(c.Key, id, resolved)
+	}
+
+	return c
+}
+
+// renamedKey returns the key a field renamed from one identifier to another
+// renders in place of key. (e.g. withDollarRef2 for withDollarRef)
+func renamedKey(key, from, to string) string {
+	if strings.HasPrefix(key, from) {
+		return to + strings.TrimPrefix(key, from)
+	}
+
+	return strings.Replace(key, strings.Title(from), strings.Title(to), 1)
+}
+
+// owners are the fields rendered with a key of an object.
+type owners struct {
+	key    string
+	dst    *nm.Object
+	fields []*renderedField
+}
+
+// keyOwners returns the owners of the keys of the fields, in the order the
+// keys are rendered.
+func keyOwners(fields []*renderedField) []*owners {
+	var list []*owners
+	index := make(map[*nm.Object]map[string]*owners)
+
+	for _, rf := range fields {
+		if index[rf.dst] == nil {
+			index[rf.dst] = make(map[string]*owners)
+		}
+
+		for _, key := range rf.keys.Keys() {
+			name := key.Name()
+			if rf.skip[name] {
+				continue
+			}
+
+			o, ok := index[rf.dst][name]
+			if !ok {
+				o = &owners{key: name, dst: rf.dst}
+				index[rf.dst][name] = o
+				list = append(list, o)
+			}
+			o.fields = append(o.fields, rf)
+		}
+	}
+
+	return list
+}
+
+// settleKey decides which field keeps a key, which fields are renamed, and
+// which fields don't render the key.
+func settleKey(key string, fields []*renderedField, reserved bool) (kept *renderedField, renamed, skipped []*renderedField) {
+	var primary []*renderedField
+	for _, rf := range fields {
+		if secondaryKeys(rf.prop)[key] {
+			skipped = append(skipped, rf)
+		} else {
+			primary = append(primary, rf)
+		}
+	}
+
+	if len(primary) == 0 {
+		if reserved {
+			return nil, nil, skipped
+		}
+
+		// the first alias keeps the key.
+		return skipped[0], nil, skipped[1:]
+	}
+
+	if !reserved {
+		kept = primary[0]
+		for _, rf := range primary {
+			if id, _ := propertyIdentifiers(rf.prop); id == rf.prop.Name() {
+				kept = rf
+				break
+			}
+		}
+	}
+
+	for _, rf := range primary {
+		if rf != kept {
+			renamed = append(renamed, rf)
+		}
+	}
+
+	return kept, renamed, skipped
+}
+
+// secondaryKeys are the keys of a property which alias other keys: its type
+// alias and its deprecated identifiers.
+func secondaryKeys(p Property) map[string]bool {
+	keys := map[string]bool{typeAliasName(p.Name()): true}
+
+	_, deprecated := propertyIdentifiers(p)
+	for _, id := range deprecated {
+		setter := fmt.Sprintf("with%s", strings.Title(id))
+		keys[id] = true
+		keys[setter] = true
+		keys[setter+"Mixin"] = true
+	}
+
+	return keys
+}
+
+// renameField gives a field the next identifier it hasn't been rendered
+// with, i.e. its first identifier with a number. (e.g. dollarRef2) The field
+// is renamed with a copy of its property, because the properties of the
+// Catalog are shared by the objects rendered with them, which can be
+// rendered concurrently.
+func renameField(rf *renderedField) error {
+	var ip identifiedProperty
+	switch t := rf.prop.(type) {
+	case *LiteralField:
+		renamed := *t
+		ip = &renamed
+	case *ReferenceField:
+		renamed := *t
+		ip = &renamed
+	default:
+		return errors.Errorf("property %s can't be renamed", rf.prop.Name())
+	}
+
+	id := rf.base + strconv.Itoa(len(rf.tried)+1)
+	rf.tried[id] = true
+	ip.setIdentifiers(id, ip.DeprecatedIdentifiers())
+	rf.prop = ip
+
+	return nil
+}
+
+// collisionLog is a typeLookup which logs the collisions found with it
+// instead of recording them in the Catalog, so the collisions of objects
+// rendered concurrently can be recorded in the order of the objects.
+type collisionLog struct {
+	typeLookup
+	collisions []Collision
+}
+
+func (l *collisionLog) recordCollision(c Collision) {
+	l.collisions = append(l.collisions, c)
+}
+
+func uniqueFields(fields []*renderedField) []*renderedField {
+	var out []*renderedField
+	seen := make(map[*renderedField]bool)
+
+	for _, rf := range fields {
+		if !seen[rf] {
+			seen[rf] = true
+			out = append(out, rf)
+		}
+	}
+
+	return out
+}
+
+// objectKeys returns the names of the keys of objects.
+func objectKeys(objects ...*nm.Object) map[*nm.Object]map[string]bool {
+	out := make(map[*nm.Object]map[string]bool)
+	for _, o := range objects {
+		if out[o] == nil {
+			out[o] = make(map[string]bool)
+		}
+
+		for _, key := range o.Keys() {
+			out[o][key.Name()] = true
+		}
+	}
+
+	return out
+}
@@ -0,0 +1,177 @@
+package ksonnet
+
+import (
+	"sync"
+	"testing"
+
+	jsonnet "github.com/google/go-jsonnet"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var expectedCollisions = []Collision{
+	{
+		Object:  "io.k8s.api.example.v1.WidgetSpec",
+		Key:     "mixinInstance",
+		Sources: []string{generatedSource, "mixinInstance"},
+		Kept:    generatedSource,
+		Renamed: map[string]string{"mixinInstance": "mixinInstance2"},
+	},
+	{
+		Object:  "io.k8s.api.example.v1.Widget",
+		Key:     "withDollarRef",
+		Sources: []string{"$ref", "dollarRef"},
+		Kept:    "dollarRef",
+		Renamed: map[string]string{"$ref": "withDollarRef2"},
+	},
+	{
+		Object:  "io.k8s.api.example.v1.Widget",
+		Key:     "withHostIpc",
+		Sources: []string{"hostIPC", "hostIpc"},
+		Kept:    "hostIpc",
+		Renamed: map[string]string{"hostIPC": "withHostIpc2"},
+	},
+	{
+		Object:  "io.k8s.api.example.v1.Widget",
+		Key:     "specType",
+		Sources: []string{"spec", "specType"},
+		Kept:    "specType",
+	},
+	{
+		Object:  "example.v1",
+		Key:     "httpRoute",
+		Sources: []string{"io.k8s.api.example.v1.HTTPRoute", "io.k8s.api.example.v1.HttpRoute"},
+		Kept:    "io.k8s.api.example.v1.HTTPRoute",
+	},
+}
+
+func TestCatalog_Collisions(t *testing.T) {
+	c := initCatalog(t, "collisions.json")
+
+	first, err := createK8s(c)
+	require.NoError(t, err)
+	require.Equal(t, expectedCollisions, c.Collisions())
+
+	c = initCatalog(t, "collisions.json")
+	second, err := createK8s(c)
+	require.NoError(t, err)
+	require.Equal(t, string(first), string(second))
+
+	warnings := c.Warnings()
+	assert.Contains(t, warnings,
+		"io.k8s.api.example.v1.Widget: withHostIpc is generated for hostIPC, hostIpc; it is kept for hostIpc, and renamed to withHostIpc2 for hostIPC")
+}
+
+func TestCatalog_Collisions_concurrent(t *testing.T) {
+	c := initCatalog(t, "collisions.json")
+
+	var wg sync.WaitGroup
+	out := make([][]byte, 4)
+	errs := make([]error, 4)
+	for i := range out {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			out[i], errs[i] = createK8s(c)
+		}(i)
+	}
+	wg.Wait()
+
+	for i := range out {
+		require.NoError(t, errs[i])
+		require.Equal(t, string(out[0]), string(out[i]))
+	}
+
+	// the collisions are recorded once, in the order they are found.
+	require.Equal(t, expectedCollisions, c.Collisions())
+}
+
+func TestGenerateLib_collisions(t *testing.T) {
+	lib, err := GenerateLib("testdata/collisions.json")
+	require.NoError(t, err)
+
+	vm := jsonnet.MakeVM()
+	vm.Importer(&jsonnet.MemoryImporter{
+		Data: map[string]string{"k8s.libsonnet": string(lib.K8s)},
+	})
+
+	snippet := `
+local widget = (import 'k8s.libsonnet').example.v1.widget;
+
+widget.new() +
+widget.withHostIpc('canonical') + widget.withHostIpc2('aliased') +
+widget.withDollarRef('canonical') + widget.withDollarRef2('formatted') +
+widget.mixin.spec.withName('spec') +
+widget.mixin.spec.mixinInstance2.withPath('/') +
+widget.mixin.specType.withName('specType')
+`
+	got, err := vm.EvaluateSnippet("collisions.jsonnet", snippet)
+	require.NoError(t, err)
+
+	expected := `{
+   "$ref": "formatted",
+   "apiVersion": "example/v1",
+   "dollarRef": "canonical",
+   "hostIPC": "aliased",
+   "hostIpc": "canonical",
+   "kind": "Widget",
+   "spec": {
+      "mixinInstance": {
+         "path": "/"
+      },
+      "name": "spec"
+   },
+   "specType": {
+      "name": "specType"
+   }
+}
+`
+	require.Equal(t, expected, got)
+}
+
+func TestGenerateLib_fail_on_collisions(t *testing.T) {
+	_, err := GenerateLib("testdata/collisions.json", CatalogOptFailOnCollisions(true))
+	require.Error(t, err)
+
+	collisionErr, ok := errors.Cause(err).(*CollisionError)
+	require.True(t, ok, "unexpected error %v", err)
+	require.Equal(t, expectedCollisions, collisionErr.Collisions)
+	assert.Contains(t, err.Error(), "5 generated identifiers collide:\n"+
+		"  io.k8s.api.example.v1.WidgetSpec: mixinInstance is generated for (generated), mixinInstance; it is kept for (generated)")
+
+	_, err = GenerateLib("testdata/swagger-1.8.json", CatalogOptFailOnCollisions(true))
+	require.NoError(t, err)
+}
+
+func Test_renameField(t *testing.T) {
+	prop := NewLiteralField("hostIPC", "boolean", "desc", "")
+	prop.setIdentifiers("hostIpc", nil)
+
+	rf := newRenderedField(prop, nil)
+	require.NoError(t, renameField(rf))
+
+	id, _ := propertyIdentifiers(rf.prop)
+	require.Equal(t, "hostIpc2", id)
+
+	// the property of the catalog isn't renamed.
+	require.Equal(t, "hostIpc", prop.Identifier())
+}
+
+func Test_renamedKey(t *testing.T) {
+	cases := []struct {
+		key      string
+		expected string
+	}{
+		{key: "dollarRef", expected: "dollarRef2"},
+		{key: "dollarRefType", expected: "dollarRef2Type"},
+		{key: "withDollarRef", expected: "withDollarRef2"},
+		{key: "withDollarRefMixin", expected: "withDollarRef2Mixin"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.key, func(t *testing.T) {
+			require.Equal(t, tc.expected, renamedKey(tc.key, "dollarRef", "dollarRef2"))
+		})
+	}
+}
@@ -9,6 +9,8 @@ import (
 
 const (
 	extensionGroupVersionKind = "x-kubernetes-group-version-kind"
+	extensionPatchMergeKey    = "x-kubernetes-patch-merge-key"
+	extensionPatchStrategy    = "x-kubernetes-patch-strategy"
 )
 
 // Component is resource information provided in the k8s swagger schema
@@ -4,7 +4,6 @@ import (
 	"regexp"
 	"sort"
 
-	"github.com/google/go-jsonnet/ast"
 	nm "github.com/ksonnet/ksonnet-lib/ksonnet-gen/nodemaker"
 	"github.com/pkg/errors"
 )
@@ -88,10 +87,7 @@ func (c *constructor) Body(baseNodes ...nm.Noder) nm.Noder {
 
 	for _, funName := range funNames {
 
-		call := nm.NewCall(funName)
-
-		var curApply *ctorApply
-		var addedCall bool
+		var target nm.Chainable = nm.NewCall(funName)
 
 		ars := funs[funName]
 		sort.Slice(ars, func(i, j int) bool {
@@ -99,38 +95,18 @@ func (c *constructor) Body(baseNodes ...nm.Noder) nm.Noder {
 		})
 
 		for _, ar := range ars {
-			indexID := ast.Identifier(ar.fn)
-			index := &ast.Index{Id: &indexID}
-			if !addedCall {
-				index.Target = call.Node()
-				addedCall = true
-			} else {
-				index.Target = curApply.Node()
-			}
-
-			arg := &ast.Var{Id: ast.Identifier(ar.name)}
-			apply := ast.Apply{
-				Arguments: ast.Arguments{Positional: ast.Nodes{arg}},
-				Target:    index,
-			}
-
-			curApply = &ctorApply{Apply: apply}
+			index := nm.NewIndex(ar.fn)
+			index.SetTarget(target)
+
+			target = nm.NewApply(index, []nm.Noder{nm.NewVar(ar.name)}, nil)
 		}
 
-		items = append(items, curApply)
+		items = append(items, target)
 	}
 
 	return nm.Combine(items...)
 }
 
-type ctorApply struct {
-	ast.Apply
-}
-
-func (ca *ctorApply) Node() ast.Node {
-	return &ca.Apply
-}
-
 type argRef struct {
 	name string
 	fn   string
@@ -142,6 +118,10 @@ type constructorParam struct {
 	defaultValue interface{}
 }
 
+// jsonnetExpr is the jsonnet source of a default value. It can refer to the
+// other parameters of the constructor. (e.g. `{app: name}`)
+type jsonnetExpr string
+
 func newConstructorParam(name, function string, defaultValue interface{}) *constructorParam {
 	if defaultValue == nil {
 		defaultValue = ""
@@ -179,6 +159,11 @@ func (cp *constructorParam) Option() (nm.OptionalArg, error) {
 		node = nm.NewInt(t)
 	case bool:
 		node = nm.NewBoolean(t)
+	case jsonnetExpr:
+		node, err = nm.Parse(cp.name, string(t))
+		if err != nil {
+			return nm.OptionalArg{}, errors.Wrapf(err, "invalid default for parameter %s", cp.name)
+		}
 	default:
 		return nm.OptionalArg{}, errors.Errorf("unable to use type %T in param", t)
 	}
@@ -170,3 +170,29 @@ func Test_constructorParam(t *testing.T) {
 		})
 	}
 }
+
+func Test_versionConstructors(t *testing.T) {
+	c := initCatalog(t, "swagger-1.8.json")
+
+	ctors, ok, err := versionConstructors(c, "io.k8s.api.core.v1.Secret")
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	expected := []constructor{
+		*newConstructor("new",
+			*newConstructorParam("name", "mixin.metadata.withName", nil),
+			*newConstructorParam("data", "withData", nil),
+			*newConstructorParam("type", "withType", jsonnetExpr(`"Opaque"`)),
+		),
+		*newConstructor("fromString",
+			*newConstructorParam("name", "mixin.metadata.withName", nil),
+			*newConstructorParam("stringData", "withStringData", nil),
+			*newConstructorParam("type", "withType", jsonnetExpr(`"Opaque"`)),
+		),
+	}
+	require.Equal(t, expected, ctors)
+
+	_, ok, err = versionConstructors(c, "io.k8s.api.core.v1.PodSpec")
+	require.NoError(t, err)
+	require.False(t, ok)
+}
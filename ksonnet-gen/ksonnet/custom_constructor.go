@@ -1,5 +1,11 @@
 package ksonnet
 
+import (
+	"github.com/ksonnet/ksonnet-lib/ksonnet-gen/kubespec"
+	"github.com/ksonnet/ksonnet-lib/ksonnet-gen/kubeversion"
+	"github.com/pkg/errors"
+)
+
 // NOTE: custom constructors will be removed at ksonnet 0.11
 
 func locateConstructors(desc Description) []constructor {
@@ -11,6 +17,53 @@ func locateConstructors(desc Description) []constructor {
 	return ctors
 }
 
+// versionConstructors returns the custom constructors of a definition from
+// the version data of the Catalog's Kubernetes version. Like the built in
+// constructors, parameters without a default value default to an empty
+// string. It returns false if there is no version data for the version, or
+// the definition doesn't have custom constructors in it.
+func versionConstructors(c *Catalog, definition string) ([]constructor, bool, error) {
+	if c.versionData == nil {
+		return nil, false, nil
+	}
+
+	specs, ok, err := c.versionData.ConstructorSpec(c.Version(), kubespec.DefinitionName(definition))
+	if err != nil {
+		if _, isUnknown := err.(*kubeversion.UnknownVersionError); isUnknown {
+			return nil, false, nil
+		}
+
+		return nil, false, errors.Wrapf(err, "load constructors of %s", definition)
+	}
+
+	if !ok {
+		return nil, false, nil
+	}
+
+	var ctors []constructor
+	for _, spec := range specs {
+		var params []constructorParam
+		for _, param := range spec.Params {
+			// parameters without a path set the property they are named after.
+			function := fieldName(c.identifier(param.ID), false)
+			if param.RelativePath != nil {
+				function = *param.RelativePath
+			}
+
+			var defaultValue interface{}
+			if param.DefaultValue != nil {
+				defaultValue = jsonnetExpr(*param.DefaultValue)
+			}
+
+			params = append(params, *newConstructorParam(param.ID, function, defaultValue))
+		}
+
+		ctors = append(ctors, *newConstructor(spec.ID, params...))
+	}
+
+	return ctors, true, nil
+}
+
 func makeDescriptor(codebase, group, kind string) Description {
 	return Description{
 		Codebase: codebase,
@@ -3,6 +3,10 @@ package ksonnet
 import (
 	"fmt"
 	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
 )
 
 const (
@@ -53,6 +57,9 @@ type Description struct {
 	Kind     string
 	Group    string
 	Codebase string
+	// QualifiedGroup is the fully qualified group. (e.g. route.openshift.io)
+	// It is blank if the definition name only has the short group.
+	QualifiedGroup string
 }
 
 // Validate validates the Description. A description is valid if it has a version.
@@ -67,31 +74,116 @@ func (d *Description) Validate() error {
 // ParseDescription takes a definition name and returns a Description.
 func ParseDescription(name string) (*Description, error) {
 	for _, r := range reNames {
-		if match := r.FindStringSubmatch(name); len(match) > 0 {
-
-			result := make(map[string]string)
-			for i, name := range r.SubexpNames() {
-				if i != 0 {
-					result[name] = match[i]
-				}
-			}
-
-			codebase := result["codebase"]
-			if codebase == "" {
-				codebase = "api"
-			}
-
-			d := &Description{
-				Name:     name,
-				Version:  result["version"],
-				Kind:     result["kind"],
-				Group:    result["group"],
-				Codebase: codebase,
-			}
-
+		if d := matchDescription(r, name); d != nil {
 			return d, nil
 		}
 	}
 
 	return nil, &UnknownDefinitionError{name: name}
 }
+
+// matchDescription describes a definition name with the named groups of a
+// regular expression. It returns nil if the name doesn't match.
+func matchDescription(r *regexp.Regexp, name string) *Description {
+	match := r.FindStringSubmatch(name)
+	if len(match) == 0 {
+		return nil
+	}
+
+	result := make(map[string]string)
+	for i, name := range r.SubexpNames() {
+		if i != 0 {
+			result[name] = match[i]
+		}
+	}
+
+	codebase := result["codebase"]
+	if codebase == "" {
+		codebase = "api"
+	}
+
+	return &Description{
+		Name:     name,
+		Version:  result["version"],
+		Kind:     result["kind"],
+		Group:    result["group"],
+		Codebase: codebase,
+	}
+}
+
+// NamePattern is a regular expression which describes the definition names
+// of an API which doesn't use the Kubernetes naming scheme. Its named groups
+// are the version, kind, group and codebase of the definition. A group with
+// dots is a fully qualified group. e.g.
+// `^com\.github\.openshift\.api\.(?P<group>\w+)\.(?P<version>\w+)\.(?P<kind>\w+)$`
+type NamePattern struct {
+	re *regexp.Regexp
+}
+
+// ParseNamePattern parses a name pattern. The pattern must have version and
+// kind groups, and can't contain spaces.
+func ParseNamePattern(pattern string) (*NamePattern, error) {
+	if strings.IndexFunc(pattern, unicode.IsSpace) != -1 {
+		return nil, errors.Errorf("name pattern %q contains spaces", pattern)
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parse name pattern %q", pattern)
+	}
+
+	for _, group := range []string{"version", "kind"} {
+		if !stringInSlice(group, re.SubexpNames()) {
+			return nil, errors.Errorf("name pattern %q has no %s group", pattern, group)
+		}
+	}
+
+	return &NamePattern{re: re}, nil
+}
+
+func (p *NamePattern) String() string {
+	return p.re.String()
+}
+
+// Describe describes a definition name. It returns nil if the name doesn't
+// match the pattern.
+func (p *NamePattern) Describe(name string) *Description {
+	d := matchDescription(p.re, name)
+	if d == nil {
+		return nil
+	}
+
+	if strings.Contains(d.Group, ".") {
+		d.QualifiedGroup = d.Group
+		d.Group = GroupName(d.Group)
+	}
+
+	return d
+}
+
+// GroupName returns the name of a group in a lib. Kubernetes groups are
+// named by their first label (e.g. rbac for rbac.authorization.k8s.io), and
+// other groups by all of their labels (e.g. routeOpenshiftIo for
+// route.openshift.io), so they can't clash with the Kubernetes groups.
+func GroupName(qualifiedGroup string) string {
+	if qualifiedGroup == "" {
+		return ""
+	}
+
+	labels := strings.Split(qualifiedGroup, ".")
+	if len(labels) == 1 || strings.HasSuffix(qualifiedGroup, ".k8s.io") {
+		return labels[0]
+	}
+
+	var parts []string
+	for _, label := range labels {
+		parts = append(parts, strings.Split(label, "-")...)
+	}
+
+	name := strings.ToLower(parts[0])
+	for _, part := range parts[1:] {
+		name += strings.Title(strings.ToLower(part))
+	}
+
+	return name
+}
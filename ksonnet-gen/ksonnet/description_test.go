@@ -101,3 +101,82 @@ func Test_ParseDescription(t *testing.T) {
 		})
 	}
 }
+
+func Test_ParseNamePattern(t *testing.T) {
+	cases := []struct {
+		name    string
+		pattern string
+		isErr   bool
+	}{
+		{
+			name:    "valid",
+			pattern: `^io\.example\.(?P<group>\w+)\.(?P<version>\w+)\.(?P<kind>\w+)$`,
+		},
+		{
+			name:    "invalid regular expression",
+			pattern: `(?P<version>\w+)\.(?P<kind>`,
+			isErr:   true,
+		},
+		{
+			name:    "without version",
+			pattern: `^io\.example\.(?P<kind>\w+)$`,
+			isErr:   true,
+		},
+		{
+			name:    "with spaces",
+			pattern: `(?P<version>\w+) (?P<kind>\w+)`,
+			isErr:   true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := ParseNamePattern(tc.pattern)
+			if tc.isErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.pattern, p.String())
+		})
+	}
+}
+
+func TestNamePattern_Describe(t *testing.T) {
+	p, err := ParseNamePattern(
+		`^io\.istio\.(?P<group>[\w.]+)\.(?P<version>v\w+)\.(?P<kind>\w+)$`)
+	require.NoError(t, err)
+
+	d := p.Describe("io.istio.networking.istio.io.v1alpha3.VirtualService")
+	require.Equal(t, &Description{
+		Name:           "io.istio.networking.istio.io.v1alpha3.VirtualService",
+		Version:        "v1alpha3",
+		Kind:           "VirtualService",
+		Group:          "networkingIstioIo",
+		Codebase:       "api",
+		QualifiedGroup: "networking.istio.io",
+	}, d)
+
+	require.Nil(t, p.Describe("io.k8s.api.apps.v1.Deployment"))
+}
+
+func TestGroupName(t *testing.T) {
+	cases := []struct {
+		qualifiedGroup string
+		expected       string
+	}{
+		{qualifiedGroup: "", expected: ""},
+		{qualifiedGroup: "apps", expected: "apps"},
+		{qualifiedGroup: "rbac.authorization.k8s.io", expected: "rbac"},
+		{qualifiedGroup: "route.openshift.io", expected: "routeOpenshiftIo"},
+		{qualifiedGroup: "serving.knative.dev", expected: "servingKnativeDev"},
+		{qualifiedGroup: "cert-manager.io", expected: "certManagerIo"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.qualifiedGroup, func(t *testing.T) {
+			require.Equal(t, tc.expected, GroupName(tc.qualifiedGroup))
+		})
+	}
+}
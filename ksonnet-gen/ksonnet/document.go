@@ -1,7 +1,9 @@
 package ksonnet
 
 import (
+	"fmt"
 	"sort"
+	"sync"
 
 	nm "github.com/ksonnet/ksonnet-lib/ksonnet-gen/nodemaker"
 	"github.com/pkg/errors"
@@ -114,11 +116,24 @@ func (d *Document) Node() (*nm.Object, error) {
 		"kubernetesVersion": d.catalog.Version(),
 		"checksum":          d.catalog.Checksum(),
 	}
+	if d.catalog.generic {
+		metadata = map[string]interface{}{
+			"version":  d.catalog.Version(),
+			"checksum": d.catalog.Checksum(),
+		}
+	}
 	metadataObj, err := nm.KVFromMap(metadata)
 	if err != nil {
 		return nil, errors.Wrap(err, "create metadata key")
 	}
 	out.Set(nm.InheritedKey("__ksonnet"), metadataObj)
+	out.Set(nm.LocalKey(localFormats), formatHelpers())
+
+	overlays, err := d.overlayHelpers()
+	if err != nil {
+		return nil, err
+	}
+	out.Set(nm.LocalKey(localOverlays), overlays)
 
 	if err := d.renderGroups(d, out); err != nil {
 		return nil, err
@@ -132,33 +147,121 @@ func (d *Document) Node() (*nm.Object, error) {
 
 	out.Set(nm.LocalKey("hidden"), hidden)
 
+	if err := d.catalog.collisionError(); err != nil {
+		return nil, err
+	}
+
 	return out, nil
 }
 
+// overlayHelpers creates the overlay helpers of the document's types and
+// fields.
+func (d *Document) overlayHelpers() (*nm.Object, error) {
+	types, err := d.typesFn()
+	if err != nil {
+		return nil, errors.Wrap(err, "retrieve types")
+	}
+
+	fields, err := d.fieldsFn()
+	if err != nil {
+		return nil, errors.Wrap(err, "retrieve fields")
+	}
+
+	var objects []Object
+	for i := range types {
+		objects = append(objects, &types[i])
+	}
+	for i := range fields {
+		objects = append(objects, &fields[i])
+	}
+
+	return overlayHelpers(objects)
+}
+
+// render renders the groups in o. Groups are rendered concurrently, and the
+// collisions found in them are recorded in the Catalog in the order of the
+// groups, so the lib and its warnings don't depend on which group finishes
+// first.
 func render(fn renderNodeFn, catalog *Catalog, o *nm.Object, groups []Group) error {
-	for _, group := range groups {
-		groupNode := group.Node()
-		for _, version := range group.Versions() {
-			versionNode := version.Node()
-			for _, apiObject := range version.APIObjects() {
-				objectNode, err := fn(catalog, &apiObject)
-				if err != nil {
-					return errors.Wrapf(err, "create node %s", apiObject.Kind())
-				}
-
-				versionNode.Set(
-					nm.NewKey(apiObject.Kind(), nm.KeyOptComment(apiObject.Description())),
-					objectNode)
-			}
+	// the definitions are looked up by the objects of every group, so they
+	// are extracted before the groups are rendered.
+	if _, err := catalog.Types(); err != nil {
+		return errors.Wrap(err, "retrieve types")
+	}
+	if _, err := catalog.Fields(); err != nil {
+		return errors.Wrap(err, "retrieve fields")
+	}
 
-			groupNode.Set(nm.NewKey(version.Name()), versionNode)
+	nodes := make([]*nm.Object, len(groups))
+	logs := make([]*collisionLog, len(groups))
+	errs := make([]error, len(groups))
+
+	var wg sync.WaitGroup
+	for i := range groups {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			logs[i] = &collisionLog{typeLookup: catalog}
+			nodes[i], errs[i] = renderGroup(fn, catalog, logs[i], groups[i])
+		}(i)
+	}
+	wg.Wait()
+
+	for i, group := range groups {
+		if errs[i] != nil {
+			return errs[i]
 		}
 
-		o.Set(nm.NewKey(group.Name()), groupNode)
+		for _, c := range logs[i].collisions {
+			catalog.recordCollision(c)
+		}
+
+		o.Set(nm.NewKey(group.Name()), nodes[i])
 	}
 
 	return nil
+}
+
+// renderGroup renders the versions of a group. The collisions found in the
+// group are logged in log.
+func renderGroup(fn renderNodeFn, catalog *Catalog, log *collisionLog, group Group) (*nm.Object, error) {
+	groupNode := group.Node()
+	for _, version := range group.Versions() {
+		versionNode := version.Node()
+		if catalog.generic {
+			// there is no apiVersion in a generic API.
+			versionNode = nm.NewObject()
+		}
+		definitions := make(map[string]string)
+		for _, apiObject := range version.APIObjects() {
+			// the first definition with a kind keeps it.
+			kind, definition := apiObject.Kind(), apiObject.resource.Identifier()
+			if kept, ok := definitions[kind]; ok {
+				log.recordCollision(Collision{
+					Object:  fmt.Sprintf("%s.%s", group.Name(), version.Name()),
+					Key:     kind,
+					Sources: []string{kept, definition},
+					Kept:    kept,
+				})
+				continue
+			}
+			definitions[kind] = definition
+
+			apiObject.lookup = log
+			objectNode, err := fn(catalog, &apiObject)
+			if err != nil {
+				return nil, errors.Wrapf(err, "create node %s", apiObject.Kind())
+			}
+
+			versionNode.Set(
+				nm.NewKey(apiObject.Kind(), nm.KeyOptComment(apiObject.Description())),
+				objectNode)
+		}
+
+		groupNode.Set(nm.NewKey(version.Name()), versionNode)
+	}
 
+	return groupNode, nil
 }
 
 func renderGroups(d *Document, container *nm.Object) error {
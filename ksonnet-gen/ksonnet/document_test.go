@@ -112,6 +112,31 @@ func TestDocument_Node(t *testing.T) {
 	}
 }
 
+func TestDocument_Node_recursive(t *testing.T) {
+	c := initCatalog(t, "recursive.json")
+
+	doc, err := NewDocument(c)
+	require.NoError(t, err)
+
+	n, err := doc.Node()
+	require.NoError(t, err)
+
+	group, ok := n.Get("example").(*nm.Object)
+	require.True(t, ok)
+	version, ok := group.Get("v1").(*nm.Object)
+	require.True(t, ok)
+	root, ok := version.Get("root").(*nm.Object)
+	require.True(t, ok)
+	mixin, ok := root.Get("mixin").(*nm.Object)
+	require.True(t, ok)
+
+	assert.NotNil(t, mixin.Get("leaf"))
+	assert.Nil(t, mixin.Get("node"))
+	assert.NotNil(t, root.Get("withNode"))
+	assert.NotNil(t, root.Get("withNodeMixin"))
+	assert.NotNil(t, root.Get("nodeType"))
+}
+
 func TestDocument_Node_groups_error(t *testing.T) {
 	c := initCatalog(t, "swagger-1.8.json")
 
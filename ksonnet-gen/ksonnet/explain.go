@@ -0,0 +1,463 @@
+package ksonnet
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	nm "github.com/ksonnet/ksonnet-lib/ksonnet-gen/nodemaker"
+	"github.com/pkg/errors"
+)
+
+// explainWidth is the width descriptions are wrapped to.
+const explainWidth = 80
+
+// Explanation explains a type of a lib, or a field of a type.
+type Explanation struct {
+	// Path is the path of the type or field. e.g. apps.v1beta2.deployment.spec
+	Path string
+	// Name is the name of the field, or the kind of the type.
+	Name string
+	// Type is the type of the field, in the style of `kubectl explain`.
+	// e.g. Object, []Object or map[string]string
+	Type string
+	// Definition is the definition of the field's object, or of the items
+	// of a list or the values of a map of objects. It is blank if the field
+	// doesn't have fields.
+	Definition  string
+	Description string
+	// Lib is the path of the object in the lib which contains the setters
+	// of the definition's fields. e.g. apps.v1beta2.deployment.mixin.spec
+	Lib string
+	// Setter and Mixin are the paths of the functions which set the field,
+	// and which merge a value into it. They are blank if the lib doesn't
+	// have them.
+	Setter string
+	Mixin  string
+	// Functions are the paths of the other functions generated for the
+	// field, e.g. the functions which set an entry of a map, or update an
+	// item of a list by its key.
+	Functions []string
+	// Fields explain the fields of the definition, sorted by name. They
+	// only explain their own fields if the explanation is Recursive.
+	Fields    []Explanation
+	Recursive bool
+}
+
+// Explain explains a path of a lib generated from a Catalog. The path starts
+// with the group, version and kind of a type, like the lib, and is followed
+// by the names of fields. (e.g. apps.v1beta2.deployment.spec.template) The
+// fields of references, of the items of lists of objects and of the values of
+// maps of objects are walked. If recursive is true, the fields of the
+// explained fields are explained too.
+func Explain(c *Catalog, path string, recursive bool) (*Explanation, error) {
+	parts := strings.Split(path, ".")
+	if len(parts) < 3 {
+		return nil, errors.Errorf("%q does not start with a group, version and kind", path)
+	}
+
+	ty, err := explainedType(c, parts[0], parts[1], parts[2])
+	if err != nil {
+		return nil, err
+	}
+
+	lib := strings.Join([]string{ty.Group(), ty.Version(), FormatKind(ty.Kind())}, ".")
+	e := &Explanation{
+		Path:        lib,
+		Name:        ty.Kind(),
+		Type:        "Object",
+		Definition:  ty.Identifier(),
+		Description: ty.Description(),
+		Lib:         lib,
+	}
+
+	scope := explainScope{lib: lib, object: ty}
+	for _, name := range parts[3:] {
+		if e.Definition == "" {
+			return nil, errors.Errorf("%s does not have fields", e.Path)
+		}
+
+		if _, ok := scope.object.Properties()[name]; !ok {
+			return nil, errors.Errorf("%s does not have a field named %q", e.Path, name)
+		}
+
+		fields, err := scope.render(c)
+		if err != nil {
+			return nil, err
+		}
+
+		field, err := explainProperty(e.Path, scope, fields[name])
+		if err != nil {
+			return nil, err
+		}
+		e = &field
+
+		if e.Definition == "" {
+			continue
+		}
+
+		scope, err = scope.field(c, fields[name].prop, e.Lib)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	e.Recursive = recursive
+	if e.Definition == "" {
+		return e, nil
+	}
+
+	seen := map[string]bool{e.Definition: true}
+	e.Fields, err = explainFields(c, e.Path, scope, recursive, seen)
+	if err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+// explainedType returns the type of a group, version and kind of the lib.
+// Kinds are matched like they are formatted in the lib.
+func explainedType(c *Catalog, group, version, kind string) (*Type, error) {
+	types, err := c.Types()
+	if err != nil {
+		return nil, errors.Wrap(err, "retrieve types")
+	}
+
+	for i := range types {
+		ty := &types[i]
+		if ty.Group() == group && ty.Version() == version && FormatKind(ty.Kind()) == FormatKind(kind) {
+			return ty, nil
+		}
+	}
+
+	return nil, errors.Errorf("unable to find %s.%s.%s", group, version, kind)
+}
+
+// explainScope is an object of the lib which contains the setters of the
+// properties of an object.
+type explainScope struct {
+	// lib is the path of the object in the lib.
+	lib    string
+	object Object
+	// name is the name of the reference the object is the mixin of, and
+	// parent the name of the reference that one is in. name is blank for
+	// the root of a type or type alias, which sets references in its mixin.
+	name, parent string
+}
+
+// render renders the properties of the object like the lib does, so
+// identifiers which were renamed because they collide are explained as they
+// are generated. The rendered fields are returned by the name of their
+// property.
+func (s explainScope) render(c *Catalog) (map[string]*renderedField, error) {
+	parent := nm.NewObject()
+	if s.name == "" {
+		o, err := NewAPIObject(s.object).initNode(c)
+		if err != nil {
+			return nil, err
+		}
+		parent = o
+	} else if err := mixinPreamble(parent, s.parent, s.name); err != nil {
+		return nil, err
+	}
+
+	// the collisions were recorded when the lib was generated.
+	tl := &collisionLog{typeLookup: c}
+	_, fields, err := renderFieldKeys(tl, parent, s.name, s.object.Identifier(), s.object.Properties())
+	if err != nil {
+		return nil, errors.Wrapf(err, "render fields of %s", s.object.Identifier())
+	}
+
+	out := make(map[string]*renderedField)
+	for _, rf := range fields {
+		out[rf.prop.Name()] = rf
+	}
+
+	return out, nil
+}
+
+// field returns the scope of the definition of a property whose setters are
+// in the object at lib.
+func (s explainScope) field(c *Catalog, p Property, lib string) (explainScope, error) {
+	ref := p.Ref()
+	if lf, ok := p.(*LiteralField); ok {
+		if mv := lf.MapValue(); mv != nil && mv.Ref != "" {
+			ref = mv.Ref
+		}
+	}
+
+	o, err := c.find(ref)
+	if err != nil {
+		return explainScope{}, err
+	}
+
+	// the fields of references are set in the reference's mixin, and the
+	// fields of type aliases like the types they refer to.
+	if _, ok := p.(*ReferenceField); ok {
+		return explainScope{lib: lib, object: o, name: p.Name(), parent: s.name}, nil
+	}
+
+	return explainScope{lib: lib, object: o}, nil
+}
+
+// explainFields explains the properties of the object of a scope. seen
+// contains the definitions being explained, so recursive definitions are
+// only explained once.
+func explainFields(c *Catalog, path string, scope explainScope, recursive bool, seen map[string]bool) ([]Explanation, error) {
+	fields, err := scope.render(c)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var out []Explanation
+	for _, name := range names {
+		rf := fields[name]
+
+		e, err := explainProperty(path, scope, rf)
+		if err != nil {
+			return nil, err
+		}
+
+		if recursive && e.Definition != "" && !seen[e.Definition] {
+			fs, err := scope.field(c, rf.prop, e.Lib)
+			if err != nil {
+				return nil, err
+			}
+
+			seen[e.Definition] = true
+			e.Fields, err = explainFields(c, e.Path, fs, recursive, seen)
+			delete(seen, e.Definition)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		out = append(out, e)
+	}
+
+	return out, nil
+}
+
+// explainProperty explains a property of the object of a scope with the
+// keys it was rendered with.
+func explainProperty(path string, scope explainScope, rf *renderedField) (Explanation, error) {
+	p := rf.prop
+	id, _ := propertyIdentifiers(p)
+
+	e := Explanation{
+		Path:        path + "." + p.Name(),
+		Name:        p.Name(),
+		Description: p.Description(),
+	}
+
+	lib := scope.lib
+	switch t := p.(type) {
+	case *ReferenceField:
+		if scope.name == "" {
+			lib += ".mixin"
+		}
+
+		e.Type = "Object"
+		e.Definition = t.Ref()
+	case *LiteralField:
+		e.Type = explainLiteralType(t)
+		e.Definition = t.Ref()
+		if mv := t.MapValue(); mv != nil && mv.Ref != "" {
+			e.Definition = mv.Ref
+		}
+	default:
+		return Explanation{}, errors.Errorf("unknown field type %T", t)
+	}
+
+	// the fields of a reference are set in its mixin, and the fields of
+	// other definitions with the field's type alias.
+	object := id
+	if _, ok := p.(*ReferenceField); !ok {
+		object = typeAliasName(p.Name())
+	}
+
+	secondary := secondaryKeys(p)
+	for _, name := range rf.names() {
+		switch {
+		case name == object && e.Definition != "":
+			e.Lib = lib + "." + name
+		case name == fieldName(id, false):
+			e.Setter = lib + "." + name
+		case name == fieldName(id, true):
+			e.Mixin = lib + "." + name
+		case !secondary[name] && name != object:
+			e.Functions = append(e.Functions, explainedFunctions(lib+"."+name, rf.keys.Get(name))...)
+		}
+	}
+
+	return e, nil
+}
+
+// explainedFunctions returns the paths of the functions of a key. Keys which
+// are objects, like the setters of the quantities of a map, have the
+// functions of the object.
+func explainedFunctions(path string, value nm.Noder) []string {
+	o, ok := value.(*nm.Object)
+	if !ok {
+		return []string{path}
+	}
+
+	var out []string
+	for _, key := range o.Keys() {
+		out = append(out, path+"."+key.Name())
+	}
+
+	return out
+}
+
+// explainLiteralType returns the type of a literal field in the style of
+// `kubectl explain`.
+func explainLiteralType(f *LiteralField) string {
+	switch f.FieldType() {
+	case "array":
+		if f.Ref() != "" {
+			return "[]Object"
+		}
+		return "array"
+	case "object":
+		if f.Ref() != "" {
+			return "Object"
+		}
+
+		if mv := f.MapValue(); mv != nil {
+			switch {
+			case mv.Ref != "":
+				return "map[string]Object"
+			case mv.Type != "":
+				return "map[string]" + mv.Type
+			}
+		}
+
+		return "object"
+	}
+
+	// only the formats the setters check are described.
+	switch f.Format() {
+	case formatIntOrString, formatQuantity, formatDateTime:
+		return fmt.Sprintf("%s (%s)", f.FieldType(), f.Format())
+	}
+
+	return f.FieldType()
+}
+
+// WriteExplanation writes an explanation in the style of `kubectl explain`.
+// The fields of a recursive explanation are written as a tree of their names
+// and types, and the fields of other explanations with their setters and
+// descriptions.
+func WriteExplanation(w io.Writer, e *Explanation) error {
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf, "PATH:       %s\n", e.Path)
+	fmt.Fprintf(&buf, "TYPE:       <%s>", e.Type)
+	if e.Definition != "" {
+		fmt.Fprintf(&buf, " %s", e.Definition)
+	}
+	buf.WriteString("\n")
+
+	for _, line := range []struct{ label, value string }{
+		{"LIB:        ", e.Lib},
+		{"SETTER:     ", e.Setter},
+		{"MIXIN:      ", e.Mixin},
+	} {
+		if line.value != "" {
+			fmt.Fprintf(&buf, "%s%s\n", line.label, line.value)
+		}
+	}
+
+	for i, fn := range e.Functions {
+		label := "            "
+		if i == 0 {
+			label = "FUNCTIONS:  "
+		}
+		fmt.Fprintf(&buf, "%s%s\n", label, fn)
+	}
+
+	if e.Description != "" {
+		buf.WriteString("\nDESCRIPTION:\n")
+		writeWrapped(&buf, "     ", e.Description)
+	}
+
+	if len(e.Fields) > 0 {
+		buf.WriteString("\nFIELDS:\n")
+		if e.Recursive {
+			writeFieldTree(&buf, "   ", e.Fields)
+		} else {
+			writeFieldList(&buf, e.Lib, e.Fields)
+		}
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// writeFieldList writes fields with their descriptions and the functions of
+// the lib which set them. Functions are written relative to lib, the object
+// of the fields' setters.
+func writeFieldList(buf *bytes.Buffer, lib string, fields []Explanation) {
+	for i, f := range fields {
+		if i > 0 {
+			buf.WriteString("\n")
+		}
+
+		fmt.Fprintf(buf, "   %s\t<%s>\n", f.Name, f.Type)
+
+		var names []string
+		for _, fn := range []string{f.Setter, f.Mixin} {
+			if fn != "" {
+				names = append(names, fn[strings.LastIndex(fn, ".")+1:])
+			}
+		}
+		for _, fn := range f.Functions {
+			names = append(names, strings.TrimPrefix(fn, lib+"."))
+		}
+		if f.Lib != "" {
+			names = append(names, f.Lib[strings.LastIndex(f.Lib, ".")+1:])
+		}
+		if len(names) > 0 {
+			writeWrapped(buf, "     ", "["+strings.Join(names, ", ")+"]")
+		}
+
+		writeWrapped(buf, "     ", f.Description)
+	}
+}
+
+func writeFieldTree(buf *bytes.Buffer, indent string, fields []Explanation) {
+	for _, f := range fields {
+		fmt.Fprintf(buf, "%s%s\t<%s>\n", indent, f.Name, f.Type)
+		writeFieldTree(buf, indent+"   ", f.Fields)
+	}
+}
+
+// writeWrapped writes text wrapped to explainWidth, with each line indented.
+func writeWrapped(buf *bytes.Buffer, indent, text string) {
+	line := indent
+	for _, word := range strings.Fields(text) {
+		if line != indent && len(line)+1+len(word) > explainWidth {
+			buf.WriteString(line + "\n")
+			line = indent
+		}
+
+		if line != indent {
+			line += " "
+		}
+		line += word
+	}
+
+	if line != indent {
+		buf.WriteString(line + "\n")
+	}
+}
@@ -0,0 +1,350 @@
+package ksonnet
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	jsonnet "github.com/google/go-jsonnet"
+	"github.com/ksonnet/ksonnet-lib/ksonnet-gen/kubespec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func explainCatalog(t *testing.T) *Catalog {
+	apiSpec, checksum, err := kubespec.Import("testdata/swagger-1.8.json")
+	require.NoError(t, err)
+
+	c, err := NewCatalog(apiSpec, CatalogOptChecksum(checksum))
+	require.NoError(t, err)
+
+	return c
+}
+
+func TestExplain(t *testing.T) {
+	c := explainCatalog(t)
+
+	cases := []struct {
+		name     string
+		path     string
+		expected Explanation
+		fields   []string
+		isErr    bool
+	}{
+		{
+			name: "type",
+			path: "apps.v1beta2.Deployment",
+			expected: Explanation{
+				Path:       "apps.v1beta2.deployment",
+				Name:       "Deployment",
+				Type:       "Object",
+				Definition: "io.k8s.api.apps.v1beta2.Deployment",
+				Lib:        "apps.v1beta2.deployment",
+			},
+			fields: []string{"metadata", "spec"},
+		},
+		{
+			name: "reference",
+			path: "apps.v1beta2.deployment.spec",
+			expected: Explanation{
+				Path:       "apps.v1beta2.deployment.spec",
+				Name:       "spec",
+				Type:       "Object",
+				Definition: "io.k8s.api.apps.v1beta2.DeploymentSpec",
+				Lib:        "apps.v1beta2.deployment.mixin.spec",
+			},
+		},
+		{
+			name: "literal",
+			path: "apps.v1beta2.deployment.spec.replicas",
+			expected: Explanation{
+				Path:   "apps.v1beta2.deployment.spec.replicas",
+				Name:   "replicas",
+				Type:   "integer",
+				Setter: "apps.v1beta2.deployment.mixin.spec.withReplicas",
+			},
+		},
+		{
+			name: "list of objects",
+			path: "apps.v1beta2.deployment.spec.template.spec.containers",
+			expected: Explanation{
+				Path:       "apps.v1beta2.deployment.spec.template.spec.containers",
+				Name:       "containers",
+				Type:       "[]Object",
+				Definition: "io.k8s.api.core.v1.Container",
+				Lib:        "apps.v1beta2.deployment.mixin.spec.template.spec.containersType",
+				Setter:     "apps.v1beta2.deployment.mixin.spec.template.spec.withContainers",
+				Mixin:      "apps.v1beta2.deployment.mixin.spec.template.spec.withContainersMixin",
+				Functions: []string{
+					"apps.v1beta2.deployment.mixin.spec.template.spec.withContainerByName",
+					"apps.v1beta2.deployment.mixin.spec.template.spec.upsertContainer",
+					"apps.v1beta2.deployment.mixin.spec.template.spec.removeContainer",
+				},
+			},
+		},
+		{
+			name: "field of list item",
+			path: "apps.v1beta2.deployment.spec.template.spec.containers.resources",
+			expected: Explanation{
+				Path:       "apps.v1beta2.deployment.spec.template.spec.containers.resources",
+				Name:       "resources",
+				Type:       "Object",
+				Definition: "io.k8s.api.core.v1.ResourceRequirements",
+				Lib:        "apps.v1beta2.deployment.mixin.spec.template.spec.containersType.mixin.resources",
+			},
+		},
+		{
+			name: "map",
+			path: "core.v1.configMap.data",
+			expected: Explanation{
+				Path:   "core.v1.configMap.data",
+				Name:   "data",
+				Type:   "map[string]string",
+				Setter: "core.v1.configMap.withData",
+				Mixin:  "core.v1.configMap.withDataMixin",
+				Functions: []string{
+					"core.v1.configMap.withDataEntry",
+					"core.v1.configMap.withoutData",
+				},
+			},
+		},
+		{
+			name: "map of quantities",
+			path: "apps.v1beta2.deployment.spec.template.spec.containers.resources.limits",
+			expected: Explanation{
+				Path:   "apps.v1beta2.deployment.spec.template.spec.containers.resources.limits",
+				Name:   "limits",
+				Type:   "map[string]string",
+				Setter: "apps.v1beta2.deployment.mixin.spec.template.spec.containersType.mixin.resources.withLimits",
+				Mixin:  "apps.v1beta2.deployment.mixin.spec.template.spec.containersType.mixin.resources.withLimitsMixin",
+				Functions: []string{
+					"apps.v1beta2.deployment.mixin.spec.template.spec.containersType.mixin.resources.withLimitsEntry",
+					"apps.v1beta2.deployment.mixin.spec.template.spec.containersType.mixin.resources.withoutLimits",
+					"apps.v1beta2.deployment.mixin.spec.template.spec.containersType.mixin.resources.limits.withCpu",
+					"apps.v1beta2.deployment.mixin.spec.template.spec.containersType.mixin.resources.limits.withMemory",
+					"apps.v1beta2.deployment.mixin.spec.template.spec.containersType.mixin.resources.limits.withStorage",
+					"apps.v1beta2.deployment.mixin.spec.template.spec.containersType.mixin.resources.limits.withEphemeralStorage",
+				},
+			},
+		},
+		{
+			name:  "short path",
+			path:  "apps.v1beta2",
+			isErr: true,
+		},
+		{
+			name:  "unknown kind",
+			path:  "apps.v1beta2.pod",
+			isErr: true,
+		},
+		{
+			name:  "unknown field",
+			path:  "apps.v1beta2.deployment.spec.nope",
+			isErr: true,
+		},
+		{
+			name:  "field of literal",
+			path:  "apps.v1beta2.deployment.spec.replicas.value",
+			isErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			e, err := Explain(c, tc.path, false)
+			if tc.isErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.NotEmpty(t, e.Description)
+
+			var fields []string
+			for _, f := range e.Fields {
+				fields = append(fields, f.Name)
+				assert.Empty(t, f.Fields)
+			}
+			if tc.fields != nil {
+				assert.Equal(t, tc.fields, fields)
+			}
+
+			e.Description = ""
+			e.Fields = nil
+			assert.Equal(t, tc.expected, *e)
+		})
+	}
+}
+
+func TestExplain_recursive(t *testing.T) {
+	c := explainCatalog(t)
+
+	e, err := Explain(c, "apps.v1beta2.deployment.spec.template.spec.containers", true)
+	require.NoError(t, err)
+	require.True(t, e.Recursive)
+
+	var names []string
+	var paths []string
+	var walk func(fields []Explanation)
+	walk = func(fields []Explanation) {
+		for _, f := range fields {
+			names = append(names, strings.TrimPrefix(f.Path, e.Path+"."))
+			for _, p := range append([]string{f.Lib, f.Setter, f.Mixin}, f.Functions...) {
+				if p != "" {
+					paths = append(paths, p)
+				}
+			}
+			walk(f.Fields)
+		}
+	}
+	walk(e.Fields)
+
+	assert.Contains(t, names, "env.valueFrom.configMapKeyRef.key")
+	assert.Contains(t, names, "resources.limits")
+
+	// every path of the explanation is in the lib.
+	lib, err := GenerateLib("testdata/swagger-1.8.json")
+	require.NoError(t, err)
+
+	vm := jsonnet.MakeVM()
+	vm.Importer(&jsonnet.MemoryImporter{
+		Data: map[string]string{"k8s.libsonnet": string(lib.K8s)},
+	})
+
+	var checks []string
+	for _, p := range paths {
+		checks = append(checks, "std.type(k8s."+p+") != null")
+	}
+	snippet := "local k8s = import 'k8s.libsonnet';\n[" + strings.Join(checks, ",\n") + "]"
+
+	_, err = vm.EvaluateSnippet("snippet", snippet)
+	require.NoError(t, err)
+}
+
+func TestExplain_collisions(t *testing.T) {
+	c := initCatalog(t, "collisions.json")
+
+	e, err := Explain(c, "example.v1.widget", false)
+	require.NoError(t, err)
+
+	explained := make(map[string]Explanation)
+	for _, f := range e.Fields {
+		explained[f.Name] = f
+	}
+
+	// renamed identifiers are explained as they are generated.
+	assert.Equal(t, "example.v1.widget.withDollarRef2", explained["$ref"].Setter)
+	assert.Equal(t, "example.v1.widget.withDollarRef", explained["dollarRef"].Setter)
+	assert.Equal(t, "example.v1.widget.withHostIpc2", explained["hostIPC"].Setter)
+	assert.Equal(t, "example.v1.widget.mixin.spec", explained["spec"].Lib)
+	assert.Equal(t, "example.v1.widget.mixin.specType", explained["specType"].Lib)
+
+	e, err = Explain(c, "example.v1.widget.spec.mixinInstance", false)
+	require.NoError(t, err)
+	assert.Equal(t, "example.v1.widget.mixin.spec.mixinInstance2", e.Lib)
+
+	// the explained paths are in the lib.
+	lib, err := GenerateLib("testdata/collisions.json")
+	require.NoError(t, err)
+
+	vm := jsonnet.MakeVM()
+	vm.Importer(&jsonnet.MemoryImporter{
+		Data: map[string]string{"k8s.libsonnet": string(lib.K8s)},
+	})
+
+	snippet := `local k8s = import 'k8s.libsonnet';
+[std.type(k8s.example.v1.widget.withDollarRef2), std.type(k8s.example.v1.widget.mixin.spec.mixinInstance2)]`
+	_, err = vm.EvaluateSnippet("snippet", snippet)
+	require.NoError(t, err)
+}
+
+func TestWriteExplanation(t *testing.T) {
+	e := &Explanation{
+		Path:        "apps.v1beta2.deployment.spec.template.spec.containers",
+		Type:        "[]Object",
+		Definition:  "io.k8s.api.core.v1.Container",
+		Description: "List of containers belonging to the pod.",
+		Lib:         "apps.v1beta2.deployment.mixin.spec.template.spec.containersType",
+		Setter:      "apps.v1beta2.deployment.mixin.spec.template.spec.withContainers",
+		Mixin:       "apps.v1beta2.deployment.mixin.spec.template.spec.withContainersMixin",
+		Functions: []string{
+			"apps.v1beta2.deployment.mixin.spec.template.spec.withContainerByName",
+			"apps.v1beta2.deployment.mixin.spec.template.spec.upsertContainer",
+		},
+		Fields: []Explanation{
+			{
+				Name:        "image",
+				Type:        "string",
+				Description: "Docker image name.",
+				Setter:      "apps.v1beta2.deployment.mixin.spec.template.spec.containersType.withImage",
+			},
+			{
+				Name:        "resources",
+				Type:        "Object",
+				Description: "Compute Resources required by this container.",
+				Lib:         "apps.v1beta2.deployment.mixin.spec.template.spec.containersType.mixin.resources",
+				Fields: []Explanation{
+					{Name: "limits", Type: "map[string]string"},
+				},
+			},
+			{
+				Name:        "ports",
+				Type:        "[]Object",
+				Description: "List of ports to expose from the container.",
+				Setter:      "apps.v1beta2.deployment.mixin.spec.template.spec.containersType.withPorts",
+				Functions: []string{
+					"apps.v1beta2.deployment.mixin.spec.template.spec.containersType.upsertPort",
+				},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteExplanation(&buf, e))
+
+	expected := `PATH:       apps.v1beta2.deployment.spec.template.spec.containers
+TYPE:       <[]Object> io.k8s.api.core.v1.Container
+LIB:        apps.v1beta2.deployment.mixin.spec.template.spec.containersType
+SETTER:     apps.v1beta2.deployment.mixin.spec.template.spec.withContainers
+MIXIN:      apps.v1beta2.deployment.mixin.spec.template.spec.withContainersMixin
+FUNCTIONS:  apps.v1beta2.deployment.mixin.spec.template.spec.withContainerByName
+            apps.v1beta2.deployment.mixin.spec.template.spec.upsertContainer
+
+DESCRIPTION:
+     List of containers belonging to the pod.
+
+FIELDS:
+   image	<string>
+     [withImage]
+     Docker image name.
+
+   resources	<Object>
+     [resources]
+     Compute Resources required by this container.
+
+   ports	<[]Object>
+     [withPorts, upsertPort]
+     List of ports to expose from the container.
+`
+	assert.Equal(t, expected, buf.String())
+
+	e.Recursive = true
+	buf.Reset()
+	require.NoError(t, WriteExplanation(&buf, e))
+
+	assert.True(t, strings.HasSuffix(buf.String(), `FIELDS:
+   image	<string>
+   resources	<Object>
+      limits	<map[string]string>
+   ports	<[]Object>
+`))
+}
+
+func Test_writeWrapped(t *testing.T) {
+	var buf bytes.Buffer
+	writeWrapped(&buf, "  ", strings.Repeat("word ", 20))
+
+	expected := "  " + strings.TrimSpace(strings.Repeat("word ", 15)) + "\n" +
+		"  " + strings.TrimSpace(strings.Repeat("word ", 5)) + "\n"
+	assert.Equal(t, expected, buf.String())
+}
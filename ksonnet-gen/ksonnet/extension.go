@@ -13,16 +13,35 @@ const (
 	localK8s = "k8s"
 )
 
+// ExtensionOpt is an option for configuring Extension.
+type ExtensionOpt func(*Extension)
+
+// ExtensionOptHelpers is an Extension option for adding helpers to the types
+// containing the helpers' descendants.
+func ExtensionOptHelpers(helpers ...ExtensionHelper) ExtensionOpt {
+	return func(e *Extension) {
+		e.helpers = append(e.helpers, helpers...)
+	}
+}
+
 // Extension represents a ksonnet lib extension document.
 type Extension struct {
 	catalog *Catalog
+	helpers []ExtensionHelper
 }
 
 // NewExtension creates an an instance of Extension.
-func NewExtension(catalog *Catalog) *Extension {
-	return &Extension{
+func NewExtension(catalog *Catalog, opts ...ExtensionOpt) *Extension {
+	e := &Extension{
 		catalog: catalog,
+		helpers: defaultExtensionHelpers(),
 	}
+
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	return e
 }
 
 // Node converts an extension to a node.
@@ -34,10 +53,8 @@ func (e *Extension) Node() (nm.Noder, error) {
 
 	extBinary := nm.NewBinary(nm.NewVar(localK8s), ext, nm.BopPlus)
 
-	fns := genMapContainers(extBinary)
-
 	k8sImportFile := nm.NewImport("k8s.libsonnet")
-	k8sImport := nm.NewLocal(localK8s, k8sImportFile, fns)
+	k8sImport := nm.NewLocal(localK8s, k8sImportFile, extBinary)
 
 	return k8sImport, nil
 }
@@ -47,33 +64,54 @@ func (e *Extension) genK8sExtension() (*nm.Object, error) {
 
 	e.listExtension(gi)
 
-	if err := e.mapContainersExtension(gi); err != nil {
-		return nil, errors.Wrap(err, "map container extensions")
+	if err := e.helpersExtension(gi); err != nil {
+		return nil, errors.Wrap(err, "helper extensions")
 	}
 
 	return gi.Node(), nil
 }
 
-func (e *Extension) mapContainersExtension(gi *groupItems) error {
-	types, err := e.catalog.TypesWithDescendant("io.k8s.api.core.v1.PodSpec")
-	if err != nil {
-		return errors.Wrap(err, "find types with PodSec")
-	}
+func (e *Extension) helpersExtension(gi *groupItems) error {
+	var names []string
+	mappings := make(map[string]*nm.Object)
+	descendants := make(map[string][]Type)
+
+	for _, helper := range e.helpers {
+		types, ok := descendants[helper.Descendant]
+		if !ok {
+			var err error
+			types, err = e.catalog.TypesWithDescendant(helper.Descendant)
+			if err != nil {
+				return errors.Wrapf(err, "find types with %s", helper.Descendant)
+			}
+			descendants[helper.Descendant] = types
+		}
 
-	mapping := nm.NewObject()
-	mapping.Set(
-		nm.FunctionKey("mapContainers", []string{"f"}),
-		nm.ApplyCall("fn.mapContainers", nm.NewVar("f")),
-	)
+		for _, ty := range types {
+			path, err := e.catalog.DescendantPath(ty, helper.Descendant)
+			if err != nil {
+				return errors.Wrapf(err, "find path to %s", helper.Descendant)
+			}
 
-	mapping.Set(
-		nm.FunctionKey("mapContainersWithName", []string{"names", "f"}),
-		nm.ApplyCall("fn.mapContainersWithName", nm.NewVar("names"), nm.NewVar("f")),
-	)
+			if path == nil {
+				continue
+			}
 
-	for _, ty := range types {
-		parts := strings.Split(ty.component.String(), ".")
-		gi.add(parts[0], parts[1], FormatKind(parts[2]), mapping, true)
+			name := ty.component.String()
+			mapping, ok := mappings[name]
+			if !ok {
+				mapping = nm.NewObject()
+				mappings[name] = mapping
+				names = append(names, name)
+			}
+
+			mapping.Set(nm.FunctionKey(helper.Name, helper.Params), helper.Body(path))
+		}
+	}
+
+	for _, name := range names {
+		parts := strings.Split(name, ".")
+		gi.add(parts[0], parts[1], FormatKind(parts[2]), mappings[name], true)
 	}
 
 	return nil
@@ -183,55 +221,8 @@ func (gi *groupItems) Node() *nm.Object {
 	return o
 }
 
-func genMapContainers(body nm.Noder) *nm.Local {
-	o := nm.NewObject()
-
-	o.Set(
-		nm.FunctionKey("mapContainers", []string{"f"}),
-		createMapContainersFn(),
-	)
-
-	o.Set(
-		nm.FunctionKey("mapContainersWithName", []string{"names", "f"}),
-		createMapContainersWithName(),
-	)
-
-	return nm.NewLocal("fn", o, body)
-}
-
-func createMapContainersFn() *nm.Object {
-	o := nm.NewObject()
-	o.Set(
-		nm.LocalKey("podContainers"),
-		nm.NewCall("super.spec.template.spec.containers"),
-	)
-
-	templateSpecObject := nm.NewObject()
-	templateSpecObject.Set(
-		nm.InheritedKey("containers"),
-		nm.ApplyCall("std.map", nm.NewVar("f"), nm.NewVar("podContainers")),
-	)
-
-	templateObject := nm.NewObject()
-	templateObject.Set(
-		nm.InheritedKey("spec", nm.KeyOptMixin(true)),
-		templateSpecObject,
-	)
-
-	specObject := nm.NewObject()
-	specObject.Set(
-		nm.InheritedKey("template", nm.KeyOptMixin(true)),
-		templateObject,
-	)
-
-	o.Set(
-		nm.InheritedKey("spec", nm.KeyOptMixin(true)),
-		specObject,
-	)
-
-	return o
-}
-
+// createMapContainersWithName creates a function body which maps f over the
+// containers with one of the names, using the type's mapContainers.
 func createMapContainersWithName() *nm.Local {
 	c1Binary := nm.NewBinary(
 		nm.ApplyCall("std.objectHas", nm.NewVar("c"), nm.NewStringDouble("name")),
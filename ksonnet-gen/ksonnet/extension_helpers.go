@@ -0,0 +1,125 @@
+package ksonnet
+
+import (
+	nm "github.com/ksonnet/ksonnet-lib/ksonnet-gen/nodemaker"
+)
+
+const (
+	podSpecDefinition = "io.k8s.api.core.v1.PodSpec"
+)
+
+// ExtensionHelper is a function added to every type containing a descendant
+// definition. Its body is created from the property path from the type to the
+// descendant, so the same helper works for a Pod, a Deployment or a CronJob.
+type ExtensionHelper struct {
+	// Name is the name of the function.
+	Name string
+	// Params are the parameters of the function.
+	Params []string
+	// Descendant is the definition the function operates on. (e.g. io.k8s.api.core.v1.PodSpec)
+	Descendant string
+	// Body creates the body of the function from the path to the descendant.
+	Body func(path []string) nm.Noder
+}
+
+// defaultExtensionHelpers are the helpers added to the extension document.
+func defaultExtensionHelpers() []ExtensionHelper {
+	return []ExtensionHelper{
+		{
+			Name:       "mapContainers",
+			Params:     []string{"f"},
+			Descendant: podSpecDefinition,
+			Body: func(path []string) nm.Noder {
+				return nestedMixin(path, mapListField("containers", nm.NewVar("f")))
+			},
+		},
+		{
+			Name:       "mapContainersWithName",
+			Params:     []string{"names", "f"},
+			Descendant: podSpecDefinition,
+			Body: func([]string) nm.Noder {
+				return createMapContainersWithName()
+			},
+		},
+		{
+			Name:       "mapInitContainers",
+			Params:     []string{"f"},
+			Descendant: podSpecDefinition,
+			Body: func(path []string) nm.Noder {
+				return nestedMixin(path, mapListField("initContainers", nm.NewVar("f")))
+			},
+		},
+		{
+			Name:       "mapVolumes",
+			Params:     []string{"f"},
+			Descendant: podSpecDefinition,
+			Body: func(path []string) nm.Noder {
+				return nestedMixin(path, mapListField("volumes", nm.NewVar("f")))
+			},
+		},
+		{
+			Name:       "mapEnv",
+			Params:     []string{"f"},
+			Descendant: podSpecDefinition,
+			Body: func(path []string) nm.Noder {
+				return nestedMixin(path, mapListField("containers", mapEnvFn()))
+			},
+		},
+		{
+			Name:       "withPodAnnotations",
+			Params:     []string{"annotations"},
+			Descendant: podSpecDefinition,
+			Body: func(path []string) nm.Noder {
+				// the pod's metadata is a sibling of its spec.
+				metadata := append(append([]string{}, path[:len(path)-1]...), "metadata")
+
+				o := nm.OnelineObject()
+				o.Set(nm.InheritedKey("annotations", nm.KeyOptMixin(true)), nm.NewVar("annotations"))
+
+				return nestedMixin(metadata, o)
+			},
+		},
+	}
+}
+
+// nestedMixin creates an object which mixes leaf into the path. e.g.
+// `{ spec+: { template+: { spec+: leaf } } }`
+func nestedMixin(path []string, leaf nm.Noder) nm.Noder {
+	node := leaf
+	for i := len(path) - 1; i >= 0; i-- {
+		o := nm.OnelineObject()
+		o.Set(nm.InheritedKey(path[i], nm.KeyOptMixin(true)), node)
+		node = o
+	}
+
+	return node
+}
+
+// mapListField creates an object which maps f over an inherited list field. e.g.
+// `{ volumes: if 'volumes' in super then std.map(f, super.volumes) else [] }`
+func mapListField(name string, f nm.Noder) *nm.Object {
+	value := nm.NewConditional(
+		nm.NewInSuper(nm.NewStringDouble(name)),
+		nm.ApplyCall("std.map", f, nm.NewCall("super."+name)),
+		nm.NewArray(nil))
+
+	o := nm.OnelineObject()
+	o.Set(nm.InheritedKey(name), value)
+
+	return o
+}
+
+// mapEnvFn creates a function which maps f over the environment of a container.
+// Containers without an environment are left as is.
+func mapEnvFn() nm.Noder {
+	env := nm.OnelineObject()
+	env.Set(nm.InheritedKey("env"), nm.ApplyCall("std.map", nm.NewVar("f"), nm.NewCall("c.env")))
+
+	return nm.NewFunction([]string{"c"}, nm.NewBinary(
+		nm.NewVar("c"),
+		nm.NewConditional(
+			nm.ApplyCall("std.objectHas", nm.NewVar("c"), nm.NewStringDouble("env")),
+			env,
+			nm.OnelineObject()),
+		nm.BopPlus))
+}
@@ -1,9 +1,15 @@
 package ksonnet
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
 	"io/ioutil"
+	"strings"
 	"testing"
 
+	jsonnet "github.com/google/go-jsonnet"
+	nm "github.com/ksonnet/ksonnet-lib/ksonnet-gen/nodemaker"
 	"github.com/ksonnet/ksonnet-lib/ksonnet-gen/printer"
 	"github.com/stretchr/testify/require"
 )
@@ -18,3 +24,116 @@ func TestExtension_Output(t *testing.T) {
 
 	require.NoError(t, printer.Fprint(ioutil.Discard, node.Node()))
 }
+
+func TestExtension_helpers(t *testing.T) {
+	c := initCatalog(t, "swagger-1.8.json")
+
+	helper := ExtensionHelper{
+		Name:       "podSpecPath",
+		Descendant: "io.k8s.api.core.v1.PodSpec",
+		Body: func(path []string) nm.Noder {
+			return nm.NewStringDouble(strings.Join(path, "."))
+		},
+	}
+
+	e := NewExtension(c, ExtensionOptHelpers(helper))
+
+	gi := makeGroupItems()
+	require.NoError(t, e.helpersExtension(gi))
+
+	cases := []struct {
+		group    string
+		version  string
+		kind     string
+		expected string
+	}{
+		{group: "core", version: "v1", kind: "pod", expected: "spec"},
+		{group: "apps", version: "v1beta2", kind: "deployment", expected: "spec.template.spec"},
+		{group: "batch", version: "v1", kind: "job", expected: "spec.template.spec"},
+		{group: "batch", version: "v1beta1", kind: "cronJob", expected: "spec.jobTemplate.spec.template.spec"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.kind, func(t *testing.T) {
+			mapping, ok := gi.groups[tc.group][tc.version][tc.kind].node.(*nm.Object)
+			require.True(t, ok)
+
+			require.Equal(t, nm.NewStringDouble(tc.expected), mapping.Get("podSpecPath"))
+
+			for _, name := range []string{"mapContainers", "mapInitContainers", "mapVolumes", "mapEnv", "withPodAnnotations"} {
+				require.NotNil(t, mapping.Get(name), "%s was not generated", name)
+			}
+		})
+	}
+}
+
+func Test_nestedMixin(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, printer.Fprint(&buf, nestedMixin([]string{"spec", "template"}, nm.NewVar("leaf")).Node()))
+	require.Equal(t, "{ spec+: { template+: leaf } }", buf.String())
+}
+
+func TestExtension_mapContainers(t *testing.T) {
+	lib, err := GenerateLib("testdata/swagger-1.8.json")
+	require.NoError(t, err)
+
+	cases := []struct {
+		kind string
+		path []string
+	}{
+		{kind: "apps.v1beta1.deployment", path: []string{"spec", "template", "spec"}},
+		{kind: "apps.v1beta1.statefulSet", path: []string{"spec", "template", "spec"}},
+		{kind: "apps.v1beta2.daemonSet", path: []string{"spec", "template", "spec"}},
+		{kind: "apps.v1beta2.deployment", path: []string{"spec", "template", "spec"}},
+		{kind: "apps.v1beta2.replicaSet", path: []string{"spec", "template", "spec"}},
+		{kind: "apps.v1beta2.statefulSet", path: []string{"spec", "template", "spec"}},
+		{kind: "batch.v1.job", path: []string{"spec", "template", "spec"}},
+		{kind: "batch.v1beta1.cronJob", path: []string{"spec", "jobTemplate", "spec", "template", "spec"}},
+		{kind: "batch.v2alpha1.cronJob", path: []string{"spec", "jobTemplate", "spec", "template", "spec"}},
+		{kind: "core.v1.pod", path: []string{"spec"}},
+		{kind: "core.v1.podTemplate", path: []string{"template", "spec"}},
+		{kind: "core.v1.replicationController", path: []string{"spec", "template", "spec"}},
+		{kind: "extensions.v1beta1.daemonSet", path: []string{"spec", "template", "spec"}},
+		{kind: "extensions.v1beta1.deployment", path: []string{"spec", "template", "spec"}},
+		{kind: "extensions.v1beta1.replicaSet", path: []string{"spec", "template", "spec"}},
+	}
+
+	vm := jsonnet.MakeVM()
+	vm.Importer(&jsonnet.MemoryImporter{
+		Data: map[string]string{
+			"k8s.libsonnet": string(lib.K8s),
+			"k.libsonnet":   string(lib.Extensions),
+		},
+	})
+
+	for _, tc := range cases {
+		t.Run(tc.kind, func(t *testing.T) {
+			path, err := json.Marshal(tc.path)
+			require.NoError(t, err)
+
+			snippet := fmt.Sprintf(`
+local k = import 'k.libsonnet';
+local kind = k.%s;
+local path = %s;
+local containers = [{ name: 'app', image: 'a' }, { name: 'side', image: 's' }];
+local base = std.foldr(function(key, o) { [key]: o }, path, { containers: containers });
+local get(o) = std.foldl(function(o, key) o[key], path, o).containers;
+local update(c) = c + { image: c.image + '2' };
+{
+  all: get(base + kind.mapContainers(update)),
+  named: get(base + kind.mapContainersWithName(['side'], update)),
+}`, tc.kind, path)
+
+			got, err := vm.EvaluateSnippet("map_containers.jsonnet", snippet)
+			require.NoError(t, err)
+
+			expected := `{
+  all: [{ name: 'app', image: 'a2' }, { name: 'side', image: 's2' }],
+  named: [{ name: 'app', image: 'a' }, { name: 'side', image: 's2' }],
+}`
+			want, err := vm.EvaluateSnippet("expected.jsonnet", expected)
+			require.NoError(t, err)
+			require.Equal(t, want, got)
+		})
+	}
+}
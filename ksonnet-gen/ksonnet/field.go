@@ -2,13 +2,14 @@ package ksonnet
 
 // Field is a Kubernetes field.
 type Field struct {
-	kind        string
-	description string
-	properties  map[string]Property
-	version     string
-	group       string
-	codebase    string
-	identifier  string
+	kind           string
+	description    string
+	properties     map[string]Property
+	version        string
+	group          string
+	qualifiedGroup string
+	codebase       string
+	identifier     string
 }
 
 var _ Object = (*Field)(nil)
@@ -50,8 +51,13 @@ func (f *Field) Group() string {
 	return f.group
 }
 
-// QualifiedGroup is the group for this field.
+// QualifiedGroup is the group for this field. It is the fully qualified
+// group if it is known.
 func (f *Field) QualifiedGroup() string {
+	if f.qualifiedGroup != "" {
+		return f.qualifiedGroup
+	}
+
 	return f.Group()
 }
 
@@ -0,0 +1,376 @@
+package ksonnet
+
+import (
+	nm "github.com/ksonnet/ksonnet-lib/ksonnet-gen/nodemaker"
+)
+
+const (
+	formatIntOrString = "int-or-string"
+	formatQuantity    = "quantity"
+	formatDateTime    = "date-time"
+
+	// localFormats is the name of the local containing the format helpers.
+	localFormats = "formats"
+)
+
+var (
+	// formatDefinitions are definitions which are serialized as strings, but
+	// do not declare a format in the swagger schema.
+	formatDefinitions = map[string]string{
+		"io.k8s.apimachinery.pkg.api.resource.Quantity": formatQuantity,
+	}
+
+	// quantitySuffixes are the binary and decimal SI suffixes allowed in a quantity.
+	quantitySuffixes = []string{"Ki", "Mi", "Gi", "Ti", "Pi", "Ei", "m", "k", "M", "G", "T", "P", "E"}
+)
+
+// formatHelpers creates the object containing the functions formatted setters
+// use to check their values. A check returns the (possibly normalized) value or
+// raises an error naming the field.
+func formatHelpers() *nm.Object {
+	o := nm.NewObject()
+
+	o.Set(
+		nm.FunctionKey("intOrString", []string{"name", "value"}),
+		nm.NewConditional(
+			nm.NewBinary(isStdType("value", "number"), isStdType("value", "string"), nm.BopOr),
+			nm.NewVar("value"),
+			formatError(" must be a number or a string"),
+		),
+	)
+
+	o.Set(
+		nm.FunctionKey("quantity", []string{"name", "value"}),
+		nm.NewConditional(
+			isStdType("value", "number"),
+			nm.ApplyCall("std.toString", nm.NewVar("value")),
+			nm.NewConditional(
+				nm.NewBinary(
+					isStdType("value", "string"),
+					nm.ApplyCall("self.isQuantity", nm.NewVar("value")),
+					nm.BopAnd),
+				nm.NewVar("value"),
+				formatError(" must be a quantity (e.g. 512Mi or 0.5)"),
+			),
+		),
+	)
+
+	o.Set(
+		nm.FunctionKey("time", []string{"name", "value"}),
+		nm.NewConditional(
+			nm.NewBinary(
+				isStdType("value", "string"),
+				nm.ApplyCall("self.isTime", nm.NewVar("value")),
+				nm.BopAnd),
+			nm.NewVar("value"),
+			formatError(" must be a RFC 3339 date-time string (e.g. 2018-01-01T00:00:00Z)"),
+		),
+	)
+
+	o.Set(
+		nm.FunctionKey("typed", []string{"name", "type", "value"}),
+		nm.NewConditional(
+			nm.NewBinary(nm.ApplyCall("std.type", nm.NewVar("value")), nm.NewVar("type"), nm.BopEqual),
+			nm.NewVar("value"),
+			nm.NewError(nm.NewBinary(
+				nm.NewBinary(nm.NewVar("name"), nm.NewStringDouble(" must be a "), nm.BopPlus),
+				nm.NewVar("type"),
+				nm.BopPlus)),
+		),
+	)
+
+	o.Set(nm.FunctionKey("isQuantity", []string{"value"}), isQuantityFn())
+	o.Set(nm.FunctionKey("isTime", []string{"value"}), isTimeFn())
+	o.Set(nm.FunctionKey("isClock", []string{"value"}), isClockFn())
+	o.Set(nm.FunctionKey("isNumber", []string{"value"}), isNumberFn())
+	o.Set(nm.FunctionKey("isInteger", []string{"value"}), isIntegerFn())
+	o.Set(nm.FunctionKey("isDigits", []string{"value"}), isDigitsFn())
+	o.Set(nm.FunctionKey("isDigitGroups", []string{"value", "sep", "lengths"}), isDigitGroupsFn())
+	o.Set(nm.FunctionKey("unsigned", []string{"value"}), unsignedFn())
+	o.Set(nm.FunctionKey("hasSuffix", []string{"value", "suffix"}), hasSuffixFn())
+
+	return o
+}
+
+// isQuantityFn creates a function body which returns true if value is a
+// quantity: a number, a number followed by one of the quantity suffixes, or a
+// number followed by a decimal exponent. (e.g. 2, 512Mi or 1e3)
+func isQuantityFn() nm.Noder {
+	var suffixes []nm.Noder
+	for _, suffix := range quantitySuffixes {
+		suffixes = append(suffixes, nm.NewStringDouble(suffix))
+	}
+
+	trimmed := nm.ApplyCall("std.substr",
+		nm.NewVar("value"),
+		nm.NewInt(0),
+		nm.NewBinary(stdLength("value"), stdLength("suffix"), nm.BopMinus))
+
+	matchesSuffix := nm.NewFunction([]string{"suffix"}, nm.NewBinary(
+		nm.ApplyCall("self.hasSuffix", nm.NewVar("value"), nm.NewVar("suffix")),
+		nm.ApplyCall("self.isNumber", trimmed),
+		nm.BopAnd))
+
+	matchesExponent := nm.NewFunction([]string{"e"}, nm.NewLocal("parts",
+		nm.ApplyCall("std.split", nm.NewVar("value"), nm.NewVar("e")),
+		allOf(
+			nm.NewBinary(stdLength("parts"), nm.NewInt(2), nm.BopEqual),
+			nm.ApplyCall("self.isNumber", indexOf("parts", 0)),
+			nm.ApplyCall("self.isInteger", indexOf("parts", 1)),
+		)))
+
+	exponents := nm.NewArray([]nm.Noder{nm.NewStringDouble("e"), nm.NewStringDouble("E")})
+
+	return anyOf(
+		nm.ApplyCall("self.isNumber", nm.NewVar("value")),
+		anyMatches(matchesSuffix, nm.NewArray(suffixes)),
+		anyMatches(matchesExponent, exponents),
+	)
+}
+
+// isTimeFn creates a function body which returns true if value is a RFC 3339
+// date-time. (e.g. 2018-01-01T00:00:00Z or 2018-01-01T00:00:00.5+01:00)
+func isTimeFn() nm.Noder {
+	return nm.NewLocal("parts",
+		nm.ApplyCall("std.split", nm.NewVar("value"), nm.NewStringDouble("T")),
+		allOf(
+			nm.NewBinary(stdLength("parts"), nm.NewInt(2), nm.BopEqual),
+			nm.ApplyCall("self.isDigitGroups", indexOf("parts", 0), nm.NewStringDouble("-"), digitLengths(4, 2, 2)),
+			nm.ApplyCall("self.isClock", indexOf("parts", 1)),
+		))
+}
+
+// isClockFn creates a function body which returns true if value is the time
+// of a RFC 3339 date-time: hours, minutes and seconds with an optional
+// fraction, followed by Z or an offset. (e.g. 12:00:00Z or 12:00:00.5+01:00)
+func isClockFn() nm.Noder {
+	// zoneStart is the index of Z, or of the sign of the offset.
+	zoneStart := nm.NewConditional(
+		nm.ApplyCall("self.hasSuffix", nm.NewVar("value"), nm.NewStringDouble("Z")),
+		nm.NewBinary(stdLength("value"), nm.NewInt(1), nm.BopMinus),
+		nm.NewBinary(stdLength("value"), nm.NewInt(6), nm.BopMinus))
+
+	zone := nm.ApplyCall("std.substr",
+		nm.NewVar("value"),
+		nm.NewVar("zoneStart"),
+		nm.NewBinary(stdLength("value"), nm.NewVar("zoneStart"), nm.BopMinus))
+
+	clock := nm.ApplyCall("std.split",
+		nm.ApplyCall("std.substr", nm.NewVar("value"), nm.NewInt(0), nm.NewVar("zoneStart")),
+		nm.NewStringDouble("."))
+
+	signs := nm.NewArray([]nm.Noder{nm.NewStringDouble("+"), nm.NewStringDouble("-")})
+	isOffset := allOf(
+		nm.ApplyCall("std.setMember", nm.ApplyCall("std.substr", nm.NewVar("zone"), nm.NewInt(0), nm.NewInt(1)), signs),
+		nm.ApplyCall("self.isDigitGroups",
+			nm.ApplyCall("std.substr", nm.NewVar("zone"), nm.NewInt(1), nm.NewInt(5)),
+			nm.NewStringDouble(":"),
+			digitLengths(2, 2)),
+	)
+
+	// the locals are only evaluated if value is long enough to have a zone.
+	return nm.NewLocalBinds([]nm.LocalBind{
+		{Name: "zoneStart", Value: zoneStart},
+		{Name: "zone", Value: zone},
+		{Name: "clock", Value: clock},
+	}, allOf(
+		nm.NewBinary(stdLength("value"), nm.NewInt(6), nm.BopGreater),
+		anyOf(nm.NewBinary(nm.NewVar("zone"), nm.NewStringDouble("Z"), nm.BopEqual), isOffset),
+		nm.NewBinary(nm.NewInt(3), stdLength("clock"), nm.BopGreater),
+		nm.ApplyCall("self.isDigitGroups", indexOf("clock", 0), nm.NewStringDouble(":"), digitLengths(2, 2, 2)),
+		anyOf(
+			nm.NewBinary(stdLength("clock"), nm.NewInt(1), nm.BopEqual),
+			nm.ApplyCall("self.isDigits", indexOf("clock", 1)),
+		),
+	))
+}
+
+// isNumberFn creates a function body which returns true if value is a decimal
+// number with an optional sign and fraction. (e.g. 2, -0.5 or .5)
+func isNumberFn() nm.Noder {
+	isInvalid := nm.NewFunction([]string{"part"}, allOf(
+		nm.NewBinary(nm.NewVar("part"), nm.NewStringDouble(""), nm.BopNotEqual),
+		nm.NewUnary(nm.UopNot, nm.ApplyCall("self.isDigits", nm.NewVar("part"))),
+	))
+
+	return nm.NewLocal("parts",
+		nm.ApplyCall("std.split", nm.ApplyCall("self.unsigned", nm.NewVar("value")), nm.NewStringDouble(".")),
+		allOf(
+			nm.NewBinary(nm.NewInt(3), stdLength("parts"), nm.BopGreater),
+			nm.NewBinary(
+				nm.ApplyCall("std.join", nm.NewStringDouble(""), nm.NewVar("parts")),
+				nm.NewStringDouble(""),
+				nm.BopNotEqual),
+			nm.NewBinary(
+				nm.ApplyCall("std.length", nm.ApplyCall("std.filter", isInvalid, nm.NewVar("parts"))),
+				nm.NewInt(0),
+				nm.BopEqual),
+		))
+}
+
+// isIntegerFn creates a function body which returns true if value is an
+// integer with an optional sign.
+func isIntegerFn() nm.Noder {
+	return nm.ApplyCall("self.isDigits", nm.ApplyCall("self.unsigned", nm.NewVar("value")))
+}
+
+// isDigitsFn creates a function body which returns true if value is a non-empty
+// string of decimal digits.
+func isDigitsFn() nm.Noder {
+	digits := nm.ApplyCall("std.set", nm.ApplyCall("std.stringChars", nm.NewStringDouble("0123456789")))
+	isDigit := nm.NewFunction([]string{"c"}, nm.ApplyCall("std.setMember", nm.NewVar("c"), digits))
+
+	digitChars := nm.ApplyCall("std.length",
+		nm.ApplyCall("std.filter", isDigit, nm.ApplyCall("std.stringChars", nm.NewVar("value"))))
+
+	return allOf(
+		nm.NewBinary(stdLength("value"), nm.NewInt(0), nm.BopGreater),
+		nm.NewBinary(digitChars, stdLength("value"), nm.BopEqual),
+	)
+}
+
+// isDigitGroupsFn creates a function body which returns true if value is
+// groups of digits with the given lengths, separated by sep. (e.g. 2018-01-01)
+func isDigitGroupsFn() nm.Noder {
+	isGroup := nm.NewFunction([]string{"i"}, allOf(
+		nm.NewBinary(
+			nm.ApplyCall("std.length", indexExpr("parts", nm.NewVar("i"))),
+			indexExpr("lengths", nm.NewVar("i")),
+			nm.BopEqual),
+		nm.ApplyCall("self.isDigits", indexExpr("parts", nm.NewVar("i"))),
+	))
+
+	indexes := nm.ApplyCall("std.range", nm.NewInt(0), nm.NewBinary(stdLength("parts"), nm.NewInt(1), nm.BopMinus))
+
+	return nm.NewLocal("parts",
+		nm.ApplyCall("std.split", nm.NewVar("value"), nm.NewVar("sep")),
+		allOf(
+			nm.NewBinary(stdLength("parts"), stdLength("lengths"), nm.BopEqual),
+			nm.NewBinary(
+				nm.ApplyCall("std.length", nm.ApplyCall("std.filter", isGroup, indexes)),
+				stdLength("parts"),
+				nm.BopEqual),
+		))
+}
+
+// unsignedFn creates a function body which returns value without its leading
+// sign.
+func unsignedFn() nm.Noder {
+	signs := nm.NewArray([]nm.Noder{nm.NewStringDouble("+"), nm.NewStringDouble("-")})
+
+	return nm.NewConditional(
+		nm.ApplyCall("std.setMember", nm.ApplyCall("std.substr", nm.NewVar("value"), nm.NewInt(0), nm.NewInt(1)), signs),
+		nm.ApplyCall("std.substr", nm.NewVar("value"), nm.NewInt(1),
+			nm.NewBinary(stdLength("value"), nm.NewInt(1), nm.BopMinus)),
+		nm.NewVar("value"))
+}
+
+// hasSuffixFn creates a function body which returns true if value ends with suffix
+// and has at least one character before it.
+func hasSuffixFn() nm.Noder {
+	end := nm.ApplyCall("std.substr",
+		nm.NewVar("value"),
+		nm.NewBinary(stdLength("value"), stdLength("suffix"), nm.BopMinus),
+		stdLength("suffix"))
+
+	return nm.NewBinary(
+		nm.NewBinary(stdLength("value"), stdLength("suffix"), nm.BopGreater),
+		nm.NewBinary(end, nm.NewVar("suffix"), nm.BopEqual),
+		nm.BopAnd)
+}
+
+// allOf joins conditions with &&.
+func allOf(conditions ...nm.Noder) nm.Noder {
+	return joinConditions(nm.BopAnd, conditions)
+}
+
+// anyOf joins conditions with ||. The printer doesn't add parentheses, so
+// the joined conditions are in parentheses to be used in allOf.
+func anyOf(conditions ...nm.Noder) nm.Noder {
+	return nm.NewParens(joinConditions(nm.BopOr, conditions))
+}
+
+func joinConditions(op nm.BinaryOp, conditions []nm.Noder) nm.Noder {
+	joined := conditions[0]
+	for _, c := range conditions[1:] {
+		joined = nm.NewBinary(joined, c, op)
+	}
+
+	return joined
+}
+
+// anyMatches creates a condition which is true if fn is true for any of the
+// elements of array.
+func anyMatches(fn, array nm.Noder) nm.Noder {
+	return nm.NewBinary(
+		nm.ApplyCall("std.length", nm.ApplyCall("std.filter", fn, array)),
+		nm.NewInt(0),
+		nm.BopGreater)
+}
+
+// indexOf creates an index of an array variable. e.g. `parts[0]`
+func indexOf(varName string, i int) nm.Noder {
+	return indexExpr(varName, nm.NewInt(i))
+}
+
+func indexExpr(varName string, expr nm.Noder) nm.Noder {
+	return nm.NewCallChain(nm.NewVar(varName), nm.NewIndexExpr(expr))
+}
+
+func digitLengths(lengths ...int) nm.Noder {
+	var elements []nm.Noder
+	for _, l := range lengths {
+		elements = append(elements, nm.NewInt(l))
+	}
+
+	return nm.NewArray(elements)
+}
+
+func isStdType(varName, typeName string) nm.Noder {
+	return nm.NewBinary(
+		nm.ApplyCall("std.type", nm.NewVar(varName)),
+		nm.NewStringDouble(typeName),
+		nm.BopEqual)
+}
+
+func stdLength(varName string) nm.Noder {
+	return nm.ApplyCall("std.length", nm.NewVar(varName))
+}
+
+func formatError(msg string) nm.Noder {
+	return nm.NewError(nm.NewBinary(nm.NewVar("name"), nm.NewStringDouble(msg), nm.BopPlus))
+}
+
+// formatHelper returns the name of the helper which checks values with a format.
+// It returns a blank string if the format isn't checked.
+func formatHelper(format string) string {
+	switch format {
+	case formatIntOrString:
+		return "intOrString"
+	case formatQuantity:
+		return "quantity"
+	case formatDateTime:
+		return "time"
+	default:
+		return ""
+	}
+}
+
+// jsonnetType returns the jsonnet type of a swagger type. It returns a blank
+// string if the type is unknown.
+func jsonnetType(swaggerType string) string {
+	switch swaggerType {
+	case "integer", "number":
+		return "number"
+	case "string", "boolean", "array", "object":
+		return swaggerType
+	default:
+		return ""
+	}
+}
+
+// formatCheck creates a call to a format helper for a field. e.g.
+// `formats.quantity('sizeLimit', sizeLimit)`
+func formatCheck(helper, name string) nm.Noder {
+	return nm.ApplyCall(localFormats+"."+helper, nm.NewStringDouble(name), nm.NewVar(FormatKind(name)))
+}
@@ -0,0 +1,76 @@
+package ksonnet
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	jsonnet "github.com/google/go-jsonnet"
+	"github.com/ksonnet/ksonnet-lib/ksonnet-gen/printer"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_formatHelpers(t *testing.T) {
+	cases := []struct {
+		name     string
+		snippet  string
+		expected string
+		isErr    bool
+	}{
+		{name: "int-or-string with a number", snippet: `intOrString("port", 80)`, expected: "80"},
+		{name: "int-or-string with a string", snippet: `intOrString("port", "http")`, expected: `"http"`},
+		{name: "int-or-string with an object", snippet: `intOrString("port", {})`, isErr: true},
+		{name: "quantity with a number", snippet: `quantity("memory", 2)`, expected: `"2"`},
+		{name: "quantity with a binary suffix", snippet: `quantity("memory", "512Mi")`, expected: `"512Mi"`},
+		{name: "quantity with a decimal suffix", snippet: `quantity("cpu", "500m")`, expected: `"500m"`},
+		{name: "quantity with a fraction", snippet: `quantity("cpu", "0.5")`, expected: `"0.5"`},
+		{name: "quantity with an exponent", snippet: `quantity("cpu", "1e3")`, expected: `"1e3"`},
+		{name: "quantity with an invalid suffix", snippet: `quantity("memory", "512MB")`, isErr: true},
+		{name: "quantity with only a suffix", snippet: `quantity("memory", "Mi")`, isErr: true},
+		{name: "quantity with a boolean", snippet: `quantity("memory", true)`, isErr: true},
+		{name: "quantity with a sign", snippet: `quantity("cpu", "-0.5")`, expected: `"-0.5"`},
+		{name: "quantity with a leading point", snippet: `quantity("cpu", ".5m")`, expected: `".5m"`},
+		{name: "quantity with a signed exponent", snippet: `quantity("cpu", "1.5E-3")`, expected: `"1.5E-3"`},
+		{name: "quantity with the exa suffix", snippet: `quantity("memory", "1E")`, expected: `"1E"`},
+		{name: "quantity with only an exponent", snippet: `quantity("memory", "e")`, isErr: true},
+		{name: "quantity with only points", snippet: `quantity("memory", "...")`, isErr: true},
+		{name: "quantity with only signs", snippet: `quantity("memory", "+-")`, isErr: true},
+		{name: "quantity with an exponent and a suffix", snippet: `quantity("memory", "eMi")`, isErr: true},
+		{name: "quantity with two points", snippet: `quantity("memory", "1.2.3")`, isErr: true},
+		{name: "quantity with an exponent without digits", snippet: `quantity("memory", "1e")`, isErr: true},
+		{name: "quantity with a fractional exponent", snippet: `quantity("memory", "1e1.5")`, isErr: true},
+		{name: "quantity with an empty string", snippet: `quantity("memory", "")`, isErr: true},
+		{name: "time with a string", snippet: `time("at", "2018-01-01T00:00:00Z")`, expected: `"2018-01-01T00:00:00Z"`},
+		{name: "time with an offset", snippet: `time("at", "2018-01-01T12:30:00-05:00")`, expected: `"2018-01-01T12:30:00-05:00"`},
+		{name: "time with a fraction", snippet: `time("at", "2018-01-01T12:30:00.123Z")`, expected: `"2018-01-01T12:30:00.123Z"`},
+		{name: "time with a number", snippet: `time("at", 1)`, isErr: true},
+		{name: "time without a zone", snippet: `time("at", "2018-01-01T00:00:00")`, isErr: true},
+		{name: "time with a date", snippet: `time("at", "2018-01-01")`, isErr: true},
+		{name: "time with a short year", snippet: `time("at", "18-01-01T00:00:00Z")`, isErr: true},
+		{name: "time with words", snippet: `time("at", "tomorrow")`, isErr: true},
+		{name: "time with an invalid clock", snippet: `time("at", "2018-01-01Tnoon:00:00Z")`, isErr: true},
+		{name: "time with an invalid fraction", snippet: `time("at", "2018-01-01T00:00:00.xZ")`, isErr: true},
+		{name: "time with a short offset", snippet: `time("at", "2018-01-01T00:00:00+5")`, isErr: true},
+		{name: "typed with a matching type", snippet: `typed("labels", "string", "a")`, expected: `"a"`},
+		{name: "typed with a different type", snippet: `typed("labels", "string", 1)`, isErr: true},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, printer.Fprint(&buf, formatHelpers().Node()))
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			snippet := fmt.Sprintf("local formats = %s;\nformats.%s", buf.String(), tc.snippet)
+
+			vm := jsonnet.MakeVM()
+			got, err := vm.EvaluateSnippet("formats.jsonnet", snippet)
+			if tc.isErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expected+"\n", got)
+		})
+	}
+}
@@ -0,0 +1,181 @@
+package ksonnet
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-openapi/spec"
+	"github.com/pkg/errors"
+)
+
+var reVersion = regexp.MustCompile(`^v\d+((alpha|beta)\d+)?$`)
+
+// Naming describes definition names. It returns an UnknownDefinitionError
+// for names it can't describe.
+type Naming func(name string) (*Description, error)
+
+// CatalogOptGeneric is a Catalog option for generating a lib for a swagger
+// API which isn't Kubernetes. The root definitions are the types of the lib,
+// and the definitions they refer to are its hidden fields. The version of
+// the spec isn't a Kubernetes version, and Kubernetes version data, paths and
+// property conventions aren't used. Types don't have an apiVersion or kind.
+func CatalogOptGeneric(roots ...string) CatalogOpt {
+	return func(c *Catalog) {
+		c.generic = true
+		c.roots = append(c.roots, roots...)
+	}
+}
+
+// CatalogOptNaming is a Catalog option for describing definition names with
+// a naming strategy, instead of the Kubernetes naming scheme or the generic
+// naming of CatalogOptGeneric. Name patterns are tried before the naming
+// strategy.
+func CatalogOptNaming(naming Naming) CatalogOpt {
+	return func(c *Catalog) {
+		c.naming = naming
+		c.customNaming = true
+	}
+}
+
+// initGeneric initializes a Catalog of a generic API.
+func (c *Catalog) initGeneric() error {
+	if len(c.roots) == 0 {
+		return errors.New("a generic API needs root definitions")
+	}
+
+	for _, root := range c.roots {
+		if _, ok := c.apiSpec.Definitions[root]; !ok {
+			return errors.Errorf("root definition %s was not found", root)
+		}
+	}
+
+	c.version = c.apiSpec.Info.Version
+	if c.naming == nil {
+		c.naming = genericNaming(majorVersion(c.version))
+	}
+
+	return nil
+}
+
+// genericNaming describes the definition names of generic APIs. A name is
+// `[group.]version.Kind`, e.g. compute.v1.Instance, and the group is named
+// with GroupName. Names without a version (e.g. Pet) are in the default
+// version.
+func genericNaming(defaultVersion string) Naming {
+	return func(name string) (*Description, error) {
+		segments := strings.Split(name, ".")
+
+		d := &Description{
+			Name:     name,
+			Version:  defaultVersion,
+			Kind:     segments[len(segments)-1],
+			Codebase: "api",
+		}
+
+		prefix := segments[:len(segments)-1]
+		if n := len(prefix); n > 0 && reVersion.MatchString(prefix[n-1]) {
+			d.Version = prefix[n-1]
+			prefix = prefix[:n-1]
+		}
+
+		if len(prefix) > 0 {
+			d.QualifiedGroup = strings.Join(prefix, ".")
+			d.Group = GroupName(d.QualifiedGroup)
+		}
+
+		if d.Kind == "" || d.Version == "" {
+			return nil, &UnknownDefinitionError{name: name}
+		}
+
+		return d, nil
+	}
+}
+
+// majorVersion returns the major version of an API version as a lib
+// version, e.g. v2 for 2.1.0. It is v1 if the version doesn't start with a
+// number.
+func majorVersion(version string) string {
+	major := strings.SplitN(strings.TrimPrefix(version, "v"), ".", 2)[0]
+
+	n, err := strconv.Atoi(major)
+	if err != nil || n < 0 {
+		return "v1"
+	}
+
+	return fmt.Sprintf("v%d", n)
+}
+
+// isRoot returns true if a definition is a root definition of a generic API.
+func (c *Catalog) isRoot(name string) bool {
+	return stringInSlice(name, c.roots)
+}
+
+// reachableDefinitions returns the root definitions of a generic API and the
+// definitions they refer to.
+func (c *Catalog) reachableDefinitions() spec.Definitions {
+	out := spec.Definitions{}
+
+	queue := append([]string{}, c.roots...)
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+
+		schema, ok := c.apiSpec.Definitions[name]
+		if !ok {
+			continue
+		}
+		if _, seen := out[name]; seen {
+			continue
+		}
+		out[name] = schema
+
+		queue = append(queue, schemaRefs(schema)...)
+	}
+
+	return out
+}
+
+// schemaRefs returns the definitions a schema refers to, sorted by name.
+func schemaRefs(schema spec.Schema) []string {
+	refs := make(map[string]bool)
+
+	var walk func(s spec.Schema)
+	walk = func(s spec.Schema) {
+		if ref := extractRef(s); ref != "" {
+			refs[ref] = true
+		}
+
+		for _, p := range s.Properties {
+			walk(p)
+		}
+
+		for _, sub := range s.AllOf {
+			walk(sub)
+		}
+
+		if s.Items != nil {
+			if s.Items.Schema != nil {
+				walk(*s.Items.Schema)
+			}
+			for _, item := range s.Items.Schemas {
+				walk(item)
+			}
+		}
+
+		if s.AdditionalProperties != nil && s.AdditionalProperties.Schema != nil {
+			walk(*s.AdditionalProperties.Schema)
+		}
+	}
+	walk(schema)
+
+	var names []string
+	for ref := range refs {
+		names = append(names, ref)
+	}
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,166 @@
+package ksonnet
+
+import (
+	"testing"
+
+	jsonnet "github.com/google/go-jsonnet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var genericRoots = []string{"Pet", "compute.v1.Instance"}
+
+func Test_genericNaming(t *testing.T) {
+	naming := genericNaming("v2")
+
+	cases := []struct {
+		name     string
+		expected *Description
+	}{
+		{
+			name: "Pet",
+			expected: &Description{
+				Name: "Pet", Version: "v2", Kind: "Pet", Codebase: "api",
+			},
+		},
+		{
+			name: "compute.v1.Instance",
+			expected: &Description{
+				Name: "compute.v1.Instance", Version: "v1", Kind: "Instance", Group: "compute",
+				Codebase: "api", QualifiedGroup: "compute",
+			},
+		},
+		{
+			name: "com.example.billing.Invoice",
+			expected: &Description{
+				Name: "com.example.billing.Invoice", Version: "v2", Kind: "Invoice",
+				Group: "comExampleBilling", Codebase: "api", QualifiedGroup: "com.example.billing",
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			d, err := naming(tc.name)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, d)
+		})
+	}
+}
+
+func Test_majorVersion(t *testing.T) {
+	assert.Equal(t, "v2", majorVersion("2.1.0"))
+	assert.Equal(t, "v3", majorVersion("v3"))
+	assert.Equal(t, "v1", majorVersion("2018-01-01"))
+}
+
+func TestCatalog_generic(t *testing.T) {
+	c := initCatalog(t, "generic.json", CatalogOptGeneric(genericRoots...))
+	assert.Equal(t, "2.1.0", c.Version())
+	assert.Empty(t, c.Warnings())
+
+	types, err := c.Types()
+	require.NoError(t, err)
+
+	var ids []string
+	for _, ty := range types {
+		ids = append(ids, ty.Identifier())
+	}
+	assert.ElementsMatch(t, genericRoots, ids)
+
+	fields, err := c.Fields()
+	require.NoError(t, err)
+
+	ids = nil
+	for _, f := range fields {
+		ids = append(ids, f.Identifier())
+	}
+	assert.ElementsMatch(t, []string{"Category", "Tag", "compute.v1.AttachedDisk"}, ids)
+
+	pet, err := c.TypeByID("Pet")
+	require.NoError(t, err)
+	assert.Contains(t, pet.Properties(), "kind")
+	assert.Contains(t, pet.Properties(), "status")
+	assert.NotContains(t, pet.Properties(), "id")
+}
+
+func TestCatalog_generic_invalid_roots(t *testing.T) {
+	apiSpec := initCatalog(t, "generic.json", CatalogOptGeneric(genericRoots...)).apiSpec
+
+	_, err := NewCatalog(apiSpec, CatalogOptGeneric())
+	require.Error(t, err)
+
+	_, err = NewCatalog(apiSpec, CatalogOptGeneric("Missing"))
+	require.Error(t, err)
+}
+
+func TestCatalog_naming(t *testing.T) {
+	naming := func(name string) (*Description, error) {
+		return &Description{Name: name, Version: "v1beta1", Kind: name, Group: "store"}, nil
+	}
+
+	c := initCatalog(t, "generic.json", CatalogOptGeneric("Pet"), CatalogOptNaming(naming))
+
+	ty, err := c.TypeByID("Pet")
+	require.NoError(t, err)
+	assert.Equal(t, "store", ty.Group())
+	assert.Equal(t, "v1beta1", ty.Version())
+}
+
+func TestGenerateLib_generic(t *testing.T) {
+	source := "testdata/generic.json"
+
+	lib, err := GenerateLib(source, CatalogOptGeneric(genericRoots...))
+	require.NoError(t, err)
+	require.Nil(t, lib.Extensions)
+	assert.Equal(t, "2.1.0", lib.Version)
+	assert.Equal(t, 2, lib.Types)
+	assert.NotContains(t, string(lib.K8s), "kubernetesVersion")
+
+	p, content, err := ParseProvenance(lib.K8s)
+	require.NoError(t, err)
+	assert.Equal(t, "2.1.0", p.APIVersion)
+	assert.Empty(t, p.KubernetesVersion)
+	assert.NotContains(t, string(content), "apiVersion")
+
+	require.NoError(t, Verify(source, lib.Files()))
+
+	vm := jsonnet.MakeVM()
+	vm.Importer(&jsonnet.MemoryImporter{
+		Data: map[string]string{"lib.libsonnet": string(lib.K8s)},
+	})
+
+	snippet := `
+local lib = import 'lib.libsonnet';
+local pet = lib.core.v2.pet;
+local instance = lib.compute.v1.instance;
+local disk = instance.disksType;
+
+[
+  pet.new() + pet.withName('rex') + pet.withKind('dog') + pet.withStatus('available') +
+  pet.mixin.category.withName('dogs') + pet.withTags({ name: 'good' }) +
+  pet.withLabelsEntry('color', 'brown'),
+  instance.new() + instance.withName('vm') +
+  instance.withDisks([disk.new() + disk.withSizeGb(10) + disk.withBoot(true)]),
+]
+`
+
+	out, err := vm.EvaluateSnippet("snippet", snippet)
+	require.NoError(t, err)
+
+	expected := `[
+  {
+    "name": "rex",
+    "kind": "dog",
+    "status": "available",
+    "category": {"name": "dogs"},
+    "tags": [{"name": "good"}],
+    "labels": {"color": "brown"}
+  },
+  {
+    "name": "vm",
+    "disks": [{"sizeGb": 10, "boot": true}]
+  }
+]`
+	assert.JSONEq(t, expected, out)
+}
@@ -0,0 +1,122 @@
+package ksonnet
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/ksonnet/ksonnet-lib/ksonnet-gen/kubeversion"
+	"github.com/pkg/errors"
+)
+
+var (
+	reIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+// identifiers are the identifiers a property is rendered with. The
+// deprecated identifiers are other spellings of the identifier which are
+// rendered as aliases.
+type identifiers struct {
+	id         string
+	deprecated []string
+}
+
+// Identifier returns the identifier of the property. (e.g. clusterIp)
+func (i *identifiers) Identifier() string {
+	return i.id
+}
+
+// DeprecatedIdentifiers returns the deprecated spellings of the identifier
+// of the property. (e.g. externalIPs)
+func (i *identifiers) DeprecatedIdentifiers() []string {
+	return i.deprecated
+}
+
+func (i *identifiers) setIdentifiers(id string, deprecated []string) {
+	i.id = id
+	i.deprecated = deprecated
+}
+
+// identifiedProperty is a property with identifiers.
+type identifiedProperty interface {
+	Property
+	Identifier() string
+	DeprecatedIdentifiers() []string
+	setIdentifiers(id string, deprecated []string)
+}
+
+var _ identifiedProperty = (*LiteralField)(nil)
+var _ identifiedProperty = (*ReferenceField)(nil)
+
+// propertyIdentifiers returns the identifiers of a property.
+func propertyIdentifiers(p Property) (string, []string) {
+	if ip, ok := p.(identifiedProperty); ok {
+		return ip.Identifier(), ip.DeprecatedIdentifiers()
+	}
+
+	return FormatKind(p.Name()), nil
+}
+
+// versionIdentifierAliases returns the identifier aliases of a Kubernetes
+// version. Versions without version data don't have aliases.
+func versionIdentifierAliases(d *kubeversion.Data, version string) (map[string]string, error) {
+	aliases, err := d.IdentifierAliases(version)
+	if err != nil {
+		if _, ok := err.(*kubeversion.UnknownVersionError); ok {
+			return nil, nil
+		}
+
+		return nil, errors.Wrapf(err, "load identifier aliases for %s", version)
+	}
+
+	return aliases, nil
+}
+
+// identifyProperties sets the identifiers of properties. A property's
+// identifier is its name after applying the identifier aliases of the
+// Catalog's version. If the Catalog renders deprecated identifiers, the
+// identifier the legacy emitter generated is kept as a deprecated
+// identifier when it is spelled differently.
+func (c *Catalog) identifyProperties(props map[string]Property) {
+	for name, p := range props {
+		ip, ok := p.(identifiedProperty)
+		if !ok {
+			continue
+		}
+
+		id := c.identifier(name)
+
+		var deprecated []string
+		if c.deprecatedIdentifiers {
+			legacy := c.legacyIdentifier(name)
+			if legacy != id && reIdentifier.MatchString(legacy) {
+				deprecated = append(deprecated, legacy)
+			}
+		}
+
+		ip.setIdentifiers(id, deprecated)
+	}
+}
+
+// identifier returns the identifier for a name.
+func (c *Catalog) identifier(name string) string {
+	if alias, ok := c.identifierAliases[name]; ok {
+		name = alias
+	}
+
+	return FormatKind(name)
+}
+
+// legacyIdentifier returns the identifier the legacy emitter generates for
+// a name. It only applies the identifier aliases and lower cases the first
+// letter.
+func (c *Catalog) legacyIdentifier(name string) string {
+	if alias, ok := c.identifierAliases[name]; ok {
+		name = alias
+	}
+
+	if name == "" {
+		return name
+	}
+
+	return strings.ToLower(name[:1]) + name[1:]
+}
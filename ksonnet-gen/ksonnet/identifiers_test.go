@@ -0,0 +1,94 @@
+package ksonnet
+
+import (
+	"testing"
+
+	"github.com/ksonnet/ksonnet-lib/ksonnet-gen/kubeversion"
+	nm "github.com/ksonnet/ksonnet-lib/ksonnet-gen/nodemaker"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCatalog_identifyProperties(t *testing.T) {
+	cases := []struct {
+		name       string
+		deprecated bool
+		id         string
+		expected   string
+		legacy     []string
+	}{
+		{name: "plain", id: "replicas", expected: "replicas"},
+		{name: "version alias", id: "fooID", expected: "fooIdentifier"},
+		{name: "initialism", id: "openAPIV3Schema", expected: "openApiV3Schema"},
+		{name: "initialism with deprecated identifiers", deprecated: true,
+			id: "openAPIV3Schema", expected: "openApiV3Schema", legacy: []string{"openAPIV3Schema"}},
+		{name: "keyword with deprecated identifiers", deprecated: true,
+			id: "error", expected: "errorParam", legacy: []string{"error"}},
+		{name: "alias with deprecated identifiers", deprecated: true,
+			id: "fooID", expected: "fooIdentifier"},
+		{name: "same spelling with deprecated identifiers", deprecated: true,
+			id: "clusterIP", expected: "clusterIp"},
+		{name: "invalid legacy identifier", deprecated: true, id: "$ref", expected: "dollarRef"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := initCatalog(t, "swagger-1.8.json", CatalogOptDeprecatedIdentifiers(tc.deprecated))
+			c.identifierAliases["fooID"] = "fooIdentifier"
+
+			props := map[string]Property{
+				tc.id:    NewLiteralField(tc.id, "string", "desc", ""),
+				"custom": &customField{},
+			}
+			c.identifyProperties(props)
+
+			id, deprecated := propertyIdentifiers(props[tc.id])
+			assert.Equal(t, tc.expected, id)
+			assert.Equal(t, tc.legacy, deprecated)
+		})
+	}
+}
+
+func Test_versionIdentifierAliases(t *testing.T) {
+	d, err := kubeversion.Embedded()
+	require.NoError(t, err)
+
+	aliases, err := versionIdentifierAliases(d, "1.8.0")
+	require.NoError(t, err)
+	assert.Equal(t, "clientIp", aliases["clientIP"])
+
+	aliases, err = versionIdentifierAliases(d, "1.10.0")
+	require.NoError(t, err)
+	assert.Nil(t, aliases)
+}
+
+func Test_propertyIdentifiers(t *testing.T) {
+	id, deprecated := propertyIdentifiers(NewReferenceField("podIP", "desc", "ref"))
+	assert.Equal(t, "podIp", id)
+	assert.Nil(t, deprecated)
+
+	id, deprecated = propertyIdentifiers(&customField{})
+	assert.Equal(t, "name", id)
+	assert.Nil(t, deprecated)
+}
+
+func Test_renderDeprecated(t *testing.T) {
+	f := NewLiteralField("openAPIV3Schema", "object", "desc", "")
+	f.setIdentifiers("openApiV3Schema", []string{"openAPIV3Schema"})
+
+	o := nm.NewObject()
+	require.NoError(t, NewLiteralFieldRenderer(f, "").Render(o))
+
+	for _, name := range []string{"withOpenApiV3Schema", "withOpenApiV3SchemaMixin", "withOpenAPIV3Schema", "withOpenAPIV3SchemaMixin"} {
+		assert.NotNil(t, o.Get(name), name)
+	}
+
+	c := initCatalog(t, "swagger-1.8.json")
+	rf := NewReferenceField("aref", "desc", "io.k8s.apimachinery.pkg.apis.meta.v1.LabelSelector")
+	rf.setIdentifiers("aRef", []string{"aref"})
+
+	o = nm.NewObject()
+	require.NoError(t, NewReferenceRenderer(rf, c, "").Render(o))
+	assert.NotNil(t, o.Get("aRef"))
+	assert.NotNil(t, o.Get("aref"))
+}
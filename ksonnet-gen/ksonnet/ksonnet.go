@@ -2,7 +2,9 @@ package ksonnet
 
 import (
 	"bytes"
+	"path/filepath"
 
+	"github.com/go-openapi/spec"
 	"github.com/ksonnet/ksonnet-lib/ksonnet-gen/kubespec"
 	"github.com/ksonnet/ksonnet-lib/ksonnet-gen/printer"
 	"github.com/pkg/errors"
@@ -10,19 +12,45 @@ import (
 
 // Lib is a ksonnet lib.
 type Lib struct {
-	K8s        []byte
+	K8s []byte
+	// Extensions is k.libsonnet. The lib of a generic API doesn't have
+	// extensions, so it is nil.
 	Extensions []byte
+	// JSONSchemas are the JSON Schemas of the types and fields of the lib,
+	// keyed by file name. They are only generated with CatalogOptJSONSchemas,
+	// and their provenance is their $comment.
+	JSONSchemas map[string][]byte
+	// TypeScript are the TypeScript declarations of the types and fields of
+	// the lib. They are only generated with CatalogOptTypeScript.
+	TypeScript []byte
 	Version    string
+	// Provenance records how the lib was generated. Each file of the lib
+	// starts with it as a header.
+	Provenance *Provenance
+	// Types is the number of types in the lib.
+	Types int
+	// Warnings are the problems found while generating the lib which didn't
+	// stop it being generated.
+	Warnings []string
 }
 
-// GenerateLib generates ksonnet lib.
-func GenerateLib(source string) (*Lib, error) {
+// GenerateLib generates ksonnet lib. The options configure the Catalog the
+// lib is generated from. Each file of the lib starts with a provenance header.
+func GenerateLib(source string, opts ...CatalogOpt) (*Lib, error) {
 	apiSpec, checksum, err := kubespec.Import(source)
 	if err != nil {
 		return nil, errors.Wrap(err, "import Kubernetes spec")
 	}
 
-	c, err := NewCatalog(apiSpec, CatalogOptChecksum(checksum))
+	return GenerateLibFromSpec(apiSpec, checksum, opts...)
+}
+
+// GenerateLibFromSpec generates ksonnet lib from an imported swagger spec
+// with a checksum. The spec isn't modified, so it can be shared by libs
+// generated at the same time.
+func GenerateLibFromSpec(apiSpec *spec.Swagger, checksum string, opts ...CatalogOpt) (*Lib, error) {
+	opts = append([]CatalogOpt{CatalogOptChecksum(checksum)}, opts...)
+	c, err := NewCatalog(apiSpec, opts...)
 	if err != nil {
 		return nil, errors.Wrap(err, "create ksonnet catalog")
 	}
@@ -32,20 +60,96 @@ func GenerateLib(source string) (*Lib, error) {
 		return nil, errors.Wrap(err, "create k8s.libsonnet")
 	}
 
-	k, err := createK(c)
+	var e *Extension
+	var k []byte
+	if !c.generic {
+		e = NewExtension(c)
+
+		k, err = createK(e)
+		if err != nil {
+			return nil, errors.Wrap(err, "create k.libsonnet")
+		}
+	}
+
+	p, err := newProvenance(c, e)
+	if err != nil {
+		return nil, errors.Wrap(err, "create provenance")
+	}
+
+	types, err := c.Types()
 	if err != nil {
-		return nil, errors.Wrap(err, "create k.libsonnet")
+		return nil, errors.Wrap(err, "retrieve types")
 	}
 
 	lib := &Lib{
-		K8s:        k8s,
-		Extensions: k,
-		Version:    c.apiVersion.String(),
+		K8s:        p.Stamp(k8s),
+		Version:    c.Version(),
+		Provenance: p,
+		Types:      len(types),
+		Warnings:   c.Warnings(),
+	}
+
+	if e != nil {
+		lib.Extensions = p.Stamp(k)
+	}
+
+	if c.jsonSchemas {
+		schemas, err := GenerateJSONSchemas(c)
+		if err != nil {
+			return nil, errors.Wrap(err, "create JSON Schemas")
+		}
+
+		lib.JSONSchemas = make(map[string][]byte)
+		for name, schema := range schemas {
+			lib.JSONSchemas[name] = p.stampJSON(schema)
+		}
+	}
+
+	if c.typeScript {
+		ts, err := GenerateTypeScript(c)
+		if err != nil {
+			return nil, errors.Wrap(err, "create TypeScript declarations")
+		}
+		lib.TypeScript = p.Stamp(ts)
 	}
 
 	return lib, nil
 }
 
+// Files returns the files of the lib, keyed by their path in the directory
+// the lib is written to: k8s.libsonnet and k.libsonnet (lib.libsonnet for
+// the lib of a generic API), the TypeScript declarations, and the JSON
+// Schemas in schemas.
+func (l *Lib) Files() map[string][]byte {
+	files := map[string][]byte{
+		"k8s.libsonnet": l.K8s,
+		"k.libsonnet":   l.Extensions,
+	}
+	if l.Extensions == nil {
+		files = map[string][]byte{"lib.libsonnet": l.K8s}
+	}
+
+	if l.TypeScript != nil {
+		files[l.TypeScriptFile()] = l.TypeScript
+	}
+
+	for name, schema := range l.JSONSchemas {
+		files[filepath.Join("schemas", name)] = schema
+	}
+
+	return files
+}
+
+// TypeScriptFile returns the name of the file of the TypeScript declarations
+// of the lib: k8s.d.ts, or lib.d.ts for the lib of a generic API.
+func (l *Lib) TypeScriptFile() string {
+	if l.Extensions == nil {
+		return "lib.d.ts"
+	}
+
+	return "k8s.d.ts"
+}
+
 func createK8s(c *Catalog) ([]byte, error) {
 	doc, err := NewDocument(c)
 	if err != nil {
@@ -66,9 +170,7 @@ func createK8s(c *Catalog) ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
-func createK(c *Catalog) ([]byte, error) {
-	e := NewExtension(c)
-
+func createK(e *Extension) ([]byte, error) {
 	node, err := e.Node()
 	if err != nil {
 		return nil, errors.Wrapf(err, "build extension node")
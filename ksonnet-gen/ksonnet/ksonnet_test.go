@@ -0,0 +1,334 @@
+package ksonnet
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/go-openapi/spec"
+	jsonnet "github.com/google/go-jsonnet"
+	"github.com/ksonnet/ksonnet-lib/ksonnet-gen/kubespec"
+	"github.com/ksonnet/ksonnet-lib/ksonnet-gen/kubeversion"
+	"github.com/stretchr/testify/require"
+)
+
+// crdSpec returns the 1.8 spec with n custom resources in ten groups, like
+// the spec of a cluster with a lot of CRDs installed. Each custom resource
+// has a list, and a spec with a pod template and a list of items merged by
+// their name.
+func crdSpec(tb testing.TB, n int) *spec.Swagger {
+	b, err := ioutil.ReadFile("testdata/swagger-1.8.json")
+	require.NoError(tb, err)
+
+	var doc map[string]interface{}
+	require.NoError(tb, json.Unmarshal(b, &doc))
+
+	definitions := doc["definitions"].(map[string]interface{})
+
+	ref := func(name string) map[string]interface{} {
+		return map[string]interface{}{"$ref": "#/definitions/" + name}
+	}
+	gvk := func(group, kind string) []interface{} {
+		return []interface{}{
+			map[string]interface{}{"group": group + ".example.com", "version": "v1", "kind": kind},
+		}
+	}
+
+	for i := 0; i < n; i++ {
+		group := fmt.Sprintf("crd%d", i%10)
+		kind := fmt.Sprintf("Widget%d", i)
+		prefix := fmt.Sprintf("io.k8s.api.%s.v1.", group)
+
+		definitions[prefix+kind] = map[string]interface{}{
+			"description": kind + " is a custom resource.",
+			"properties": map[string]interface{}{
+				"apiVersion": map[string]interface{}{"type": "string"},
+				"kind":       map[string]interface{}{"type": "string"},
+				"metadata":   ref("io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta"),
+				"spec":       ref(prefix + kind + "Spec"),
+			},
+			extensionGroupVersionKind: gvk(group, kind),
+		}
+
+		definitions[prefix+kind+"List"] = map[string]interface{}{
+			"description": kind + "List is a list of " + kind + ".",
+			"properties": map[string]interface{}{
+				"apiVersion": map[string]interface{}{"type": "string"},
+				"kind":       map[string]interface{}{"type": "string"},
+				"items":      map[string]interface{}{"type": "array", "items": ref(prefix + kind)},
+				"metadata":   ref("io.k8s.apimachinery.pkg.apis.meta.v1.ListMeta"),
+			},
+			extensionGroupVersionKind: gvk(group, kind+"List"),
+		}
+
+		definitions[prefix+kind+"Spec"] = map[string]interface{}{
+			"description": kind + "Spec is the spec of a " + kind + ".",
+			"properties": map[string]interface{}{
+				"replicas": map[string]interface{}{"type": "integer", "format": "int32"},
+				"template": ref("io.k8s.api.core.v1.PodTemplateSpec"),
+				"items": map[string]interface{}{
+					"type":                 "array",
+					"items":                ref(prefix + kind + "Item"),
+					extensionPatchMergeKey: "name",
+					extensionPatchStrategy: "merge",
+				},
+				"labels": map[string]interface{}{
+					"type":                 "object",
+					"additionalProperties": map[string]interface{}{"type": "string"},
+				},
+			},
+		}
+
+		definitions[prefix+kind+"Item"] = map[string]interface{}{
+			"description": kind + "Item is an item of a " + kind + ".",
+			"properties": map[string]interface{}{
+				"name":  map[string]interface{}{"type": "string"},
+				"value": map[string]interface{}{"type": "string"},
+			},
+		}
+	}
+
+	b, err = json.Marshal(doc)
+	require.NoError(tb, err)
+
+	apiSpec, err := kubespec.CreateAPISpec(b)
+	require.NoError(tb, err)
+
+	return apiSpec
+}
+
+func TestGenerateLibFromSpec_crds(t *testing.T) {
+	lib, err := GenerateLibFromSpec(crdSpec(t, 20), "checksum")
+	require.NoError(t, err)
+
+	base, err := GenerateLib("testdata/swagger-1.8.json")
+	require.NoError(t, err)
+
+	// each custom resource is a type, and so is its list.
+	require.Equal(t, base.Types+40, lib.Types)
+	require.Contains(t, string(lib.K8s), "widget19List")
+}
+
+func TestGenerateLibFromSpec_concurrent(t *testing.T) {
+	specs := []string{"swagger-1.8.json", "collisions.json", "recursive.json"}
+
+	type result struct {
+		lib *Lib
+		err error
+	}
+
+	// every spec is parsed once, and shared by the libs generated from it.
+	apiSpecs := make(map[string]*spec.Swagger)
+	expected := make(map[string]*Lib)
+	for _, name := range specs {
+		apiSpec, checksum, err := kubespec.Import(testdata(name))
+		require.NoError(t, err)
+		apiSpecs[name] = apiSpec
+
+		expected[name], err = GenerateLibFromSpec(apiSpec, checksum)
+		require.NoError(t, err)
+	}
+
+	const copies = 3
+	results := make(map[string][]result)
+	for _, name := range specs {
+		results[name] = make([]result, copies)
+	}
+
+	var wg sync.WaitGroup
+	for _, name := range specs {
+		for i := 0; i < copies; i++ {
+			wg.Add(1)
+			go func(name string, i int) {
+				defer wg.Done()
+				lib, err := GenerateLibFromSpec(apiSpecs[name], expected[name].Provenance.Checksum)
+				results[name][i] = result{lib: lib, err: err}
+			}(name, i)
+		}
+	}
+	wg.Wait()
+
+	for _, name := range specs {
+		for _, r := range results[name] {
+			require.NoError(t, r.err)
+			require.Equal(t, string(expected[name].K8s), string(r.lib.K8s))
+			require.Equal(t, string(expected[name].Extensions), string(r.lib.Extensions))
+			require.Equal(t, expected[name].Warnings, r.lib.Warnings)
+		}
+	}
+}
+
+func TestGenerateLibFromSpec_load_version_data(t *testing.T) {
+	dir := versionDataDir(t)
+	defer os.RemoveAll(dir)
+	defer kubeversion.LoadEmbedded()
+
+	apiSpec, checksum, err := kubespec.Import(testdata("swagger-1.8.json"))
+	require.NoError(t, err)
+
+	expected, err := GenerateLibFromSpec(apiSpec, checksum)
+	require.NoError(t, err)
+
+	d, err := kubeversion.ReadDir(dir)
+	require.NoError(t, err)
+
+	// version data is loaded while libs are generated, both with the
+	// version data of the package and with their own.
+	done := make(chan struct{})
+	loaded := make(chan error, 1)
+	go func() {
+		defer close(loaded)
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+
+			if err := kubeversion.LoadDir(dir); err != nil {
+				loaded <- err
+				return
+			}
+			if err := kubeversion.LoadEmbedded(); err != nil {
+				loaded <- err
+				return
+			}
+		}
+	}()
+
+	libs := make([]*Lib, 4)
+	errs := make([]error, len(libs))
+	var wg sync.WaitGroup
+	for i := range libs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			var opts []CatalogOpt
+			if i%2 == 1 {
+				opts = append(opts, CatalogOptVersionData(d))
+			}
+			libs[i], errs[i] = GenerateLibFromSpec(apiSpec, checksum, opts...)
+		}(i)
+	}
+	wg.Wait()
+	close(done)
+	require.NoError(t, <-loaded)
+
+	for i, lib := range libs {
+		require.NoError(t, errs[i])
+		if i%2 == 1 {
+			require.Contains(t, string(lib.K8s), "withPodIpAddress")
+			continue
+		}
+
+		// the package data is either the embedded data or the data of the
+		// directory, depending on when the lib was generated.
+		if !strings.Contains(string(lib.K8s), "withPodIpAddress") {
+			require.Equal(t, string(expected.K8s), string(lib.K8s))
+		}
+	}
+}
+
+func TestGenerateLib_type_definitions(t *testing.T) {
+	opts := []CatalogOpt{CatalogOptGeneric(genericRoots...)}
+
+	lib, err := GenerateLib(testdata("generic.json"), opts...)
+	require.NoError(t, err)
+	require.Nil(t, lib.JSONSchemas)
+	require.Nil(t, lib.TypeScript)
+
+	opts = append(opts, CatalogOptJSONSchemas(true), CatalogOptTypeScript(true))
+	lib, err = GenerateLib(testdata("generic.json"), opts...)
+	require.NoError(t, err)
+	require.Contains(t, lib.JSONSchemas, "Pet.json")
+	require.Contains(t, string(lib.TypeScript), "export interface Pet {")
+	require.Equal(t, "lib.d.ts", lib.TypeScriptFile())
+
+	lib.Extensions = []byte{}
+	require.Equal(t, "k8s.d.ts", lib.TypeScriptFile())
+}
+
+func TestGenerateLib_quantities(t *testing.T) {
+	lib, err := GenerateLib("testdata/swagger-1.8.json")
+	require.NoError(t, err)
+
+	cases := []struct {
+		name     string
+		snippet  string
+		expected string
+		isErr    bool
+	}{
+		{
+			name:     "resource setter",
+			snippet:  `resources.limits.withMemory("512Mi") + resources.requests.withCpu("250m")`,
+			expected: `{"resources": {"limits": {"memory": "512Mi"}, "requests": {"cpu": "250m"}}}`,
+		},
+		{
+			name:     "entry of another map of quantities",
+			snippet:  `quota.withHardEntry("pods", "10") + {resources: std.objectHasAll(quota, "hard")}`,
+			expected: `{"spec": {"hard": {"pods": "10"}}, "resources": false}`,
+		},
+		{
+			// the other invalid quantities are in Test_formatHelpers.
+			name:    "entry with an invalid quantity",
+			snippet: `resources.withLimitsEntry("memory", "eMi")`,
+			isErr:   true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			vm := jsonnet.MakeVM()
+			vm.Importer(&jsonnet.MemoryImporter{
+				Data: map[string]string{"k8s.libsonnet": string(lib.K8s)},
+			})
+
+			snippet := "local k8s = import 'k8s.libsonnet';\n" +
+				"local resources = k8s.apps.v1beta2.deployment.mixin.spec.template.spec.containersType.mixin.resources;\n" +
+				"local quota = k8s.core.v1.resourceQuota.mixin.spec;\n" +
+				tc.snippet
+
+			got, err := vm.EvaluateSnippet("snippet", snippet)
+			if tc.isErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+
+			expected, err := vm.EvaluateSnippet("expected", tc.expected)
+			require.NoError(t, err)
+			require.JSONEq(t, expected, got)
+		})
+	}
+}
+
+func BenchmarkGenerateLib(b *testing.B) {
+	cases := []struct {
+		name string
+		crds int
+	}{
+		{name: "kubernetes", crds: 0},
+		{name: "100 crds", crds: 100},
+		{name: "500 crds", crds: 500},
+	}
+
+	for _, tc := range cases {
+		b.Run(tc.name, func(b *testing.B) {
+			apiSpec := crdSpec(b, tc.crds)
+
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				if _, err := GenerateLibFromSpec(apiSpec, "checksum"); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
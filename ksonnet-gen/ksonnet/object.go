@@ -1,5 +1,7 @@
 package ksonnet
 
+import "strings"
+
 // Object is an object that can be turned into a node by APIObject.
 type Object interface {
 	Kind() string
@@ -26,18 +28,66 @@ type LiteralField struct {
 	fieldType   string
 	description string
 	ref         string
+	itemType    string
+	format      string
+	mapValue    *MapValue
+	mergeKey    string
+	strategy    string
+	identifiers
 }
 
 var _ Property = (*LiteralField)(nil)
 
+// LiteralFieldOpt is an option for configuring LiteralField.
+type LiteralFieldOpt func(*LiteralField)
+
+// LiteralFieldOptFormat is a LiteralField option for setting the format of the field.
+func LiteralFieldOptFormat(format string) LiteralFieldOpt {
+	return func(f *LiteralField) {
+		f.format = format
+	}
+}
+
+// LiteralFieldOptItemType is a LiteralField option for setting the literal
+// type of the items of an array field whose items aren't a ref.
+func LiteralFieldOptItemType(itemType string) LiteralFieldOpt {
+	return func(f *LiteralField) {
+		f.itemType = itemType
+	}
+}
+
+// LiteralFieldOptMapValue is a LiteralField option for describing the values of
+// a map field. A nil value means the field is not a map.
+func LiteralFieldOptMapValue(mv *MapValue) LiteralFieldOpt {
+	return func(f *LiteralField) {
+		f.mapValue = mv
+	}
+}
+
+// LiteralFieldOptPatch is a LiteralField option for setting the patch merge key
+// and patch strategy of the field.
+func LiteralFieldOptPatch(mergeKey, strategy string) LiteralFieldOpt {
+	return func(f *LiteralField) {
+		f.mergeKey = mergeKey
+		f.strategy = strategy
+	}
+}
+
 // NewLiteralField creates an instance of LiteralField.
-func NewLiteralField(name, fieldType, description, ref string) *LiteralField {
-	return &LiteralField{
+func NewLiteralField(name, fieldType, description, ref string, opts ...LiteralFieldOpt) *LiteralField {
+	f := &LiteralField{
 		name:        name,
 		fieldType:   fieldType,
 		description: description,
 		ref:         ref,
+		identifiers: identifiers{id: FormatKind(name)},
+	}
+
+	for _, opt := range opts {
+		opt(f)
 	}
+
+	return f
 }
 
 // FieldType returns the field type of the LiteralField.
@@ -45,6 +95,43 @@ func (f *LiteralField) FieldType() string {
 	return f.fieldType
 }
 
+// Format returns the format of the LiteralField. (e.g. int-or-string, date-time)
+func (f *LiteralField) Format() string {
+	return f.format
+}
+
+// ItemType returns the literal type of the items of the LiteralField if it is
+// an array whose items aren't a ref. (e.g. string) It is blank if the items
+// can be of any type.
+func (f *LiteralField) ItemType() string {
+	return f.itemType
+}
+
+// MapValue returns the description of the LiteralField's values if it is a map.
+func (f *LiteralField) MapValue() *MapValue {
+	return f.mapValue
+}
+
+// MergeKey returns the patch merge key of the LiteralField. (e.g. name)
+func (f *LiteralField) MergeKey() string {
+	return f.mergeKey
+}
+
+// PatchStrategy returns the patch strategy of the LiteralField. (e.g. merge)
+func (f *LiteralField) PatchStrategy() string {
+	return f.strategy
+}
+
+// IsListMap returns true if the LiteralField is a list of objects which are
+// merged by a key.
+func (f *LiteralField) IsListMap() bool {
+	if f.fieldType != "array" || f.mergeKey == "" {
+		return false
+	}
+
+	return stringInSlice("merge", strings.Split(f.strategy, ","))
+}
+
 // Name returns the name of the LiteralField.
 func (f *LiteralField) Name() string {
 	return f.name
@@ -60,11 +147,23 @@ func (f *LiteralField) Ref() string {
 	return f.ref
 }
 
+// MapValue describes the values of a map field. (e.g. map[string]string)
+type MapValue struct {
+	// Type is the literal type of the values. It is blank if the values can
+	// be of any type.
+	Type string
+	// Format is the format of the values. (e.g. quantity)
+	Format string
+	// Ref is the definition of the values if they are objects.
+	Ref string
+}
+
 // ReferenceField is a reference field.
 type ReferenceField struct {
 	name        string
 	description string
 	ref         string
+	identifiers
 }
 
 var _ Property = (*ReferenceField)(nil)
@@ -75,6 +174,7 @@ func NewReferenceField(name, description, ref string) *ReferenceField {
 		name:        name,
 		description: description,
 		ref:         ref,
+		identifiers: identifiers{id: FormatKind(name)},
 	}
 }
 
@@ -15,6 +15,29 @@ func TestLiteralField(t *testing.T) {
 	assert.Equal(t, "ref", f.Ref())
 }
 
+func TestLiteralField_IsListMap(t *testing.T) {
+	cases := []struct {
+		name      string
+		fieldType string
+		mergeKey  string
+		strategy  string
+		expected  bool
+	}{
+		{name: "merged by key", fieldType: "array", mergeKey: "name", strategy: "merge", expected: true},
+		{name: "merged with retained keys", fieldType: "array", mergeKey: "name", strategy: "merge,retainKeys", expected: true},
+		{name: "without a merge key", fieldType: "array", strategy: "merge"},
+		{name: "replaced", fieldType: "array", mergeKey: "name", strategy: "replace"},
+		{name: "not an array", fieldType: "object", mergeKey: "name", strategy: "merge"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := NewLiteralField("name", tc.fieldType, "desc", "", LiteralFieldOptPatch(tc.mergeKey, tc.strategy))
+			assert.Equal(t, tc.expected, f.IsListMap())
+		})
+	}
+}
+
 func TestReferenceField(t *testing.T) {
 	f := NewReferenceField("name", "desc", "ref")
 
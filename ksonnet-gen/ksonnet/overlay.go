@@ -0,0 +1,213 @@
+package ksonnet
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/google/go-jsonnet/ast"
+	nm "github.com/ksonnet/ksonnet-lib/ksonnet-gen/nodemaker"
+	"github.com/pkg/errors"
+)
+
+const (
+	// localOverlays is the name of the local containing the overlay helpers.
+	localOverlays = "overlays"
+
+	// overlaySource is the source of the functions which merge a patch into a
+	// value with strategic merge semantics. Objects are merged by field and
+	// fields which are null in the patch are removed. Lists with a merge key
+	// are merged by the key, and items with `$patch: 'delete'` remove the
+	// item with their key. Lists with the merge strategy but no merge key
+	// get the patch's items they don't contain, and other lists are
+	// replaced. How the fields of a definition are merged is looked up in
+	// `definitions`, which is generated from the patch extensions of the
+	// catalog.
+	overlaySource = `{
+  field(definition, name)::
+    local fields = if definition != null && std.objectHas(self.definitions, definition) then self.definitions[definition] else {};
+    if std.objectHas(fields, name) then fields[name] else {},
+
+  merge(base, patch, definition)::
+    local overlays = self;
+    self.mergeObject(base, patch, function(name) overlays.field(definition, name)),
+
+  mergeObject(base, patch, field)::
+    local overlays = self;
+    local current = if std.type(base) == 'object' then base else {};
+    if std.type(patch) != 'object' then patch
+    else
+      { [name]: current[name] for name in std.objectFields(current) if !std.objectHas(patch, name) } +
+      { [name]: overlays.mergeField(if std.objectHas(current, name) then current[name] else null, patch[name], field(name)) for name in std.objectFields(patch) if patch[name] != null },
+
+  mergeField(base, patch, field)::
+    local get(key) = if std.objectHas(field, key) then field[key] else null;
+    if std.type(base) == 'array' && std.type(patch) == 'array' then self.mergeArray(base, patch, field)
+    else if get('values') != null then self.mergeObject(base, patch, function(key) { ref: field.values })
+    else self.merge(base, patch, get('ref')),
+
+  mergeArray(base, patch, field)::
+    local ref = if std.objectHas(field, 'ref') then field.ref else null;
+    local missing = [item for item in patch if std.length(std.filter(function(b) b == item, base)) == 0];
+    if std.objectHas(field, 'mergeKey') then self.mergeList(base, patch, field.mergeKey, ref)
+    else if std.objectHas(field, 'merge') then base + missing
+    else patch,
+
+  mergeList(base, patch, key, definition)::
+    local overlays = self;
+    local keyOf(item) = if std.type(item) == 'object' && std.objectHas(item, key) then item[key] else null;
+    local isDelete(item) = std.type(item) == 'object' && std.objectHas(item, '$patch') && item['$patch'] == 'delete';
+    local patches(item) = [p for p in patch if keyOf(item) != null && keyOf(p) == keyOf(item)];
+    local inBase(p) = std.length([item for item in base if keyOf(p) != null && keyOf(item) == keyOf(p)]) > 0;
+    [if std.length(patches(item)) == 0 then item else overlays.merge(item, patches(item)[0], definition) for item in base if std.length(std.filter(isDelete, patches(item))) == 0] +
+    [overlays.merge(null, p, definition) for p in patch if !inBase(p) && !isDelete(p)],
+}`
+)
+
+// overlayHelpers creates the object containing the functions overlays use to
+// merge a patch into an object, and the descriptions of the fields of the
+// objects which aren't merged as plain objects.
+func overlayHelpers(objects []Object) (*nm.Object, error) {
+	node, err := nm.Parse("overlays", overlaySource)
+	if err != nil {
+		return nil, errors.Wrap(err, "parse overlay helpers")
+	}
+
+	o, ok := node.(*nm.Object)
+	if !ok {
+		return nil, errors.Errorf("overlay helpers are a %T, not an object", node)
+	}
+
+	o.Set(nm.NewKey("definitions"), overlayDefinitions(objects))
+
+	return o, nil
+}
+
+// overlayDefinitions describes how the fields of objects are merged, by the
+// objects' identifiers. Objects whose fields are all merged as plain objects
+// or replaced aren't described.
+func overlayDefinitions(objects []Object) *nm.Object {
+	sorted := append([]Object{}, objects...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Identifier() < sorted[j].Identifier()
+	})
+
+	definitions := nm.NewObject()
+	seen := make(map[string]bool)
+
+	for _, object := range sorted {
+		if seen[object.Identifier()] {
+			continue
+		}
+		seen[object.Identifier()] = true
+
+		props := object.Properties()
+
+		var names []string
+		for name := range props {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		fields := nm.NewObject()
+		for _, name := range names {
+			if field := overlayField(props[name]); field != nil {
+				fields.Set(nm.InheritedKey(name), field)
+			}
+		}
+
+		if len(fields.Keys()) > 0 {
+			definitions.Set(nm.InheritedKey(object.Identifier()), fields)
+		}
+	}
+
+	return definitions
+}
+
+// overlayField describes how a property is merged. e.g.
+// `{ref: 'io.k8s.api.core.v1.Container', mergeKey: 'name', merge: true}`
+// It returns nil if the property is merged as a plain object or replaced.
+func overlayField(p Property) *nm.Object {
+	field := nm.OnelineObject()
+
+	switch t := p.(type) {
+	case *ReferenceField:
+		field.Set(nm.InheritedKey("ref"), nm.NewStringDouble(t.Ref()))
+	case *LiteralField:
+		if t.Ref() != "" {
+			field.Set(nm.InheritedKey("ref"), nm.NewStringDouble(t.Ref()))
+		}
+
+		if mv := t.MapValue(); mv != nil && mv.Ref != "" {
+			field.Set(nm.InheritedKey("values"), nm.NewStringDouble(mv.Ref))
+		}
+
+		if t.FieldType() == "array" && stringInSlice("merge", strings.Split(t.PatchStrategy(), ",")) {
+			if t.MergeKey() != "" {
+				field.Set(nm.InheritedKey("mergeKey"), nm.NewStringDouble(t.MergeKey()))
+			}
+			field.Set(nm.InheritedKey("merge"), nm.NewBoolean(true))
+		}
+	}
+
+	if len(field.Keys()) == 0 {
+		return nil
+	}
+
+	return field
+}
+
+// overlayFunction creates the overlay function of a type, which merges a
+// patch into the object with the overlay helpers. Each property of the type
+// is merged into the inherited value of the field, and fields which are null
+// in the patch are hidden. Merged fields are forced visible, so a field hidden
+// by an earlier overlay is shown again. e.g.
+// `self + { [if std.objectHas(patch, 'spec') && patch.spec != null then 'spec']::: ... } +
+// (if std.objectHas(patch, 'spec') && patch.spec == null then { spec:: null } else {})`
+func overlayFunction(object Object) nm.Noder {
+	props := object.Properties()
+
+	var names []string
+	for name := range props {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	merged := nm.NewObject()
+	var removed []nm.Noder
+
+	for _, name := range names {
+		has := nm.ApplyCall("std.objectHas", nm.NewVar("patch"), nm.NewStringDouble(name))
+		value := nm.NewIndexExpr(nm.NewStringDouble(name))
+		value.SetTarget(nm.NewVar("patch"))
+
+		inherited := nm.NewConditional(
+			nm.NewInSuper(nm.NewStringDouble(name)),
+			nm.NewSuperIndexExpr(nm.NewStringDouble(name)),
+			&nm.Null{})
+
+		field := nm.ApplyCall(localOverlays+".field",
+			nm.NewStringDouble(object.Identifier()), nm.NewStringDouble(name))
+
+		set := nm.NewConditional(
+			nm.NewBinary(has, nm.NewBinary(value, &nm.Null{}, nm.BopNotEqual), nm.BopAnd),
+			nm.NewStringDouble(name),
+			&nm.Null{})
+		merged.Set(nm.NewKey("["+name+"]", nm.KeyOptExpr(set), nm.KeyOptVisibility(ast.ObjectFieldVisible)),
+			nm.ApplyCall(localOverlays+".mergeField", inherited, value, field))
+
+		hiddenField := nm.OnelineObject()
+		hiddenField.Set(nm.NewKey(name), &nm.Null{})
+
+		removed = append(removed, nm.NewParens(nm.NewConditional(
+			nm.NewBinary(has, nm.NewBinary(value, &nm.Null{}, nm.BopEqual), nm.BopAnd),
+			hiddenField,
+			nm.OnelineObject())))
+	}
+
+	body := nm.NewBinary(&nm.Self{}, merged, nm.BopPlus)
+	for _, r := range removed {
+		body = nm.NewBinary(body, r, nm.BopPlus)
+	}
+
+	return body
+}
@@ -0,0 +1,149 @@
+package ksonnet
+
+import (
+	"bytes"
+	"testing"
+
+	jsonnet "github.com/google/go-jsonnet"
+	nm "github.com/ksonnet/ksonnet-lib/ksonnet-gen/nodemaker"
+	"github.com/ksonnet/ksonnet-lib/ksonnet-gen/printer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_overlayField(t *testing.T) {
+	cases := []struct {
+		name     string
+		prop     Property
+		expected string
+	}{
+		{
+			name:     "reference",
+			prop:     NewReferenceField("spec", "desc", "io.k8s.api.apps.v1.DeploymentSpec"),
+			expected: "{ ref: 'io.k8s.api.apps.v1.DeploymentSpec' }",
+		},
+		{
+			name: "list map",
+			prop: NewLiteralField("containers", "array", "desc", "io.k8s.api.core.v1.Container",
+				LiteralFieldOptPatch("name", "merge")),
+			expected: "{ ref: 'io.k8s.api.core.v1.Container', mergeKey: 'name', merge: true }",
+		},
+		{
+			name:     "merged list",
+			prop:     NewLiteralField("finalizers", "array", "desc", "", LiteralFieldOptPatch("", "merge")),
+			expected: "{ merge: true }",
+		},
+		{
+			name: "map of objects",
+			prop: NewLiteralField("versions", "object", "desc", "",
+				LiteralFieldOptMapValue(&MapValue{Type: "object", Ref: "io.example.v1.Version"})),
+			expected: "{ values: 'io.example.v1.Version' }",
+		},
+		{
+			name: "replaced list",
+			prop: NewLiteralField("args", "array", "desc", "", LiteralFieldOptPatch("", "replace")),
+		},
+		{
+			name: "map of strings",
+			prop: NewLiteralField("labels", "object", "desc", "",
+				LiteralFieldOptMapValue(&MapValue{Type: "string"})),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			field := overlayField(tc.prop)
+			if tc.expected == "" {
+				require.Nil(t, field)
+				return
+			}
+
+			require.NotNil(t, field)
+
+			o := nm.NewObject()
+			o.Set(nm.InheritedKey("field"), field)
+
+			var buf bytes.Buffer
+			require.NoError(t, printer.Fprint(&buf, o.Node()))
+			require.Equal(t, "{\n  field: "+tc.expected+",\n}", buf.String())
+		})
+	}
+}
+
+func TestGenerateLib_overlay(t *testing.T) {
+	lib, err := GenerateLib("testdata/swagger-1.8.json")
+	require.NoError(t, err)
+
+	vm := jsonnet.MakeVM()
+	vm.Importer(&jsonnet.MemoryImporter{
+		Data: map[string]string{"k8s.libsonnet": string(lib.K8s)},
+	})
+
+	snippet := `
+local k8s = import 'k8s.libsonnet';
+local deployment = k8s.apps.v1beta2.deployment;
+local container = k8s.apps.v1beta2.deployment.mixin.spec.template.spec.containersType;
+
+local base = deployment.new('web', 2, [
+  container.new('web', 'web:1') + container.withEnv([{name: 'A', value: '1'}, {name: 'B', value: '2'}]),
+  container.new('proxy', 'proxy:1'),
+], {app: 'web'}) + deployment.mixin.metadata.withLabels({app: 'web', tier: 'frontend'});
+
+base + deployment.overlay({
+  metadata: {labels: {tier: null, env: 'prod'}},
+  spec: {
+    replicas: 3,
+    template: {spec: {containers: [
+      {name: 'web', image: 'web:2', env: [{name: 'B', value: '3'}, {name: 'C', value: '4'}]},
+      {name: 'proxy', '$patch': 'delete'},
+      {name: 'logger', image: 'logger:1'},
+    ]}},
+  },
+})
+`
+
+	out, err := vm.EvaluateSnippet("snippet", snippet)
+	require.NoError(t, err)
+
+	expected := `{
+  "apiVersion": "apps/v1beta2",
+  "kind": "Deployment",
+  "metadata": {"name": "web", "labels": {"app": "web", "env": "prod"}},
+  "spec": {
+    "replicas": 3,
+    "template": {
+      "metadata": {"labels": {"app": "web"}},
+      "spec": {
+        "containers": [
+          {
+            "name": "web",
+            "image": "web:2",
+            "env": [{"name": "A", "value": "1"}, {"name": "B", "value": "3"}, {"name": "C", "value": "4"}]
+          },
+          {"name": "logger", "image": "logger:1"}
+        ]
+      }
+    }
+  }
+}`
+	assert.JSONEq(t, expected, out)
+
+	out, err = vm.EvaluateSnippet("snippet", `
+local k8s = import 'k8s.libsonnet';
+local configMap = k8s.core.v1.configMap;
+
+configMap.new('config', {a: '1'}) + configMap.overlay({data: null})
+`)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "config"}}`, out)
+
+	// a field removed by an overlay is set again by a later one.
+	out, err = vm.EvaluateSnippet("snippet", `
+local k8s = import 'k8s.libsonnet';
+local configMap = k8s.core.v1.configMap;
+
+configMap.new('config', {a: '1'}) + configMap.overlay({data: null}) + configMap.overlay({data: {b: '2'}})
+`)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "config"}, "data": {"b": "2"}}`, out)
+}
@@ -1,18 +1,33 @@
 package ksonnet
 
 import (
+	"sort"
+
 	"github.com/go-openapi/spec"
 	"github.com/pkg/errors"
 )
 
-func parsePaths(apiSpec *spec.Swagger) (map[string]Component, error) {
+// parsePaths maps the definitions of request bodies to the component of
+// their operations. Definitions which are the bodies of operations on
+// different components (e.g. the body of PATCH requests) aren't mapped, and
+// are returned in order.
+func parsePaths(apiSpec *spec.Swagger) (map[string]Component, []string, error) {
 	m := make(map[string]Component)
+	ambiguous := make(map[string]bool)
 
 	if apiSpec.Paths == nil {
-		return nil, errors.New("api spec has zero paths")
+		return nil, nil, errors.New("api spec has zero paths")
 	}
 	paths := apiSpec.Paths.Paths
-	for _, pathItem := range paths {
+
+	var names []string
+	for name := range paths {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		pathItem := paths[name]
 		verbs := []*spec.Operation{pathItem.Post, pathItem.Patch, pathItem.Put}
 		for _, verb := range verbs {
 			if verb == nil {
@@ -34,23 +49,34 @@ func parsePaths(apiSpec *spec.Swagger) (map[string]Component, error) {
 			}
 
 			if body.Schema == nil {
-				return nil, errors.Errorf("invalid body parameter - missing required field: schema")
+				return nil, nil, errors.Errorf("invalid body parameter - missing required field: schema")
 			}
 			ref := extractRef(*body.Schema)
 
 			component, exists, err := pathExtensionComponent(verb.Extensions)
 			if err != nil {
-				return nil, errors.Wrapf(err, "extract component for %s", ref)
+				return nil, nil, errors.Wrapf(err, "extract component for %s", ref)
 			}
 
-			if exists {
-				m[ref] = component
+			if !exists {
+				continue
 			}
 
+			if existing, ok := m[ref]; ok && existing != component {
+				ambiguous[ref] = true
+			}
+			m[ref] = component
 		}
 	}
 
-	return m, nil
+	var refs []string
+	for ref := range ambiguous {
+		delete(m, ref)
+		refs = append(refs, ref)
+	}
+	sort.Strings(refs)
+
+	return m, refs, nil
 }
 
 // pathExtensionComponent generates a component from a method tpe extension
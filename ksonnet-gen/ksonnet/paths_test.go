@@ -10,7 +10,7 @@ import (
 func Test_parsePaths(t *testing.T) {
 	c := initCatalog(t, "swagger-1.8.json")
 
-	m, err := parsePaths(c.apiSpec)
+	m, _, err := parsePaths(c.apiSpec)
 	require.NoError(t, err)
 	require.NotNil(t, m)
 
@@ -22,3 +22,15 @@ func Test_parsePaths(t *testing.T) {
 
 	assert.Equal(t, expected, m["io.k8s.api.rbac.v1alpha1.ClusterRoleBinding"])
 }
+
+func Test_parsePaths_ambiguous(t *testing.T) {
+	c := initCatalog(t, "swagger-1.8.json")
+
+	m, ambiguous, err := parsePaths(c.apiSpec)
+	require.NoError(t, err)
+	assert.Contains(t, ambiguous, "io.k8s.apimachinery.pkg.apis.meta.v1.Patch")
+
+	// Patch is the body of the PATCH requests of every component.
+	_, ok := m["io.k8s.apimachinery.pkg.apis.meta.v1.Patch"]
+	assert.False(t, ok)
+}
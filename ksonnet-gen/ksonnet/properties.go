@@ -7,12 +7,6 @@ import (
 	"github.com/pkg/errors"
 )
 
-var (
-	recursiveRefs = []string{
-		"io.k8s.apiextensions-apiserver.pkg.apis.apiextensions.v1beta1.JSONSchemaProps",
-	}
-)
-
 func extractProperties(c *Catalog, properties map[string]spec.Schema, required []string) (map[string]Property, error) {
 	if c == nil {
 		return nil, errors.New("catalog is nil")
@@ -21,7 +15,7 @@ func extractProperties(c *Catalog, properties map[string]spec.Schema, required [
 	out := make(map[string]Property)
 
 	for name, schema := range properties {
-		if isSkippedProperty(name, schema) {
+		if c.isSkippedProperty(name, schema) {
 			if !stringInSlice(name, required) {
 				continue
 			}
@@ -29,26 +23,37 @@ func extractProperties(c *Catalog, properties map[string]spec.Schema, required [
 
 		ref := extractRef(schema)
 
-		if ref != "" && stringInSlice(ref, recursiveRefs) {
+		// recursive references can't be expanded inline, so they are set as
+		// objects. Their mixins are still available through the type alias.
+		if ref != "" && c.isRecursiveRef(ref) {
 			out[name] = NewLiteralField(name, "object", schema.Description, ref)
 			continue
 		}
 
 		// literal
 		if t := schema.Type; len(t) == 1 {
-			out[name] = buildLiteralField(t[0], name, schema)
+			mv, err := c.mapValue(schema)
+			if err != nil {
+				return nil, errors.Wrapf(err, "describe map values for %s", name)
+			}
+
+			mergeKey, strategy := patchExtensions(schema)
+
+			out[name] = buildLiteralField(t[0], name, schema,
+				LiteralFieldOptFormat(schema.Format), LiteralFieldOptMapValue(mv),
+				LiteralFieldOptPatch(mergeKey, strategy))
 			continue
 		}
 
-		ifr, err := c.isFormatRef(ref)
+		format, err := c.refFormat(ref)
 		if err != nil {
 			return nil, errors.Wrap(err, "check for format ref")
 		}
 
-		if ifr {
-			// don't have to check for existence here because isFormatRef does the same thing
+		if format != "" {
+			// don't have to check for existence here because refFormat does the same thing
 			formatSchema := c.apiSpec.Definitions[ref]
-			out[name] = buildLiteralField(fieldType(formatSchema), name, schema)
+			out[name] = buildLiteralField(fieldType(formatSchema), name, schema, LiteralFieldOptFormat(format))
 			continue
 		}
 
@@ -60,13 +65,67 @@ func extractProperties(c *Catalog, properties map[string]spec.Schema, required [
 	return out, nil
 }
 
-func buildLiteralField(fieldType, name string, schema spec.Schema) *LiteralField {
+func buildLiteralField(fieldType, name string, schema spec.Schema, opts ...LiteralFieldOpt) *LiteralField {
 	var itemRef string
 	if schema.Items != nil && schema.Items.Schema != nil {
-		itemRef = extractRef(*schema.Items.Schema)
+		items := *schema.Items.Schema
+		itemRef = extractRef(items)
+		if len(items.Type) == 1 {
+			opts = append(opts, LiteralFieldOptItemType(items.Type[0]))
+		}
+	}
+
+	return NewLiteralField(name, fieldType, schema.Description, itemRef, opts...)
+}
+
+// mapValue describes the values of a map schema (a schema with
+// additionalProperties). It returns nil if the schema is not a map.
+func (c *Catalog) mapValue(schema spec.Schema) (*MapValue, error) {
+	ap := schema.AdditionalProperties
+	if ap == nil || ap.Schema == nil {
+		return nil, nil
+	}
+
+	value := *ap.Schema
+
+	if t := value.Type; len(t) == 1 {
+		return &MapValue{Type: t[0], Format: value.Format}, nil
+	}
+
+	ref := extractRef(value)
+	if ref == "" {
+		return &MapValue{}, nil
+	}
+
+	format, err := c.refFormat(ref)
+	if err != nil {
+		return nil, errors.Wrap(err, "check for format ref")
+	}
+
+	if format != "" {
+		return &MapValue{Type: fieldType(c.apiSpec.Definitions[ref]), Format: format}, nil
+	}
+
+	return &MapValue{Type: "object", Ref: ref}, nil
+}
+
+// patchExtensions returns the patch merge key and patch strategy of a schema.
+func patchExtensions(schema spec.Schema) (string, string) {
+	mergeKey, _ := schema.Extensions[extensionPatchMergeKey].(string)
+	strategy, _ := schema.Extensions[extensionPatchStrategy].(string)
+
+	return mergeKey, strategy
+}
+
+// isSkippedProperty returns true if a property isn't rendered. The
+// Kubernetes conventions don't apply to generic APIs, whose properties are
+// only skipped if they are read only.
+func (c *Catalog) isSkippedProperty(name string, schema spec.Schema) bool {
+	if c.generic {
+		return schema.ReadOnly
 	}
 
-	return NewLiteralField(name, fieldType, schema.Description, itemRef)
+	return isSkippedProperty(name, schema)
 }
 
 func isSkippedProperty(name string, schema spec.Schema) bool {
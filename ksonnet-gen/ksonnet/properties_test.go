@@ -40,6 +40,15 @@ func Test_extractProperties_literal(t *testing.T) {
 	assert.Equal(t, "The name of the cluster which the object belongs to. This is used to distinguish resources with same name and namespace in different clusters. This field is not set anywhere right now and apiserver is going to ignore it if set in create or update request.", prop.Description())
 	assert.Equal(t, "", prop.Ref())
 	assert.Equal(t, "clusterName", prop.Name())
+
+	prop, ok = props["finalizers"].(*LiteralField)
+	require.True(t, ok)
+	assert.Equal(t, "array", prop.FieldType())
+	assert.Equal(t, "string", prop.ItemType())
+
+	prop, ok = props["ownerReferences"].(*LiteralField)
+	require.True(t, ok)
+	assert.Equal(t, "", prop.ItemType())
 }
 
 func Test_extractProperties_json_schema_props(t *testing.T) {
@@ -115,6 +124,136 @@ func Test_extractProperties_type_ref(t *testing.T) {
 	assert.Equal(t, "maxSurge", prop.Name())
 }
 
+func Test_extractProperties_format_ref(t *testing.T) {
+	c := initCatalog(t, "swagger-1.8.json")
+
+	cases := []struct {
+		name       string
+		definition string
+		property   string
+		format     string
+	}{
+		{
+			name:       "int-or-string",
+			definition: "io.k8s.api.core.v1.ServicePort",
+			property:   "targetPort",
+			format:     formatIntOrString,
+		},
+		{
+			name:       "quantity",
+			definition: "io.k8s.api.core.v1.EmptyDirVolumeSource",
+			property:   "sizeLimit",
+			format:     formatQuantity,
+		},
+		{
+			name:       "time",
+			definition: "io.k8s.api.core.v1.Taint",
+			property:   "timeAdded",
+			format:     formatDateTime,
+		},
+		{
+			name:       "literal with a format",
+			definition: "io.k8s.api.core.v1.ServicePort",
+			property:   "port",
+			format:     "int32",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s, ok := c.apiSpec.Definitions[tc.definition]
+			require.True(t, ok)
+
+			props, err := extractProperties(c, s.Properties, s.Required)
+			require.NoError(t, err)
+
+			prop, ok := props[tc.property].(*LiteralField)
+			require.True(t, ok)
+
+			assert.Equal(t, tc.format, prop.Format())
+		})
+	}
+}
+
+func Test_extractProperties_map(t *testing.T) {
+	c := initCatalog(t, "swagger-1.8.json")
+
+	cases := []struct {
+		name       string
+		definition string
+		property   string
+		expected   *MapValue
+	}{
+		{
+			name:       "map of strings",
+			definition: "io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta",
+			property:   "labels",
+			expected:   &MapValue{Type: "string"},
+		},
+		{
+			name:       "map of quantities",
+			definition: "io.k8s.api.core.v1.ResourceRequirements",
+			property:   "limits",
+			expected:   &MapValue{Type: "string", Format: formatQuantity},
+		},
+		{
+			name:       "map of times",
+			definition: "io.k8s.api.policy.v1beta1.PodDisruptionBudgetStatus",
+			property:   "disruptedPods",
+			expected:   &MapValue{Type: "string", Format: formatDateTime},
+		},
+		{
+			name:       "map of references",
+			definition: "io.k8s.apiextensions-apiserver.pkg.apis.apiextensions.v1beta1.JSONSchemaProps",
+			property:   "properties",
+			expected: &MapValue{
+				Type: "object",
+				Ref:  "io.k8s.apiextensions-apiserver.pkg.apis.apiextensions.v1beta1.JSONSchemaProps",
+			},
+		},
+		{
+			name:       "not a map",
+			definition: "io.k8s.api.core.v1.ServicePort",
+			property:   "name",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s, ok := c.apiSpec.Definitions[tc.definition]
+			require.True(t, ok)
+
+			props, err := extractProperties(c, s.Properties, s.Required)
+			require.NoError(t, err)
+
+			prop, ok := props[tc.property].(*LiteralField)
+			require.True(t, ok)
+
+			assert.Equal(t, tc.expected, prop.MapValue())
+		})
+	}
+}
+
+func Test_extractProperties_patch(t *testing.T) {
+	c := initCatalog(t, "swagger-1.8.json")
+
+	s, ok := c.apiSpec.Definitions["io.k8s.api.core.v1.Container"]
+	require.True(t, ok)
+
+	props, err := extractProperties(c, s.Properties, s.Required)
+	require.NoError(t, err)
+
+	ports, ok := props["ports"].(*LiteralField)
+	require.True(t, ok)
+	assert.Equal(t, "containerPort", ports.MergeKey())
+	assert.Equal(t, "merge", ports.PatchStrategy())
+	assert.True(t, ports.IsListMap())
+
+	args, ok := props["args"].(*LiteralField)
+	require.True(t, ok)
+	assert.False(t, args.IsListMap())
+}
+
 func Test_extractProperties_ref(t *testing.T) {
 	c := initCatalog(t, "swagger-1.8.json")
 
@@ -180,3 +319,65 @@ func Test_fieldType(t *testing.T) {
 		})
 	}
 }
+
+func Test_extractProperties_recursive_ref(t *testing.T) {
+	c := initCatalog(t, "recursive.json")
+
+	cases := []struct {
+		name       string
+		definition string
+		property   string
+		isLiteral  bool
+	}{
+		{
+			name:       "reference into a cycle",
+			definition: "io.k8s.api.example.v1.Root",
+			property:   "node",
+			isLiteral:  true,
+		},
+		{
+			name:       "reference outside of a cycle",
+			definition: "io.k8s.api.example.v1.Root",
+			property:   "leaf",
+		},
+		{
+			name:       "self reference",
+			definition: "io.k8s.api.example.v1.Node",
+			property:   "child",
+			isLiteral:  true,
+		},
+		{
+			name:       "mutual reference",
+			definition: "io.k8s.api.example.v1.Peer",
+			property:   "node",
+			isLiteral:  true,
+		},
+		{
+			name:       "reference out of a cycle",
+			definition: "io.k8s.api.example.v1.Peer",
+			property:   "target",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s, ok := c.apiSpec.Definitions[tc.definition]
+			require.True(t, ok)
+
+			props, err := extractProperties(c, s.Properties, s.Required)
+			require.NoError(t, err)
+
+			i, ok := props[tc.property]
+			require.True(t, ok)
+
+			if tc.isLiteral {
+				prop, ok := i.(*LiteralField)
+				require.True(t, ok)
+				assert.Equal(t, "object", prop.FieldType())
+			} else {
+				_, ok := i.(*ReferenceField)
+				require.True(t, ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,361 @@
+package ksonnet
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ksonnet/ksonnet-lib/ksonnet-gen/kubeversion"
+	"github.com/pkg/errors"
+)
+
+// GeneratorVersion is the version of ksonnet-gen recorded in the provenance
+// of generated libs. It can be set when building ksonnet-gen with
+// `-ldflags "-X github.com/ksonnet/ksonnet-lib/ksonnet-gen/ksonnet.GeneratorVersion=<version>"`.
+var GeneratorVersion = "dev"
+
+const (
+	provenanceTitle = "// Generated by ksonnet-gen. DO NOT EDIT."
+
+	optionDeprecatedIdentifiers = "deprecated-identifiers"
+	optionExtensionHelpers      = "extension-helpers"
+	optionGenericRoots          = "generic-roots"
+	optionJSONSchemas           = "json-schema"
+	optionNaming                = "naming"
+	optionNamePattern           = "name-pattern"
+	optionTypeScript            = "typescript"
+	optionVersionData           = "version-data"
+)
+
+// jsonProvenancePrefix starts a JSON file stamped with a provenance.
+const jsonProvenancePrefix = "{\n  \"$comment\": "
+
+// Provenance records how a lib was generated. It is written as a header of
+// comments at the top of each generated file, or as the $comment of a JSON
+// Schema.
+type Provenance struct {
+	// Generator is the version of ksonnet-gen.
+	Generator string
+	// KubernetesVersion is the Kubernetes version of the swagger spec.
+	KubernetesVersion string
+	// APIVersion is the version of the swagger spec of a generic API. It is
+	// set instead of KubernetesVersion.
+	APIVersion string
+	// Checksum is the checksum of the swagger spec.
+	Checksum string
+	// Options are the options which change the generated lib, formatted as
+	// `name=value`. (e.g. deprecated-identifiers=true)
+	Options []string
+	// OptionsHash is a hash of the options.
+	OptionsHash string
+	// ContentHash is a hash of the contents of the file following the header.
+	// It is only set for the provenance of a file.
+	ContentHash string
+}
+
+// newProvenance creates the provenance of a lib. The lib of a generic API
+// doesn't have an Extension, so e is nil.
+func newProvenance(c *Catalog, e *Extension) (*Provenance, error) {
+	versionData := "none"
+	if !c.generic {
+		checksum, err := c.versionData.Checksum(c.Version())
+		if err != nil {
+			if _, isUnknown := err.(*kubeversion.UnknownVersionError); !isUnknown {
+				return nil, errors.Wrap(err, "checksum version data")
+			}
+		} else {
+			versionData = checksum
+		}
+	}
+
+	var helpers []string
+	if e != nil {
+		for _, helper := range e.helpers {
+			helpers = append(helpers, helper.Name)
+		}
+	}
+
+	options := []string{
+		fmt.Sprintf("%s=%t", optionDeprecatedIdentifiers, c.deprecatedIdentifiers),
+		fmt.Sprintf("%s=%s", optionExtensionHelpers, strings.Join(helpers, ",")),
+		fmt.Sprintf("%s=%s", optionVersionData, versionData),
+		fmt.Sprintf("%s=%t", optionJSONSchemas, c.jsonSchemas),
+		fmt.Sprintf("%s=%t", optionTypeScript, c.typeScript),
+	}
+
+	for _, p := range c.namePatterns {
+		options = append(options, fmt.Sprintf("%s=%s", optionNamePattern, p))
+	}
+
+	if c.generic {
+		options = append(options, fmt.Sprintf("%s=%s", optionGenericRoots, strings.Join(c.roots, ",")))
+	}
+
+	// a naming strategy is code, so only the fact it was used is recorded.
+	if c.customNaming {
+		options = append(options, fmt.Sprintf("%s=custom", optionNaming))
+	}
+
+	p := &Provenance{
+		Generator:   GeneratorVersion,
+		Checksum:    c.Checksum(),
+		Options:     options,
+		OptionsHash: hash([]byte(strings.Join(options, "\n"))),
+	}
+	if c.generic {
+		p.APIVersion = c.Version()
+	} else {
+		p.KubernetesVersion = c.Version()
+	}
+
+	return p, nil
+}
+
+// Stamp prefixes the contents of a generated file with the provenance header.
+func (p *Provenance) Stamp(content []byte) []byte {
+	stamped := *p
+	stamped.ContentHash = hash(content)
+
+	var buf bytes.Buffer
+	buf.WriteString(stamped.header())
+	buf.Write(content)
+
+	return buf.Bytes()
+}
+
+// stampJSON adds the provenance to a generated JSON object as its $comment,
+// which JSON Schema ignores. It is the first key of the object, where
+// json.MarshalIndent would sort it.
+func (p *Provenance) stampJSON(content []byte) []byte {
+	stamped := *p
+	stamped.ContentHash = hash(content)
+
+	comment, _ := json.Marshal(strings.TrimSuffix(stamped.header(), "\n\n"))
+
+	var buf bytes.Buffer
+	buf.WriteString(jsonProvenancePrefix)
+	buf.Write(comment)
+	buf.WriteString(",\n")
+	buf.Write(bytes.TrimPrefix(content, []byte("{\n")))
+
+	return buf.Bytes()
+}
+
+func (p *Provenance) header() string {
+	version := "// kubernetesVersion: " + p.KubernetesVersion
+	if p.APIVersion != "" {
+		version = "// apiVersion: " + p.APIVersion
+	}
+
+	lines := []string{
+		provenanceTitle,
+		"//",
+		"// generator: " + p.Generator,
+		version,
+		"// checksum: " + p.Checksum,
+		"// options: " + strings.Join(p.Options, " "),
+		"// optionsHash: " + p.OptionsHash,
+		"// contentHash: " + p.ContentHash,
+	}
+
+	return strings.Join(lines, "\n") + "\n\n"
+}
+
+// CatalogOpts returns the Catalog options recorded in the provenance.
+func (p *Provenance) CatalogOpts() ([]CatalogOpt, error) {
+	var opts []CatalogOpt
+
+	for _, option := range p.Options {
+		parts := strings.SplitN(option, "=", 2)
+		if len(parts) != 2 {
+			return nil, errors.Errorf("invalid option %q", option)
+		}
+
+		switch parts[0] {
+		case optionDeprecatedIdentifiers:
+			deprecated, err := strconv.ParseBool(parts[1])
+			if err != nil {
+				return nil, errors.Wrapf(err, "parse option %s", parts[0])
+			}
+			opts = append(opts, CatalogOptDeprecatedIdentifiers(deprecated))
+		case optionNamePattern:
+			p, err := ParseNamePattern(parts[1])
+			if err != nil {
+				return nil, errors.Wrapf(err, "parse option %s", parts[0])
+			}
+			opts = append(opts, CatalogOptNamePatterns(p))
+		case optionGenericRoots:
+			opts = append(opts, CatalogOptGeneric(strings.Split(parts[1], ",")...))
+		case optionJSONSchemas, optionTypeScript:
+			enabled, err := strconv.ParseBool(parts[1])
+			if err != nil {
+				return nil, errors.Wrapf(err, "parse option %s", parts[0])
+			}
+			if parts[0] == optionJSONSchemas {
+				opts = append(opts, CatalogOptJSONSchemas(enabled))
+			} else {
+				opts = append(opts, CatalogOptTypeScript(enabled))
+			}
+		case optionNaming:
+			return nil, errors.New("lib was generated with a custom naming strategy, which can't be recorded")
+		}
+	}
+
+	return opts, nil
+}
+
+// ParseProvenance parses the provenance header of a generated file. It
+// returns the provenance and the contents following the header.
+func ParseProvenance(b []byte) (*Provenance, []byte, error) {
+	end := bytes.Index(b, []byte("\n\n"))
+	if !bytes.HasPrefix(b, []byte(provenanceTitle+"\n")) || end == -1 {
+		return nil, nil, errors.New("file does not have a provenance header")
+	}
+
+	p := &Provenance{}
+	fields := map[string]*string{
+		"generator":         &p.Generator,
+		"kubernetesVersion": &p.KubernetesVersion,
+		"apiVersion":        &p.APIVersion,
+		"checksum":          &p.Checksum,
+		"optionsHash":       &p.OptionsHash,
+		"contentHash":       &p.ContentHash,
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(b[:end]))
+	for scanner.Scan() {
+		line := strings.TrimPrefix(scanner.Text(), "// ")
+		parts := strings.SplitN(line, ": ", 2)
+		if len(parts) != 2 {
+			continue
+		}
+
+		if parts[0] == "options" {
+			p.Options = strings.Fields(parts[1])
+			continue
+		}
+
+		if field, ok := fields[parts[0]]; ok {
+			*field = parts[1]
+		}
+	}
+
+	return p, b[end+2:], nil
+}
+
+// parseJSONProvenance parses the provenance of a JSON file stamped with
+// stampJSON. It returns the provenance and the contents without it.
+func parseJSONProvenance(b []byte) (*Provenance, []byte, error) {
+	rest := bytes.TrimPrefix(b, []byte(jsonProvenancePrefix))
+	end := bytes.IndexByte(rest, '\n')
+	if len(rest) == len(b) || end == -1 {
+		return nil, nil, errors.New("file does not have a provenance comment")
+	}
+
+	var comment string
+	if err := json.Unmarshal(bytes.TrimSuffix(rest[:end], []byte(",")), &comment); err != nil {
+		return nil, nil, errors.Wrap(err, "parse provenance comment")
+	}
+
+	p, _, err := ParseProvenance([]byte(comment + "\n\n"))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return p, append([]byte("{\n"), rest[end+1:]...), nil
+}
+
+// Verify checks that the files of a lib are unmodified and that they are
+// the same, byte for byte, as the files of a lib generated from the swagger
+// spec at source with the options recorded in their provenance. The files
+// are keyed by their path in the lib's directory, like the files of
+// Lib.Files, so its TypeScript declarations and JSON Schemas are checked
+// too, and every file of the regenerated lib has to be there.
+func Verify(source string, files map[string][]byte) error {
+	main := "k8s.libsonnet"
+	if _, ok := files[main]; !ok {
+		main = "lib.libsonnet"
+	}
+
+	var names []string
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var recorded *Provenance
+	for _, name := range names {
+		parse := ParseProvenance
+		if strings.HasSuffix(name, ".json") {
+			parse = parseJSONProvenance
+		}
+
+		p, content, err := parse(files[name])
+		if err != nil {
+			return errors.Wrapf(err, "read provenance of %s", name)
+		}
+
+		if hash(content) != p.ContentHash {
+			return errors.Errorf("%s was modified after it was generated", name)
+		}
+
+		if name == main {
+			recorded = p
+		}
+	}
+
+	if recorded == nil {
+		return errors.New("lib does not have k8s.libsonnet or lib.libsonnet")
+	}
+
+	opts, err := recorded.CatalogOpts()
+	if err != nil {
+		return err
+	}
+
+	lib, err := GenerateLib(source, opts...)
+	if err != nil {
+		return errors.Wrap(err, "regenerate lib")
+	}
+
+	current := lib.Provenance
+	switch {
+	case current.Checksum != recorded.Checksum:
+		return errors.Errorf("lib was generated from a spec with checksum %s, but %s has checksum %s",
+			recorded.Checksum, source, current.Checksum)
+	case current.Generator != recorded.Generator:
+		return errors.Errorf("lib was generated by ksonnet-gen %s, but this is ksonnet-gen %s",
+			recorded.Generator, current.Generator)
+	case current.OptionsHash != recorded.OptionsHash:
+		return errors.Errorf("lib was generated with options %s, but the current options are %s",
+			strings.Join(recorded.Options, " "), strings.Join(current.Options, " "))
+	}
+
+	regenerated := lib.Files()
+	for _, name := range names {
+		if _, ok := regenerated[name]; !ok {
+			return errors.Errorf("%s is not a file of the regenerated lib", name)
+		}
+	}
+
+	for name, content := range regenerated {
+		if _, ok := files[name]; !ok {
+			return errors.Errorf("%s of the regenerated lib is missing", name)
+		}
+
+		if !bytes.Equal(content, files[name]) {
+			return errors.Errorf("%s does not match the regenerated lib", name)
+		}
+	}
+
+	return nil
+}
+
+func hash(b []byte) string {
+	return fmt.Sprintf("%x", sha256.Sum256(b))
+}
@@ -0,0 +1,153 @@
+package ksonnet
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProvenance(t *testing.T) {
+	lib, err := GenerateLib("testdata/swagger-1.8.json", CatalogOptDeprecatedIdentifiers(false))
+	require.NoError(t, err)
+
+	for _, file := range [][]byte{lib.K8s, lib.Extensions} {
+		p, content, err := ParseProvenance(file)
+		require.NoError(t, err)
+
+		assert.Equal(t, GeneratorVersion, p.Generator)
+		assert.Equal(t, "1.8.0", p.KubernetesVersion)
+		assert.Equal(t, lib.Provenance.Checksum, p.Checksum)
+		assert.Equal(t, lib.Provenance.Options, p.Options)
+		assert.Equal(t, lib.Provenance.OptionsHash, p.OptionsHash)
+		assert.Equal(t, hash(content), p.ContentHash)
+		assert.Equal(t, file, p.Stamp(content))
+	}
+
+	require.Len(t, lib.Provenance.Options, 5)
+	assert.Equal(t, "deprecated-identifiers=false", lib.Provenance.Options[0])
+
+	opts, err := lib.Provenance.CatalogOpts()
+	require.NoError(t, err)
+	require.Len(t, opts, 3)
+
+	_, _, err = ParseProvenance([]byte("{}\n"))
+	require.Error(t, err)
+}
+
+func TestVerify(t *testing.T) {
+	source := "testdata/swagger-1.8.json"
+
+	lib, err := GenerateLib(source, CatalogOptDeprecatedIdentifiers(true))
+	require.NoError(t, err)
+
+	require.NoError(t, Verify(source, lib.Files()))
+
+	modified := lib.Files()
+	modified["k.libsonnet"] = append(append([]byte{}, lib.Extensions...), '\n')
+	require.Error(t, Verify(source, modified))
+
+	_, content, err := ParseProvenance(lib.K8s)
+	require.NoError(t, err)
+	unstamped := lib.Files()
+	unstamped["k8s.libsonnet"] = content
+	require.Error(t, Verify(source, unstamped))
+
+	// the options are changed, but the content still matches its hash.
+	changed := lib.Files()
+	changed["k8s.libsonnet"] = bytes.Replace(lib.K8s, []byte("deprecated-identifiers=true"), []byte("deprecated-identifiers=false"), 1)
+	require.Error(t, Verify(source, changed))
+
+	require.Error(t, Verify("testdata/recursive.json", lib.Files()))
+}
+
+func TestVerify_type_definitions(t *testing.T) {
+	source := "testdata/generic.json"
+	opts := []CatalogOpt{CatalogOptGeneric(genericRoots...), CatalogOptJSONSchemas(true), CatalogOptTypeScript(true)}
+
+	lib, err := GenerateLib(source, opts...)
+	require.NoError(t, err)
+	assert.Contains(t, lib.Provenance.Options, "json-schema=true")
+	assert.Contains(t, lib.Provenance.Options, "typescript=true")
+
+	files := lib.Files()
+	require.Contains(t, files, "lib.d.ts")
+	require.Contains(t, files, "schemas/Pet.json")
+	require.NoError(t, Verify(source, files))
+
+	p, content, err := parseJSONProvenance(files["schemas/Pet.json"])
+	require.NoError(t, err)
+	assert.Equal(t, lib.Provenance.OptionsHash, p.OptionsHash)
+	assert.Equal(t, hash(content), p.ContentHash)
+	assert.Equal(t, files["schemas/Pet.json"], p.stampJSON(content))
+
+	var schema map[string]interface{}
+	require.NoError(t, json.Unmarshal(files["schemas/Pet.json"], &schema))
+	assert.Equal(t, "Pet", schema["title"])
+
+	cases := []struct {
+		name   string
+		modify func(files map[string][]byte)
+	}{
+		{
+			name: "modified TypeScript declarations",
+			modify: func(files map[string][]byte) {
+				files["lib.d.ts"] = append(append([]byte{}, files["lib.d.ts"]...), '\n')
+			},
+		},
+		{
+			name: "modified JSON Schema",
+			modify: func(files map[string][]byte) {
+				files["schemas/Pet.json"] = bytes.Replace(files["schemas/Pet.json"], []byte(`"Pet"`), []byte(`"Dog"`), 1)
+			},
+		},
+		{
+			name: "JSON Schema without a provenance",
+			modify: func(files map[string][]byte) {
+				files["schemas/Pet.json"] = content
+			},
+		},
+		{
+			name: "missing JSON Schema",
+			modify: func(files map[string][]byte) {
+				delete(files, "schemas/Pet.json")
+			},
+		},
+		{
+			name: "missing TypeScript declarations",
+			modify: func(files map[string][]byte) {
+				delete(files, "lib.d.ts")
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			files := lib.Files()
+			tc.modify(files)
+			require.Error(t, Verify(source, files))
+		})
+	}
+
+	// a lib generated without type definitions doesn't have them.
+	plain, err := GenerateLib(source, CatalogOptGeneric(genericRoots...))
+	require.NoError(t, err)
+	files = plain.Files()
+	files["lib.d.ts"] = lib.TypeScript
+	require.Error(t, Verify(source, files))
+}
+
+func TestVerify_name_patterns(t *testing.T) {
+	source := "testdata/name-patterns.json"
+
+	p, err := ParseNamePattern(`^io\.example\.(?P<group>\w+)\.(?P<version>v\w+)\.(?P<kind>\w+)$`)
+	require.NoError(t, err)
+
+	lib, err := GenerateLib(source, CatalogOptNamePatterns(p))
+	require.NoError(t, err)
+	assert.Contains(t, lib.Provenance.Options, "name-pattern="+p.String())
+
+	require.NoError(t, Verify(source, lib.Files()))
+}
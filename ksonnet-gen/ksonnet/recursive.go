@@ -0,0 +1,132 @@
+package ksonnet
+
+import (
+	"sort"
+
+	"github.com/go-openapi/spec"
+)
+
+// findRecursiveDefinitions returns the set of definitions which can reach
+// themselves by following property references. A reference to one of these
+// definitions can't be expanded inline, because the expansion would never
+// terminate.
+func findRecursiveDefinitions(definitions spec.Definitions) map[string]bool {
+	graph := make(map[string][]string)
+	for name, schema := range definitions {
+		graph[name] = definitionRefs(schema)
+	}
+
+	t := newTarjan(graph)
+
+	out := make(map[string]bool)
+	for _, scc := range t.components() {
+		if len(scc) > 1 {
+			for _, name := range scc {
+				out[name] = true
+			}
+			continue
+		}
+
+		name := scc[0]
+		if stringInSlice(name, graph[name]) {
+			out[name] = true
+		}
+	}
+
+	return out
+}
+
+// definitionRefs returns the sorted definitions a schema references
+// directly through its properties.
+func definitionRefs(schema spec.Schema) []string {
+	var refs []string
+	for _, prop := range schema.Properties {
+		if ref := extractRef(prop); ref != "" && !stringInSlice(ref, refs) {
+			refs = append(refs, ref)
+		}
+	}
+
+	sort.Strings(refs)
+	return refs
+}
+
+// tarjan finds strongly connected components in a reference graph.
+type tarjan struct {
+	graph   map[string][]string
+	index   map[string]int
+	lowLink map[string]int
+	onStack map[string]bool
+	stack   []string
+	counter int
+	sccs    [][]string
+}
+
+func newTarjan(graph map[string][]string) *tarjan {
+	return &tarjan{
+		graph:   graph,
+		index:   make(map[string]int),
+		lowLink: make(map[string]int),
+		onStack: make(map[string]bool),
+	}
+}
+
+// components returns the strongly connected components of the graph.
+func (t *tarjan) components() [][]string {
+	var names []string
+	for name := range t.graph {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if _, ok := t.index[name]; !ok {
+			t.connect(name)
+		}
+	}
+
+	return t.sccs
+}
+
+func (t *tarjan) connect(name string) {
+	t.index[name] = t.counter
+	t.lowLink[name] = t.counter
+	t.counter++
+	t.stack = append(t.stack, name)
+	t.onStack[name] = true
+
+	for _, ref := range t.graph[name] {
+		if _, ok := t.graph[ref]; !ok {
+			// dangling references are reported when the property is extracted.
+			continue
+		}
+
+		if _, ok := t.index[ref]; !ok {
+			t.connect(ref)
+			if t.lowLink[ref] < t.lowLink[name] {
+				t.lowLink[name] = t.lowLink[ref]
+			}
+		} else if t.onStack[ref] && t.index[ref] < t.lowLink[name] {
+			t.lowLink[name] = t.index[ref]
+		}
+	}
+
+	if t.lowLink[name] != t.index[name] {
+		return
+	}
+
+	var scc []string
+	for {
+		n := len(t.stack) - 1
+		cur := t.stack[n]
+		t.stack = t.stack[:n]
+		t.onStack[cur] = false
+		scc = append(scc, cur)
+
+		if cur == name {
+			break
+		}
+	}
+
+	sort.Strings(scc)
+	t.sccs = append(t.sccs, scc)
+}
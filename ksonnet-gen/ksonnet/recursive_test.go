@@ -0,0 +1,48 @@
+package ksonnet
+
+import (
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_findRecursiveDefinitions(t *testing.T) {
+	ref := func(name string) spec.Schema {
+		return *spec.RefProperty("#/definitions/" + name)
+	}
+
+	definitions := spec.Definitions{
+		"self": {SchemaProps: spec.SchemaProps{
+			Properties: map[string]spec.Schema{"self": ref("self")},
+		}},
+		"a": {SchemaProps: spec.SchemaProps{
+			Properties: map[string]spec.Schema{"b": ref("b")},
+		}},
+		"b": {SchemaProps: spec.SchemaProps{
+			Properties: map[string]spec.Schema{"c": ref("c")},
+		}},
+		"c": {SchemaProps: spec.SchemaProps{
+			Properties: map[string]spec.Schema{"a": ref("a"), "plain": ref("plain")},
+		}},
+		"entry": {SchemaProps: spec.SchemaProps{
+			Properties: map[string]spec.Schema{"a": ref("a"), "missing": ref("missing")},
+		}},
+		"plain": {SchemaProps: spec.SchemaProps{
+			Properties: map[string]spec.Schema{"name": *spec.StringProperty()},
+		}},
+		"array": {SchemaProps: spec.SchemaProps{
+			Properties: map[string]spec.Schema{"items": *spec.ArrayProperty(spec.RefSchema("#/definitions/array"))},
+		}},
+	}
+
+	got := findRecursiveDefinitions(definitions)
+
+	expected := map[string]bool{
+		"self": true,
+		"a":    true,
+		"b":    true,
+		"c":    true,
+	}
+	require.Equal(t, expected, got)
+}
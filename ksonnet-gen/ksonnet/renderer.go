@@ -5,6 +5,7 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/google/go-jsonnet/ast"
 	nm "github.com/ksonnet/ksonnet-lib/ksonnet-gen/nodemaker"
 	"github.com/pkg/errors"
 )
@@ -16,32 +17,71 @@ type renderer interface {
 
 type baseRenderer struct {
 	name        string
+	id          string
+	deprecated  []string
 	description string
 	parent      string
 	ref         string
+	describe    describeFn
+}
+
+// describeFn describes the definition a type alias refers to.
+type describeFn func(name string) (*Description, error)
+
+// aliasRenderer is a renderer which renders a type alias.
+type aliasRenderer interface {
+	renderer
+	describeWith(fn describeFn)
 }
 
 func newBaseRenderer(field Property, parent string) baseRenderer {
+	id, deprecated := propertyIdentifiers(field)
+
 	return baseRenderer{
 		name:        field.Name(),
+		id:          id,
+		deprecated:  deprecated,
 		description: field.Description(),
 		parent:      parent,
 		ref:         field.Ref(),
+		describe:    ParseDescription,
 	}
 }
 
+// describeWith sets the function which describes the definition of the type
+// alias.
+func (r *baseRenderer) describeWith(fn describeFn) {
+	r.describe = fn
+}
+
 func (r *baseRenderer) setter() string {
-	return fieldName(r.name, false)
+	return fieldName(r.id, false)
 }
 
 func (r *baseRenderer) mixin() string {
-	return fieldName(r.name, true)
+	return fieldName(r.id, true)
+}
+
+// renderDeprecated renders the deprecated identifiers of the field as aliases
+// of its setter, and of its mixin if the field has one.
+func (r *baseRenderer) renderDeprecated(container *nm.Object, hasMixin bool) {
+	args := []string{FormatKind(r.name)}
+
+	for _, id := range r.deprecated {
+		setter := fmt.Sprintf("with%s", strings.Title(id))
+		setDeprecatedAlias(container, setter, r.setter(), args)
+
+		if hasMixin {
+			setDeprecatedAlias(container, setter+"Mixin", r.mixin(), args)
+		}
+	}
 }
 
 // LiteralFieldRenderer renders a literal field.
 type LiteralFieldRenderer struct {
 	lf         *LiteralField
 	parentName string
+	describe   describeFn
 }
 
 // NewLiteralFieldRenderer creates an instance of LiteralField.
@@ -49,6 +89,7 @@ func NewLiteralFieldRenderer(lf *LiteralField, parentName string) *LiteralFieldR
 	return &LiteralFieldRenderer{
 		lf:         lf,
 		parentName: parentName,
+		describe:   ParseDescription,
 	}
 }
 
@@ -56,20 +97,116 @@ func NewLiteralFieldRenderer(lf *LiteralField, parentName string) *LiteralFieldR
 func (r *LiteralFieldRenderer) Render(container *nm.Object) error {
 	var rndr renderer
 
+	switch r.lf.Format() {
+	case formatIntOrString:
+		rndr = NewIntOrStringRenderer(r.lf, r.parentName)
+	case formatQuantity:
+		rndr = NewQuantityRenderer(r.lf, r.parentName)
+	case formatDateTime:
+		rndr = NewTimeRenderer(r.lf, r.parentName)
+	}
+
+	if rndr != nil {
+		return r.render(rndr, container)
+	}
+
 	switch ft := r.lf.FieldType(); ft {
 	case "array":
-		rndr = NewArrayRenderer(r.lf, r.parentName)
+		if r.lf.IsListMap() {
+			rndr = NewListMapRenderer(r.lf, r.parentName)
+		} else {
+			rndr = NewArrayRenderer(r.lf, r.parentName)
+		}
 	case "object":
-		rndr = NewObjectRenderer(r.lf, r.parentName)
+		if mv := r.lf.MapValue(); mv != nil && mv.Format == formatQuantity && isResourceRequirement(r.lf, r.parentName) {
+			rndr = NewQuantityMapRenderer(r.lf, r.parentName)
+		} else if mv != nil {
+			rndr = NewMapRenderer(r.lf, r.parentName)
+		} else {
+			rndr = NewObjectRenderer(r.lf, r.parentName)
+		}
 	case "string", "boolean", "integer", "number":
 		rndr = NewItemRenderer(r.lf, r.parentName)
 	default:
 		return errors.Errorf("unknown literal field type %s", ft)
 	}
 
+	return r.render(rndr, container)
+}
+
+// render renders the field with the renderer for its type, which describes
+// type aliases the same way as r.
+func (r *LiteralFieldRenderer) render(rndr renderer, container *nm.Object) error {
+	if ar, ok := rndr.(aliasRenderer); ok {
+		ar.describeWith(r.describe)
+	}
+
 	return rndr.Render(container)
 }
 
+// IntOrStringRenderer renders an int-or-string field. Its setter accepts
+// either a number or a string.
+type IntOrStringRenderer struct {
+	baseRenderer
+}
+
+var _ renderer = (*IntOrStringRenderer)(nil)
+
+// NewIntOrStringRenderer creates an instance of IntOrStringRenderer.
+func NewIntOrStringRenderer(f Property, parent string) *IntOrStringRenderer {
+	return &IntOrStringRenderer{baseRenderer: newBaseRenderer(f, parent)}
+}
+
+// Render renders the int-or-string setter in its parent object.
+func (r *IntOrStringRenderer) Render(parent *nm.Object) error {
+	renderCheckedItem(parent, r.baseRenderer, formatCheck("intOrString", r.name))
+	return nil
+}
+
+// QuantityRenderer renders a quantity field. Its setter accepts numbers or
+// strings with a valid SI suffix (e.g. 512Mi), and converts numbers to strings.
+type QuantityRenderer struct {
+	baseRenderer
+}
+
+var _ renderer = (*QuantityRenderer)(nil)
+
+// NewQuantityRenderer creates an instance of QuantityRenderer.
+func NewQuantityRenderer(f Property, parent string) *QuantityRenderer {
+	return &QuantityRenderer{baseRenderer: newBaseRenderer(f, parent)}
+}
+
+// Render renders the quantity setter in its parent object.
+func (r *QuantityRenderer) Render(parent *nm.Object) error {
+	renderCheckedItem(parent, r.baseRenderer, formatCheck("quantity", r.name))
+	return nil
+}
+
+// TimeRenderer renders a date-time field. Its setter accepts a date-time string.
+type TimeRenderer struct {
+	baseRenderer
+}
+
+var _ renderer = (*TimeRenderer)(nil)
+
+// NewTimeRenderer creates an instance of TimeRenderer.
+func NewTimeRenderer(f Property, parent string) *TimeRenderer {
+	return &TimeRenderer{baseRenderer: newBaseRenderer(f, parent)}
+}
+
+// Render renders the date-time setter in its parent object.
+func (r *TimeRenderer) Render(parent *nm.Object) error {
+	renderCheckedItem(parent, r.baseRenderer, formatCheck("time", r.name))
+	return nil
+}
+
+// renderCheckedItem renders a setter which sets the field to the result of a format check.
+func renderCheckedItem(parent *nm.Object, r baseRenderer, check nm.Noder) {
+	noder := createObjectWithValue(r.name, mixinName(r.parent), check, false)
+	setProperty(parent, r.setter(), r.description, []string{FormatKind(r.name)}, noder)
+	r.renderDeprecated(parent, false)
+}
+
 // ReferenceRenderer renders a reference field.
 type ReferenceRenderer struct {
 	baseRenderer
@@ -79,8 +216,11 @@ type ReferenceRenderer struct {
 
 // NewReferenceRenderer creates an instance of ReferenceRenderer.
 func NewReferenceRenderer(rf *ReferenceField, tl typeLookup, parent string) *ReferenceRenderer {
+	br := newBaseRenderer(rf, parent)
+	br.describe = tl.describeRef
+
 	return &ReferenceRenderer{
-		baseRenderer: newBaseRenderer(rf, parent),
+		baseRenderer: br,
 		tl:           tl,
 		rf:           rf,
 	}
@@ -101,12 +241,16 @@ func (r *ReferenceRenderer) Render(container *nm.Object) error {
 		return errors.Wrapf(err, "fetch type %s", ref)
 	}
 
-	renderFields(r.tl, mo, name, ty.Properties())
+	if err := renderFields(r.tl, mo, name, ref, ty.Properties()); err != nil {
+		return errors.Wrapf(err, "render fields of %s", ref)
+	}
 
-	formattedName := FormatKind(r.rf.Name())
+	container.Set(nm.NewKey(r.id, nm.KeyOptComment(desc)), mo)
 
-	container.Set(nm.NewKey(formattedName, nm.KeyOptComment(desc)), mo)
-	_ = genTypeAliasEntry(container, name, ref)
+	for _, id := range r.deprecated {
+		container.Set(nm.NewKey(id, nm.KeyOptComment(deprecatedComment(r.id))), nm.NewCall("self."+r.id))
+	}
+	_ = genTypeAliasEntry(container, name, ref, r.describe)
 
 	return nil
 }
@@ -132,11 +276,158 @@ func (r *ObjectRenderer) Render(container *nm.Object) error {
 	mixinFn := createObjectWithField(r.name, wrapper, true)
 	setProperty(container, r.mixin(), r.description, []string{FormatKind(r.name)}, mixinFn)
 
-	_ = genTypeAliasEntry(container, r.name, r.ref)
+	r.renderDeprecated(container, true)
+	_ = genTypeAliasEntry(container, r.name, r.ref, r.describe)
+
+	return nil
+}
+
+// MapRenderer renders a map field. Besides the object setters, it renders a
+// setter for a single entry, which checks the entry's value, and a function
+// which removes entries by key.
+type MapRenderer struct {
+	baseRenderer
+	lf *LiteralField
+}
+
+var _ renderer = (*MapRenderer)(nil)
+
+// NewMapRenderer creates an instance of MapRenderer.
+func NewMapRenderer(lf *LiteralField, parent string) *MapRenderer {
+	return &MapRenderer{
+		baseRenderer: newBaseRenderer(lf, parent),
+		lf:           lf,
+	}
+}
+
+// Render renders the map field in the container.
+func (r *MapRenderer) Render(container *nm.Object) error {
+	value := r.lf.MapValue()
+	if value == nil {
+		return errors.Errorf("%s is not a map", r.name)
+	}
+
+	or := NewObjectRenderer(r.lf, r.parent)
+	or.describeWith(r.describe)
+	if err := or.Render(container); err != nil {
+		return err
+	}
+
+	wrapper := mixinName(r.parent)
+
+	entry := nm.OnelineObject()
+	entry.Set(nm.InheritedKey("[key]", nm.KeyOptExpr(nm.NewVar("key"))), mapValueCheck(r.name, value))
+	entryFn := createObjectWithValue(r.name, wrapper, entry, true)
+	setProperty(container, r.entrySetter(), r.description, []string{"key", "value"}, entryFn)
+
+	withoutFn := createObjectWithValue(r.name, wrapper, removeMapKeys(r.name), false)
+	setProperty(container, r.remover(), r.description, []string{"keys"}, withoutFn)
+
+	if value.Ref != "" {
+		_ = genTypeAliasEntry(container, r.name, value.Ref, r.describe)
+	}
+
+	return nil
+}
+
+func (r *MapRenderer) entrySetter() string {
+	return fmt.Sprintf("%sEntry", r.setter())
+}
+
+func (r *MapRenderer) remover() string {
+	return fmt.Sprintf("without%s", strings.Title(r.id))
+}
+
+// quantityResources are the resources which maps of quantities have setters
+// for, by the identifiers of the setters.
+var quantityResources = []struct{ id, name string }{
+	{id: "cpu", name: "cpu"},
+	{id: "memory", name: "memory"},
+	{id: "storage", name: "storage"},
+	{id: "ephemeralStorage", name: "ephemeral-storage"},
+}
+
+// isResourceRequirement returns true if a field is the limits or requests of
+// the resources of a container or a volume claim. Other maps of quantities,
+// e.g. the hard limits of a resource quota or the capacity of a node, can
+// have any resource, so they only have the map setters.
+func isResourceRequirement(lf *LiteralField, parent string) bool {
+	return parent == "resources" && (lf.Name() == "limits" || lf.Name() == "requests")
+}
+
+// QuantityMapRenderer renders the limits or requests of resource
+// requirements, which are maps of quantities. Besides the map setters, it
+// renders an object named after the field with a setter for each of the
+// common resources, which checks the quantity.
+// e.g. `resources.limits.withMemory('512Mi')`
+type QuantityMapRenderer struct {
+	MapRenderer
+}
+
+var _ renderer = (*QuantityMapRenderer)(nil)
 
+// NewQuantityMapRenderer creates an instance of QuantityMapRenderer.
+func NewQuantityMapRenderer(lf *LiteralField, parent string) *QuantityMapRenderer {
+	return &QuantityMapRenderer{MapRenderer: *NewMapRenderer(lf, parent)}
+}
+
+// Render renders the map of quantities in the container.
+func (r *QuantityMapRenderer) Render(container *nm.Object) error {
+	if err := r.MapRenderer.Render(container); err != nil {
+		return err
+	}
+
+	wrapper := mixinName(r.parent)
+
+	o := nm.NewObject()
+	for _, resource := range quantityResources {
+		entry := nm.OnelineObject()
+		entry.Set(
+			nm.InheritedKey(resource.name, nm.KeyOptCategory(ast.ObjectFieldStr)),
+			nm.ApplyCall(localFormats+".quantity", nm.NewStringDouble(resource.name), nm.NewVar(resource.id)))
+
+		desc := fmt.Sprintf("Sets the %s quantity of %s.", resource.name, r.name)
+		setterFn := createObjectWithValue(r.name, wrapper, entry, true)
+		setProperty(o, fieldName(resource.id, false), desc, []string{resource.id}, setterFn)
+	}
+
+	container.Set(nm.NewKey(r.id, nm.KeyOptComment(r.description)), o)
 	return nil
 }
 
+// mapValueCheck creates the expression which checks the value of a map entry.
+// Formatted values are checked by their format helper, and typed values by their type.
+func mapValueCheck(name string, value *MapValue) nm.Noder {
+	if helper := formatHelper(value.Format); helper != "" {
+		return nm.ApplyCall(localFormats+"."+helper, nm.NewStringDouble(name), nm.NewVar("value"))
+	}
+
+	if t := jsonnetType(value.Type); t != "" {
+		return nm.ApplyCall(localFormats+".typed",
+			nm.NewStringDouble(name), nm.NewStringDouble(t), nm.NewVar("value"))
+	}
+
+	return nm.NewVar("value")
+}
+
+// removeMapKeys creates the expression which removes keys from the inherited
+// value of a map field. e.g.
+// `if 'labels' in super then std.mergePatch(super.labels, patch) else {}`
+func removeMapKeys(name string) nm.Noder {
+	nullEntry := nm.OnelineObject()
+	nullEntry.Set(nm.InheritedKey("[key]", nm.KeyOptExpr(nm.NewVar("key"))), &nm.Null{})
+
+	patch := nm.ApplyCall("std.foldl",
+		nm.NewFunction([]string{"acc", "key"}, nm.NewBinary(nm.NewVar("acc"), nullEntry, nm.BopPlus)),
+		setArray("keys"),
+		nm.OnelineObject())
+
+	return nm.NewConditional(
+		nm.NewInSuper(nm.NewStringDouble(name)),
+		nm.ApplyCall("std.mergePatch", nm.NewCall("super."+name), patch),
+		nm.OnelineObject())
+}
+
 // ItemRenderer renders items.
 type ItemRenderer struct {
 	baseRenderer
@@ -154,7 +445,8 @@ func (r *ItemRenderer) Render(parent *nm.Object) error {
 	noder := createObjectWithField(r.name, mixinName(r.parent), false)
 	setProperty(parent, r.setter(), r.description, []string{FormatKind(r.name)}, noder)
 
-	_ = genTypeAliasEntry(parent, r.name, r.ref)
+	r.renderDeprecated(parent, false)
+	_ = genTypeAliasEntry(parent, r.name, r.ref, r.describe)
 	return nil
 }
 
@@ -177,10 +469,120 @@ func (r *ArrayRenderer) Render(container *nm.Object) error {
 	mixinFn := convertToArray(r.name, wrapper, true)
 	setProperty(container, r.mixin(), r.description, []string{FormatKind(r.name)}, mixinFn)
 
-	_ = genTypeAliasEntry(container, r.name, r.ref)
+	r.renderDeprecated(container, true)
+	_ = genTypeAliasEntry(container, r.name, r.ref, r.describe)
 	return nil
 }
 
+// ListMapRenderer renders a list of objects which are merged by a key. Besides
+// the array setters, it renders functions which update, upsert and remove
+// items by their key. e.g. `withContainerByName(name, f)`, `upsertEnv(name, value)`
+// and `removePort(containerPort)`.
+type ListMapRenderer struct {
+	baseRenderer
+	lf       *LiteralField
+	mergeKey string
+}
+
+var _ renderer = (*ListMapRenderer)(nil)
+
+// NewListMapRenderer creates an instance of ListMapRenderer.
+func NewListMapRenderer(lf *LiteralField, parent string) *ListMapRenderer {
+	return &ListMapRenderer{
+		baseRenderer: newBaseRenderer(lf, parent),
+		lf:           lf,
+		mergeKey:     lf.MergeKey(),
+	}
+}
+
+// Render renders the list map field in the container.
+func (r *ListMapRenderer) Render(container *nm.Object) error {
+	if r.mergeKey == "" {
+		return errors.Errorf("%s does not have a merge key", r.name)
+	}
+
+	ar := NewArrayRenderer(r.lf, r.parent)
+	ar.describeWith(r.describe)
+	if err := ar.Render(container); err != nil {
+		return err
+	}
+
+	wrapper := mixinName(r.parent)
+	key := FormatKind(r.mergeKey)
+	item := strings.Title(singular(r.id))
+
+	update := nm.NewFunction([]string{"item"},
+		nm.NewConditional(r.matchesKey(), nm.ApplyCall("f", nm.NewVar("item")), nm.NewVar("item")))
+	updateFn := createObjectWithValue(r.name, wrapper,
+		r.withItems(nm.ApplyCall("std.map", update, nm.NewVar("items"))), false)
+	setProperty(container, fmt.Sprintf("with%sBy%s", item, strings.Title(key)), r.description,
+		[]string{key, "f"}, updateFn)
+
+	upsertFn := createObjectWithValue(r.name, wrapper, r.withItems(r.upsert()), false)
+	setProperty(container, fmt.Sprintf("upsert%s", item), r.description, []string{key, "value"}, upsertFn)
+
+	keep := nm.NewFunction([]string{"item"}, r.differsByKey())
+	removeFn := createObjectWithValue(r.name, wrapper,
+		r.withItems(nm.ApplyCall("std.filter", keep, nm.NewVar("items"))), false)
+	setProperty(container, fmt.Sprintf("remove%s", item), r.description, []string{key}, removeFn)
+
+	return nil
+}
+
+// withItems binds the inherited items of the field to `items`. If the field
+// isn't inherited, `items` is empty.
+func (r *ListMapRenderer) withItems(body nm.Noder) nm.Noder {
+	items := nm.NewConditional(
+		nm.NewInSuper(nm.NewStringDouble(r.name)),
+		nm.NewCall("super."+r.name),
+		nm.NewArray(nil))
+
+	return nm.NewLocal("items", items, body)
+}
+
+// upsert merges value into the item with the key, or appends it to the items
+// if no item has the key.
+func (r *ListMapRenderer) upsert() nm.Noder {
+	key := FormatKind(r.mergeKey)
+
+	merge := nm.NewFunction([]string{"item"},
+		nm.NewConditional(r.matchesKey(),
+			nm.NewBinary(nm.NewVar("item"), nm.NewVar("value"), nm.BopPlus),
+			nm.NewVar("item")))
+
+	keyed := nm.OnelineObject()
+	keyed.Set(nm.InheritedKey(r.mergeKey), nm.NewVar(key))
+
+	matches := nm.ApplyCall("std.length",
+		nm.ApplyCall("std.filter", nm.NewFunction([]string{"item"}, r.matchesKey()), nm.NewVar("items")))
+
+	return nm.NewConditional(
+		nm.NewBinary(matches, nm.NewInt(0), nm.BopGreater),
+		nm.ApplyCall("std.map", merge, nm.NewVar("items")),
+		nm.NewBinary(nm.NewVar("items"),
+			nm.NewArray([]nm.Noder{nm.NewBinary(nm.NewVar("value"), keyed, nm.BopPlus)}),
+			nm.BopPlus))
+}
+
+// matchesKey creates the expression which is true if `item` has the key.
+func (r *ListMapRenderer) matchesKey() nm.Noder {
+	return nm.NewBinary(
+		nm.ApplyCall("std.objectHas", nm.NewVar("item"), nm.NewStringDouble(r.mergeKey)),
+		nm.NewBinary(nm.NewCall("item."+r.mergeKey), nm.NewVar(FormatKind(r.mergeKey)), nm.BopEqual),
+		nm.BopAnd)
+}
+
+// differsByKey creates the expression which is true if `item` does not have the key.
+func (r *ListMapRenderer) differsByKey() nm.Noder {
+	return nm.NewBinary(
+		nm.NewBinary(
+			nm.ApplyCall("std.objectHas", nm.NewVar("item"), nm.NewStringDouble(r.mergeKey)),
+			nm.NewBoolean(false),
+			nm.BopEqual),
+		nm.NewBinary(nm.NewCall("item."+r.mergeKey), nm.NewVar(FormatKind(r.mergeKey)), nm.BopNotEqual),
+		nm.BopOr)
+}
+
 func convertToArray(varName, parent string, mixin bool) nm.Noder {
 	apply := nm.NewApply(
 		nm.NewCall("std.type"),
@@ -216,9 +618,15 @@ func convertToArray(varName, parent string, mixin bool) nm.Noder {
 // createObjectWithField creates an object with a field. Creates {field: field} or {field+: field}
 // if mixin. If it has a parent, it create __parentNameMixin({field: field}).
 func createObjectWithField(name, parentName string, mixin bool) nm.Noder {
+	return createObjectWithValue(name, parentName, nm.NewVar(FormatKind(name)), mixin)
+}
+
+// createObjectWithValue creates an object with a field set to value. Creates {field: value} or
+// {field+: value} if mixin. If it has a parent, it create __parentNameMixin({field: value}).
+func createObjectWithValue(name, parentName string, value nm.Noder, mixin bool) nm.Noder {
 	var noder nm.Noder
 	io := nm.OnelineObject()
-	io.Set(nm.InheritedKey(name, nm.KeyOptMixin(mixin)), nm.NewVar(FormatKind(name)))
+	io.Set(nm.InheritedKey(name, nm.KeyOptMixin(mixin)), value)
 
 	if parentName == "" {
 		noder = io
@@ -235,6 +643,22 @@ func setProperty(o *nm.Object, fnName, desc string, args []string, node nm.Noder
 	o.Set(key, node)
 }
 
+// setDeprecatedAlias sets a function which calls the function it replaces.
+func setDeprecatedAlias(o *nm.Object, fnName, target string, args []string) {
+	var params []nm.Noder
+	for _, arg := range args {
+		params = append(params, nm.NewVar(arg))
+	}
+
+	key := nm.FunctionKey(fnName, args, nm.KeyOptComment(deprecatedComment(target)))
+	o.Set(key, nm.NewApply(nm.NewCall("self."+target), params, nil))
+}
+
+// deprecatedComment creates the comment of a deprecated alias.
+func deprecatedComment(replacement string) string {
+	return fmt.Sprintf("Deprecated: use %s instead.", replacement)
+}
+
 func mixinPreamble(o *nm.Object, parent, name string) error {
 	if o == nil {
 		return errors.New("parent object is nil")
@@ -262,12 +686,12 @@ func mixinPreamble(o *nm.Object, parent, name string) error {
 	return nil
 }
 
-func genTypeAliasEntry(container *nm.Object, name, refName string) error {
+func genTypeAliasEntry(container *nm.Object, name, refName string, describe describeFn) error {
 	if refName == "" {
 		return errors.New("ref name is blank")
 	}
 
-	rd, err := ParseDescription(refName)
+	rd, err := describe(refName)
 	if err != nil {
 		return errors.Wrapf(err, "parse ref name from %q and %q", name, refName)
 	}
@@ -285,15 +709,18 @@ func genTypeAliasEntry(container *nm.Object, name, refName string) error {
 	path := []string{"hidden", rd.Group, rd.Version, kind}
 	location := strings.Join(path, ".")
 
-	typeAliasName := fmt.Sprintf("%sType", name)
-
 	c := nm.NewCall(location)
 
-	container.Set(nm.NewKey(typeAliasName), c)
+	container.Set(nm.NewKey(typeAliasName(name)), c)
 
 	return nil
 }
 
+// typeAliasName returns the name of the type alias of a field.
+func typeAliasName(name string) string {
+	return fmt.Sprintf("%sType", name)
+}
+
 // Generates a field name.
 func fieldName(name string, isMixin bool) string {
 	var out string
@@ -321,17 +748,48 @@ func mixinName(name string) string {
 // typeLookup can look up types by id.
 type typeLookup interface {
 	Field(id string) (*Field, error)
+	describeRef(name string) (*Description, error)
+	recordCollision(c Collision)
 }
 
-type renderFieldsFn func(tl typeLookup, parent *nm.Object, parentName string, props map[string]Property) error
+type renderFieldsFn func(tl typeLookup, parent *nm.Object, parentName, definition string, props map[string]Property) error
+
+// renderFields renders fields from a property map. The fields are the
+// properties of definition. Keys which collide are resolved with
+// resolveCollisions before the fields are added to parent.
+func renderFields(tl typeLookup, parent *nm.Object, parentName, definition string, props map[string]Property) error {
+	container, fields, err := renderFieldKeys(tl, parent, parentName, definition, props)
+	if err != nil {
+		return err
+	}
+
+	for _, rf := range fields {
+		rf.merge()
+	}
+
+	if parentName == "" {
+		parent.Set(nm.NewKey("mixin"), container)
+	}
+
+	return nil
+}
 
-// renderFields renders fields from a property map.
-func renderFields(tl typeLookup, parent *nm.Object, parentName string, props map[string]Property) error {
+// renderFieldKeys renders the properties of definition into objects of their
+// own, sorted by name, and resolves the keys which collide with each other or
+// with the keys of parent. The fields of references are merged into the
+// returned container, which is a new object for the root of a type
+// (parentName is blank), and parent otherwise.
+func renderFieldKeys(tl typeLookup, parent *nm.Object, parentName, definition string, props map[string]Property) (*nm.Object, []*renderedField, error) {
 	container := parent
 	if parentName == "" {
 		container = nm.NewObject()
 	}
 
+	reserved := objectKeys(parent, container)
+	if parentName == "" {
+		reserved[parent]["mixin"] = true
+	}
+
 	var names []string
 	for name := range props {
 		names = append(names, name)
@@ -339,28 +797,48 @@ func renderFields(tl typeLookup, parent *nm.Object, parentName string, props map
 
 	sort.Strings(names)
 
-	for _, name := range names {
-		field := props[name]
+	render := func(rf *renderedField) error {
+		rf.keys = nm.NewObject()
+		rf.skip = make(map[string]bool)
 
-		switch t := field.(type) {
+		switch t := rf.prop.(type) {
 		case *LiteralField:
 			r := NewLiteralFieldRenderer(t, parentName)
-			if err := r.Render(parent); err != nil {
+			r.describe = tl.describeRef
+			if err := r.Render(rf.keys); err != nil {
 				return errors.Wrap(err, "render literal field")
 			}
 		case *ReferenceField:
 			r := NewReferenceRenderer(t, tl, parentName)
-			if err := r.Render(container); err != nil {
+			if err := r.Render(rf.keys); err != nil {
 				return errors.Wrap(err, "render reference field")
 			}
 		default:
 			return errors.Errorf("unknown field type %T", t)
 		}
+
+		return nil
+	}
+
+	var fields []*renderedField
+	for _, name := range names {
+		field := props[name]
+
+		dst := parent
+		if _, ok := field.(*ReferenceField); ok {
+			dst = container
+		}
+
+		rf := newRenderedField(field, dst)
+		if err := render(rf); err != nil {
+			return nil, nil, err
+		}
+		fields = append(fields, rf)
 	}
 
-	if parentName == "" {
-		parent.Set(nm.NewKey("mixin"), container)
+	if err := resolveCollisions(tl, definition, fields, reserved, render); err != nil {
+		return nil, nil, err
 	}
 
-	return nil
+	return container, fields, nil
 }
@@ -1,9 +1,12 @@
 package ksonnet
 
 import (
+	"bytes"
+	"fmt"
 	"io/ioutil"
 	"testing"
 
+	jsonnet "github.com/google/go-jsonnet"
 	nm "github.com/ksonnet/ksonnet-lib/ksonnet-gen/nodemaker"
 	"github.com/ksonnet/ksonnet-lib/ksonnet-gen/printer"
 	"github.com/stretchr/testify/assert"
@@ -60,6 +63,304 @@ func TestLiteralFieldRenderer(t *testing.T) {
 	}
 }
 
+func TestLiteralFieldRenderer_format(t *testing.T) {
+	cases := []struct {
+		name     string
+		format   string
+		expected nm.Noder
+	}{
+		{
+			name:     "int-or-string",
+			format:   formatIntOrString,
+			expected: formatCheck("intOrString", "name"),
+		},
+		{
+			name:     "quantity",
+			format:   formatQuantity,
+			expected: formatCheck("quantity", "name"),
+		},
+		{
+			name:     "date-time",
+			format:   formatDateTime,
+			expected: formatCheck("time", "name"),
+		},
+		{
+			name:     "unknown format",
+			format:   "int32",
+			expected: nm.NewVar("name"),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := NewLiteralField("name", "string", "desc", "", LiteralFieldOptFormat(tc.format))
+			r := NewLiteralFieldRenderer(f, "parent")
+
+			o := nm.NewObject()
+			require.NoError(t, r.Render(o))
+
+			expected := nm.NewObject()
+			setProperty(expected, "withName", "desc", []string{"name"},
+				createObjectWithValue("name", mixinName("parent"), tc.expected, false))
+
+			require.Equal(t, expected, o)
+		})
+	}
+}
+
+func TestMapRenderer(t *testing.T) {
+	cases := []struct {
+		name     string
+		value    *MapValue
+		snippet  string
+		expected string
+		isErr    bool
+	}{
+		{
+			name:     "set an entry",
+			value:    &MapValue{Type: "string"},
+			snippet:  `fns.withLabelsEntry("a", "1")`,
+			expected: `{"metadata": {"labels": {"a": "1"}}}`,
+		},
+		{
+			name:     "set entries",
+			value:    &MapValue{Type: "string"},
+			snippet:  `fns.withLabels({a: "1"}) + fns.withLabelsEntry("b", "2")`,
+			expected: `{"metadata": {"labels": {"a": "1", "b": "2"}}}`,
+		},
+		{
+			name:    "set an entry with an invalid type",
+			value:   &MapValue{Type: "string"},
+			snippet: `fns.withLabelsEntry("a", 1)`,
+			isErr:   true,
+		},
+		{
+			name:     "set an entry with a format",
+			value:    &MapValue{Type: "string", Format: formatQuantity},
+			snippet:  `fns.withLabelsEntry("memory", 2)`,
+			expected: `{"metadata": {"labels": {"memory": "2"}}}`,
+		},
+		{
+			name:     "set an entry of any type",
+			value:    &MapValue{},
+			snippet:  `fns.withLabelsEntry("a", [1])`,
+			expected: `{"metadata": {"labels": {"a": [1]}}}`,
+		},
+		{
+			name:     "remove a key",
+			value:    &MapValue{Type: "string"},
+			snippet:  `fns.withLabels({a: "1", b: "2"}) + fns.withoutLabels("a")`,
+			expected: `{"metadata": {"labels": {"b": "2"}}}`,
+		},
+		{
+			name:     "remove keys",
+			value:    &MapValue{Type: "string"},
+			snippet:  `fns.withLabels({a: "1", b: "2", c: "3"}) + fns.withoutLabels(["a", "c", "d"])`,
+			expected: `{"metadata": {"labels": {"b": "2"}}}`,
+		},
+		{
+			name:     "remove a key from an unset map",
+			value:    &MapValue{Type: "string"},
+			snippet:  `fns.withoutLabels("a")`,
+			expected: `{"metadata": {"labels": {}}}`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := NewLiteralField("labels", "object", "desc", "", LiteralFieldOptMapValue(tc.value))
+
+			o := nm.NewObject()
+			o.Set(nm.LocalKey("__metadataMixin", nm.KeyOptParams([]string{"metadata"})),
+				createObjectWithValue("metadata", "", nm.NewVar("metadata"), true))
+			require.NoError(t, NewLiteralFieldRenderer(f, "metadata").Render(o))
+
+			for _, name := range []string{"withLabels", "withLabelsMixin", "withLabelsEntry", "withoutLabels"} {
+				require.NotNil(t, o.Get(name), "%s was not rendered", name)
+			}
+
+			var helpers, fns bytes.Buffer
+			require.NoError(t, printer.Fprint(&helpers, formatHelpers().Node()))
+			require.NoError(t, printer.Fprint(&fns, o.Node()))
+
+			snippet := fmt.Sprintf("local formats = %s;\nlocal fns = %s;\n%s",
+				helpers.String(), fns.String(), tc.snippet)
+
+			vm := jsonnet.MakeVM()
+			got, err := vm.EvaluateSnippet("map.jsonnet", snippet)
+			if tc.isErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+
+			expected, err := vm.EvaluateSnippet("expected.jsonnet", tc.expected)
+			require.NoError(t, err)
+			require.Equal(t, expected, got)
+		})
+	}
+}
+
+func TestQuantityMapRenderer(t *testing.T) {
+	cases := []struct {
+		name     string
+		snippet  string
+		expected string
+		isErr    bool
+	}{
+		{
+			name:     "set a resource",
+			snippet:  `fns.limits.withMemory("512Mi")`,
+			expected: `{"resources": {"limits": {"memory": "512Mi"}}}`,
+		},
+		{
+			name:     "set resources",
+			snippet:  `fns.limits.withCpu(0.5).withEphemeralStorage("1Gi") + fns.withLimitsEntry("nvidia.com/gpu", 1)`,
+			expected: `{"resources": {"limits": {"cpu": "0.5", "ephemeral-storage": "1Gi", "nvidia.com/gpu": "1"}}}`,
+		},
+		{
+			name:    "set a resource with an invalid quantity",
+			snippet: `fns.limits.withMemory("512MB")`,
+			isErr:   true,
+		},
+	}
+
+	value := &MapValue{Type: "string", Format: formatQuantity}
+	f := NewLiteralField("limits", "object", "desc", "", LiteralFieldOptMapValue(value))
+
+	o := nm.NewObject()
+	o.Set(nm.LocalKey("__resourcesMixin", nm.KeyOptParams([]string{"resources"})),
+		createObjectWithValue("resources", "", nm.NewVar("resources"), true))
+	require.NoError(t, NewLiteralFieldRenderer(f, "resources").Render(o))
+
+	for _, name := range []string{"withLimits", "withLimitsEntry", "withoutLimits", "limits"} {
+		require.NotNil(t, o.Get(name), "%s was not rendered", name)
+	}
+
+	var helpers, fns bytes.Buffer
+	require.NoError(t, printer.Fprint(&helpers, formatHelpers().Node()))
+	require.NoError(t, printer.Fprint(&fns, o.Node()))
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			snippet := fmt.Sprintf("local formats = %s;\nlocal fns = %s;\n%s",
+				helpers.String(), fns.String(), tc.snippet)
+
+			vm := jsonnet.MakeVM()
+			got, err := vm.EvaluateSnippet("quantities.jsonnet", snippet)
+			if tc.isErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+
+			expected, err := vm.EvaluateSnippet("expected.jsonnet", tc.expected)
+			require.NoError(t, err)
+			require.Equal(t, expected, got)
+		})
+	}
+}
+
+func TestLiteralFieldRenderer_other_quantity_maps(t *testing.T) {
+	value := &MapValue{Type: "string", Format: formatQuantity}
+	f := NewLiteralField("hard", "object", "desc", "", LiteralFieldOptMapValue(value))
+
+	o := nm.NewObject()
+	require.NoError(t, NewLiteralFieldRenderer(f, "spec").Render(o))
+
+	require.NotNil(t, o.Get("withHardEntry"))
+	require.Nil(t, o.Get("hard"), "resource setters were rendered for a quota")
+}
+
+func TestMapRenderer_type_alias(t *testing.T) {
+	value := &MapValue{Type: "object", Ref: "io.k8s.api.core.v1.Container"}
+	f := NewLiteralField("containers", "object", "desc", "", LiteralFieldOptMapValue(value))
+
+	o := nm.NewObject()
+	require.NoError(t, NewMapRenderer(f, "").Render(o))
+
+	require.Equal(t, nm.NewCall("hidden.core.v1.container"), o.Get("containersType"))
+}
+
+func TestListMapRenderer(t *testing.T) {
+	ports := `fns.withPorts([{containerPort: 80, name: "http"}, {containerPort: 443}])`
+
+	cases := []struct {
+		name     string
+		snippet  string
+		expected string
+	}{
+		{
+			name:     "update an item",
+			snippet:  ports + `.withPortByContainerPort(80, function(port) port + {name: "web"})`,
+			expected: `{"spec": {"ports": [{"containerPort": 80, "name": "web"}, {"containerPort": 443}]}}`,
+		},
+		{
+			name:     "update a missing item",
+			snippet:  ports + `.withPortByContainerPort(8080, function(port) port + {name: "web"})`,
+			expected: `{"spec": {"ports": [{"containerPort": 80, "name": "http"}, {"containerPort": 443}]}}`,
+		},
+		{
+			name:     "upsert an existing item",
+			snippet:  ports + `.upsertPort(443, {name: "https"})`,
+			expected: `{"spec": {"ports": [{"containerPort": 80, "name": "http"}, {"containerPort": 443, "name": "https"}]}}`,
+		},
+		{
+			name:     "upsert a new item",
+			snippet:  ports + `.upsertPort(8080, {name: "alt"})`,
+			expected: `{"spec": {"ports": [{"containerPort": 80, "name": "http"}, {"containerPort": 443}, {"containerPort": 8080, "name": "alt"}]}}`,
+		},
+		{
+			name:     "upsert into an unset list",
+			snippet:  `fns.upsertPort(80, {})`,
+			expected: `{"spec": {"ports": [{"containerPort": 80}]}}`,
+		},
+		{
+			name:     "remove an item",
+			snippet:  ports + `.removePort(80)`,
+			expected: `{"spec": {"ports": [{"containerPort": 443}]}}`,
+		},
+		{
+			name:     "remove from an unset list",
+			snippet:  `fns.removePort(80)`,
+			expected: `{"spec": {"ports": []}}`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			f := NewLiteralField("ports", "array", "desc", "", LiteralFieldOptPatch("containerPort", "merge"))
+
+			o := nm.NewObject()
+			o.Set(nm.LocalKey("__specMixin", nm.KeyOptParams([]string{"spec"})),
+				createObjectWithValue("spec", "", nm.NewVar("spec"), true))
+			require.NoError(t, NewLiteralFieldRenderer(f, "spec").Render(o))
+
+			for _, name := range []string{"withPorts", "withPortsMixin", "withPortByContainerPort", "upsertPort", "removePort"} {
+				require.NotNil(t, o.Get(name), "%s was not rendered", name)
+			}
+
+			var fns bytes.Buffer
+			require.NoError(t, printer.Fprint(&fns, o.Node()))
+
+			vm := jsonnet.MakeVM()
+			got, err := vm.EvaluateSnippet("list.jsonnet", fmt.Sprintf("local fns = %s;\n%s", fns.String(), tc.snippet))
+			require.NoError(t, err)
+
+			expected, err := vm.EvaluateSnippet("expected.jsonnet", tc.expected)
+			require.NoError(t, err)
+			require.Equal(t, expected, got)
+		})
+	}
+}
+
+func TestListMapRenderer_no_merge_key(t *testing.T) {
+	f := NewLiteralField("ports", "array", "desc", "")
+	require.Error(t, NewListMapRenderer(f, "").Render(nm.NewObject()))
+}
+
 func TestReferenceRenderer(t *testing.T) {
 	cases := []struct {
 		name  string
@@ -249,7 +550,7 @@ func Test_genTypeAlias(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 
 			o := nm.NewObject()
-			err := genTypeAliasEntry(o, tc.propName, tc.ref)
+			err := genTypeAliasEntry(o, tc.propName, tc.ref, ParseDescription)
 
 			if tc.isErr {
 				require.Error(t, err)
@@ -377,7 +678,7 @@ func Test_renderFields(t *testing.T) {
 		"aref": NewReferenceField("aref", "desc", "io.k8s.apimachinery.pkg.apis.meta.v1.LabelSelector"),
 	}
 
-	err := renderFields(c, o, "", props)
+	err := renderFields(c, o, "", "io.k8s.api.example.v1.Example", props)
 	require.NoError(t, err)
 
 	err = printer.Fprint(ioutil.Discard, o.Node())
@@ -402,7 +703,7 @@ func Test_renderFields_unknown_type(t *testing.T) {
 		"name": &customField{},
 	}
 
-	err := renderFields(c, o, "", props)
+	err := renderFields(c, o, "", "io.k8s.api.example.v1.Example", props)
 	require.Error(t, err)
 }
 
@@ -413,7 +714,7 @@ func Test_renderFields_literal_field_error(t *testing.T) {
 		"name": NewLiteralField("name", "unknown", "desc", ""),
 	}
 
-	err := renderFields(c, o, "", props)
+	err := renderFields(c, o, "", "io.k8s.api.example.v1.Example", props)
 	require.Error(t, err)
 }
 
@@ -424,6 +725,6 @@ func Test_renderFields_reference_field_error(t *testing.T) {
 		"aref": NewReferenceField("aref", "desc", "unknown-id"),
 	}
 
-	err := renderFields(c, o, "", props)
+	err := renderFields(c, o, "", "io.k8s.api.example.v1.Example", props)
 	require.Error(t, err)
 }
@@ -0,0 +1,197 @@
+package ksonnet
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+const jsonSchemaDraft = "http://json-schema.org/draft-07/schema#"
+
+// GenerateJSONSchemas generates a JSON Schema for each type and field of a
+// Catalog. The schemas are keyed by their file name, which is the
+// definition's identifier with a .json extension, and refer to each other
+// with relative $refs. (e.g. io.k8s.api.apps.v1.DeploymentSpec.json)
+func GenerateJSONSchemas(c *Catalog) (map[string][]byte, error) {
+	objects, err := catalogObjects(c)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(map[string][]byte)
+	for _, o := range objects {
+		b, err := json.MarshalIndent(objectSchema(c, o), "", "  ")
+		if err != nil {
+			return nil, errors.Wrapf(err, "marshal schema of %s", o.Identifier())
+		}
+
+		out[schemaFile(o.Identifier())] = append(b, '\n')
+	}
+
+	return out, nil
+}
+
+// catalogObjects returns the types and fields of a Catalog, sorted by their
+// identifier. A definition which is both a type and a field (e.g. a list) is
+// returned once, as a type.
+func catalogObjects(c *Catalog) ([]Object, error) {
+	types, err := c.Types()
+	if err != nil {
+		return nil, errors.Wrap(err, "retrieve types")
+	}
+
+	fields, err := c.Fields()
+	if err != nil {
+		return nil, errors.Wrap(err, "retrieve fields")
+	}
+
+	seen := make(map[string]bool)
+	var objects []Object
+	for i := range types {
+		seen[types[i].Identifier()] = true
+		objects = append(objects, &types[i])
+	}
+
+	for i := range fields {
+		if !seen[fields[i].Identifier()] {
+			objects = append(objects, &fields[i])
+		}
+	}
+
+	sort.Slice(objects, func(i, j int) bool {
+		return objects[i].Identifier() < objects[j].Identifier()
+	})
+
+	return objects, nil
+}
+
+func schemaFile(definition string) string {
+	return definition + ".json"
+}
+
+// objectSchema creates the schema of an object. The schema of a Kubernetes
+// type requires its apiVersion and kind.
+func objectSchema(c *Catalog, o Object) map[string]interface{} {
+	properties := make(map[string]interface{})
+	for name, prop := range o.Properties() {
+		properties[name] = propertySchema(prop)
+	}
+
+	schema := map[string]interface{}{
+		"$schema":    jsonSchemaDraft,
+		"$id":        schemaFile(o.Identifier()),
+		"title":      o.Kind(),
+		"type":       "object",
+		"properties": properties,
+	}
+
+	if o.Description() != "" {
+		schema["description"] = o.Description()
+	}
+
+	if o.IsType() && !c.generic {
+		apiVersion := NewVersion(o.Version(), o.QualifiedGroup()).APIVersion()
+		properties["apiVersion"] = map[string]interface{}{"type": "string", "enum": []string{apiVersion}}
+		properties["kind"] = map[string]interface{}{"type": "string", "enum": []string{o.Kind()}}
+		schema["required"] = []string{"apiVersion", "kind"}
+	}
+
+	return schema
+}
+
+// propertySchema creates the schema of a property.
+func propertySchema(p Property) map[string]interface{} {
+	var schema map[string]interface{}
+
+	switch t := p.(type) {
+	case *ReferenceField:
+		schema = refSchema(t.Ref())
+	case *LiteralField:
+		schema = literalSchema(t)
+	default:
+		schema = map[string]interface{}{}
+	}
+
+	if p.Description() != "" {
+		schema["description"] = p.Description()
+	}
+
+	return schema
+}
+
+func literalSchema(f *LiteralField) map[string]interface{} {
+	switch f.Format() {
+	case formatIntOrString:
+		return oneOfSchema("integer", "string")
+	case formatQuantity:
+		return oneOfSchema("number", "string")
+	}
+
+	switch f.FieldType() {
+	case "array":
+		items := map[string]interface{}{}
+		switch {
+		case f.Ref() != "":
+			items = refSchema(f.Ref())
+		case f.ItemType() != "":
+			items = map[string]interface{}{"type": f.ItemType()}
+		}
+
+		return map[string]interface{}{"type": "array", "items": items}
+	case "object":
+		// recursive references are object fields with a ref.
+		if f.Ref() != "" {
+			return refSchema(f.Ref())
+		}
+
+		schema := map[string]interface{}{"type": "object"}
+		if mv := f.MapValue(); mv != nil {
+			schema["additionalProperties"] = mapValueSchema(mv)
+		}
+
+		return schema
+	case "":
+		return map[string]interface{}{}
+	}
+
+	schema := map[string]interface{}{"type": f.FieldType()}
+	if f.Format() != "" {
+		schema["format"] = f.Format()
+	}
+
+	return schema
+}
+
+func mapValueSchema(mv *MapValue) map[string]interface{} {
+	switch {
+	case mv.Ref != "":
+		return refSchema(mv.Ref)
+	case mv.Format == formatIntOrString:
+		return oneOfSchema("integer", "string")
+	case mv.Format == formatQuantity:
+		return oneOfSchema("number", "string")
+	case mv.Type == "":
+		return map[string]interface{}{}
+	}
+
+	schema := map[string]interface{}{"type": mv.Type}
+	if mv.Format != "" {
+		schema["format"] = mv.Format
+	}
+
+	return schema
+}
+
+func refSchema(ref string) map[string]interface{} {
+	return map[string]interface{}{"$ref": schemaFile(ref)}
+}
+
+func oneOfSchema(types ...string) map[string]interface{} {
+	var schemas []interface{}
+	for _, t := range types {
+		schemas = append(schemas, map[string]interface{}{"type": t})
+	}
+
+	return map[string]interface{}{"oneOf": schemas}
+}
@@ -0,0 +1,76 @@
+package ksonnet
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateJSONSchemas(t *testing.T) {
+	c := initCatalog(t, "swagger-1.8.json")
+
+	schemas, err := GenerateJSONSchemas(c)
+	require.NoError(t, err)
+
+	var deployment map[string]interface{}
+	require.NoError(t, json.Unmarshal(schemas["io.k8s.api.apps.v1beta2.Deployment.json"], &deployment))
+
+	assert.Equal(t, "Deployment", deployment["title"])
+	assert.Equal(t, []interface{}{"apiVersion", "kind"}, deployment["required"])
+
+	props := deployment["properties"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"type": "string", "enum": []interface{}{"apps/v1beta2"}}, props["apiVersion"])
+	assert.Equal(t, "io.k8s.api.apps.v1beta2.DeploymentSpec.json", props["spec"].(map[string]interface{})["$ref"])
+
+	var container map[string]interface{}
+	require.NoError(t, json.Unmarshal(schemas["io.k8s.api.core.v1.Container.json"], &container))
+	assert.NotContains(t, container, "required")
+
+	props = container["properties"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"$ref": "io.k8s.api.core.v1.ContainerPort.json"},
+		props["ports"].(map[string]interface{})["items"])
+	assert.Equal(t, map[string]interface{}{"type": "string"},
+		props["args"].(map[string]interface{})["items"])
+
+	var rollingUpdate map[string]interface{}
+	require.NoError(t, json.Unmarshal(schemas["io.k8s.api.apps.v1beta2.RollingUpdateDeployment.json"], &rollingUpdate))
+
+	props = rollingUpdate["properties"].(map[string]interface{})
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"type": "integer"},
+		map[string]interface{}{"type": "string"},
+	}, props["maxSurge"].(map[string]interface{})["oneOf"])
+}
+
+func TestGenerateJSONSchemas_generic(t *testing.T) {
+	c := initCatalog(t, "generic.json", CatalogOptGeneric(genericRoots...))
+
+	schemas, err := GenerateJSONSchemas(c)
+	require.NoError(t, err)
+
+	var names []string
+	for name := range schemas {
+		names = append(names, name)
+	}
+	assert.ElementsMatch(t, []string{"Category.json", "Pet.json", "Tag.json",
+		"compute.v1.AttachedDisk.json", "compute.v1.Instance.json"}, names)
+
+	expected := `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "$id": "Pet.json",
+  "title": "Pet",
+  "description": "A pet for sale in the pet store.",
+  "type": "object",
+  "properties": {
+    "category": {"$ref": "Category.json", "description": "Category of the pet."},
+    "kind": {"type": "string", "description": "Kind of animal."},
+    "labels": {"type": "object", "additionalProperties": {"type": "string"}, "description": "Labels of the pet."},
+    "name": {"type": "string", "description": "Name of the pet."},
+    "status": {"type": "string", "description": "Status of the pet in the store."},
+    "tags": {"type": "array", "items": {"$ref": "Tag.json"}, "description": "Tags of the pet."}
+  }
+}`
+	assert.JSONEq(t, expected, string(schemas["Pet.json"]))
+}
@@ -101,3 +101,48 @@ func FormatKind(s string) string {
 
 	return s
 }
+
+// irregularPlurals are the plurals whose singular the suffix rules of
+// singular don't find, by their singular.
+var irregularPlurals = map[string]string{
+	"aliases":  "alias",
+	"biases":   "bias",
+	"buses":    "bus",
+	"caches":   "cache",
+	"cookies":  "cookie",
+	"series":   "series",
+	"statuses": "status",
+}
+
+// singular converts the plural name of a list to the name of one of its items.
+// e.g. `containers` into `container` and `hostAliases` into `hostAlias`.
+// The last word of a camel cased name is converted.
+func singular(name string) string {
+	for plural, one := range irregularPlurals {
+		start := len(name) - len(plural)
+		if start < 0 || strings.ToLower(name[start:]) != plural {
+			continue
+		}
+
+		// the plural is a word of the name, not the end of a longer word.
+		if start == 0 {
+			return one
+		}
+		if isUpper(name[start]) {
+			return name[:start] + strings.Title(one)
+		}
+	}
+
+	switch {
+	case strings.HasSuffix(name, "ies"):
+		return strings.TrimSuffix(name, "ies") + "y"
+	case strings.HasSuffix(name, "sses"), strings.HasSuffix(name, "xes"),
+		strings.HasSuffix(name, "ches"), strings.HasSuffix(name, "shes"):
+		return strings.TrimSuffix(name, "es")
+	case strings.HasSuffix(name, "ss"):
+		return name
+	default:
+		// e.g. `responses` and `licenses`, whose singulars end with an e.
+		return strings.TrimSuffix(name, "s")
+	}
+}
@@ -165,3 +165,32 @@ func Test_FormatKind(t *testing.T) {
 		})
 	}
 }
+
+func Test_singular(t *testing.T) {
+	cases := []struct {
+		in  string
+		out string
+	}{
+		{in: "containers", out: "container"},
+		{in: "env", out: "env"},
+		{in: "hostAliases", out: "hostAlias"},
+		{in: "addresses", out: "address"},
+		{in: "policies", out: "policy"},
+		{in: "class", out: "class"},
+		{in: "responses", out: "response"},
+		{in: "licenses", out: "license"},
+		{in: "statuses", out: "status"},
+		{in: "containerStatuses", out: "containerStatus"},
+		{in: "aliases", out: "alias"},
+		{in: "prefixes", out: "prefix"},
+		{in: "matches", out: "match"},
+		{in: "caches", out: "cache"},
+		{in: "meshes", out: "mesh"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.in, func(t *testing.T) {
+			require.Equal(t, tc.out, singular(tc.in))
+		})
+	}
+}
@@ -0,0 +1,258 @@
+package ksonnet
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var reTSIdentifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// GenerateTypeScript generates TypeScript declarations of the types and
+// fields of a Catalog. Each object is an interface in a namespace for its
+// group and version, like the lib. (e.g. apps.v1.Deployment) Objects without
+// a version are in the namespace of their group. (e.g. core.Info)
+func GenerateTypeScript(c *Catalog) ([]byte, error) {
+	objects, err := catalogObjects(c)
+	if err != nil {
+		return nil, err
+	}
+
+	names := tsNames(objects)
+
+	namespaces := make(map[string]map[string][]Object)
+	for _, o := range objects {
+		group, version := o.Group(), o.Version()
+		if namespaces[group] == nil {
+			namespaces[group] = make(map[string][]Object)
+		}
+		namespaces[group][version] = append(namespaces[group][version], o)
+	}
+
+	var groups []string
+	for group := range namespaces {
+		groups = append(groups, group)
+	}
+	sort.Strings(groups)
+
+	var buf bytes.Buffer
+	for _, group := range groups {
+		fmt.Fprintf(&buf, "export namespace %s {\n", group)
+
+		versions := namespaces[group]
+		var versionNames []string
+		for version := range versions {
+			versionNames = append(versionNames, version)
+		}
+		sort.Strings(versionNames)
+
+		for _, version := range versionNames {
+			indent := "  "
+			if version != "" {
+				fmt.Fprintf(&buf, "  export namespace %s {\n", version)
+				indent = "    "
+			}
+
+			for _, o := range versions[version] {
+				if err := writeTSInterface(&buf, indent, c, o, names); err != nil {
+					return nil, err
+				}
+			}
+
+			if version != "" {
+				buf.WriteString("  }\n")
+			}
+		}
+
+		buf.WriteString("}\n")
+	}
+
+	return buf.Bytes(), nil
+}
+
+// tsNames returns the qualified interface names of objects by their
+// identifiers. Objects which would have the same name get a number.
+// (e.g. extensions.v1beta1.Deployment2)
+func tsNames(objects []Object) map[string]string {
+	names := make(map[string]string)
+	taken := make(map[string]bool)
+
+	for _, o := range objects {
+		path := []string{o.Group(), o.Version(), tsKind(o.Kind())}
+		if o.Version() == "" {
+			path = []string{o.Group(), tsKind(o.Kind())}
+		}
+		base := strings.Join(path, ".")
+
+		name := base
+		for i := 2; taken[name]; i++ {
+			name = base + strconv.Itoa(i)
+		}
+
+		taken[name] = true
+		names[o.Identifier()] = name
+	}
+
+	return names
+}
+
+// tsKind formats a kind as an interface name.
+func tsKind(kind string) string {
+	kind = strings.Map(func(r rune) rune {
+		if r == '_' || r == '$' || ('0' <= r && r <= '9') || ('A' <= r && r <= 'Z') || ('a' <= r && r <= 'z') {
+			return r
+		}
+		return -1
+	}, kind)
+
+	if kind == "" || !reTSIdentifier.MatchString(kind) {
+		kind = "T" + kind
+	}
+
+	return strings.ToUpper(kind[:1]) + kind[1:]
+}
+
+func writeTSInterface(buf *bytes.Buffer, indent string, c *Catalog, o Object, names map[string]string) error {
+	name := names[o.Identifier()]
+	local := name[strings.LastIndex(name, ".")+1:]
+	fieldIndent := indent + "  "
+
+	writeTSComment(buf, indent, o.Description())
+	fmt.Fprintf(buf, "%sexport interface %s {\n", indent, local)
+
+	if o.IsType() && !c.generic {
+		apiVersion := NewVersion(o.Version(), o.QualifiedGroup()).APIVersion()
+		fmt.Fprintf(buf, "%sapiVersion: %s;\n", fieldIndent, strconv.Quote(apiVersion))
+		fmt.Fprintf(buf, "%skind: %s;\n", fieldIndent, strconv.Quote(o.Kind()))
+	}
+
+	props := o.Properties()
+	var propNames []string
+	for propName := range props {
+		propNames = append(propNames, propName)
+	}
+	sort.Strings(propNames)
+
+	for _, propName := range propNames {
+		prop := props[propName]
+
+		t, err := tsPropertyType(prop, names)
+		if err != nil {
+			return errors.Wrapf(err, "declare %s of %s", propName, o.Identifier())
+		}
+
+		key := propName
+		if !reTSIdentifier.MatchString(key) {
+			key = strconv.Quote(key)
+		}
+
+		writeTSComment(buf, fieldIndent, prop.Description())
+		fmt.Fprintf(buf, "%s%s?: %s;\n", fieldIndent, key, t)
+	}
+
+	fmt.Fprintf(buf, "%s}\n", indent)
+	return nil
+}
+
+func writeTSComment(buf *bytes.Buffer, indent, text string) {
+	if text == "" {
+		return
+	}
+
+	text = strings.Replace(text, "*/", "*\\/", -1)
+	lines := strings.Split(text, "\n")
+
+	fmt.Fprintf(buf, "%s/**\n", indent)
+	for _, line := range lines {
+		fmt.Fprintf(buf, "%s%s\n", indent, strings.TrimRight(" * "+line, " \t"))
+	}
+	fmt.Fprintf(buf, "%s */\n", indent)
+}
+
+// tsPropertyType returns the TypeScript type of a property.
+func tsPropertyType(p Property, names map[string]string) (string, error) {
+	switch t := p.(type) {
+	case *ReferenceField:
+		return tsRefType(t.Ref(), names)
+	case *LiteralField:
+		return tsLiteralType(t, names)
+	default:
+		return "", errors.Errorf("unknown field type %T", t)
+	}
+}
+
+func tsLiteralType(f *LiteralField, names map[string]string) (string, error) {
+	switch f.Format() {
+	case formatIntOrString, formatQuantity:
+		return "number | string", nil
+	}
+
+	switch f.FieldType() {
+	case "array":
+		if f.Ref() == "" {
+			return tsScalarType(f.ItemType()) + "[]", nil
+		}
+
+		t, err := tsRefType(f.Ref(), names)
+		if err != nil {
+			return "", err
+		}
+		return t + "[]", nil
+	case "object":
+		if f.Ref() != "" {
+			return tsRefType(f.Ref(), names)
+		}
+
+		if mv := f.MapValue(); mv != nil {
+			t, err := tsMapValueType(mv, names)
+			if err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("{ [key: string]: %s }", t), nil
+		}
+
+		return "{ [key: string]: any }", nil
+	}
+
+	return tsScalarType(f.FieldType()), nil
+}
+
+func tsMapValueType(mv *MapValue, names map[string]string) (string, error) {
+	switch {
+	case mv.Ref != "":
+		return tsRefType(mv.Ref, names)
+	case mv.Format == formatIntOrString, mv.Format == formatQuantity:
+		return "number | string", nil
+	case mv.Type == "array":
+		return "any[]", nil
+	case mv.Type == "object":
+		return "{ [key: string]: any }", nil
+	}
+
+	return tsScalarType(mv.Type), nil
+}
+
+func tsScalarType(swaggerType string) string {
+	switch swaggerType {
+	case "integer", "number":
+		return "number"
+	case "string", "boolean":
+		return swaggerType
+	}
+
+	return "any"
+}
+
+func tsRefType(ref string, names map[string]string) (string, error) {
+	name, ok := names[ref]
+	if !ok {
+		return "", errors.Errorf("%s was not found", ref)
+	}
+
+	return name, nil
+}
@@ -0,0 +1,98 @@
+package ksonnet
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateTypeScript(t *testing.T) {
+	c := initCatalog(t, "swagger-1.8.json")
+
+	ts, err := GenerateTypeScript(c)
+	require.NoError(t, err)
+
+	s := string(ts)
+	assert.Contains(t, s, `    export interface Deployment {
+      apiVersion: "apps/v1beta2";
+      kind: "Deployment";
+      /**
+       * Standard object metadata.
+       */
+      metadata?: meta.v1.ObjectMeta;
+      /**
+       * Specification of the desired behavior of the Deployment.
+       */
+      spec?: apps.v1beta2.DeploymentSpec;
+    }
+`)
+	assert.Contains(t, s, "      maxSurge?: number | string;\n")
+	assert.Contains(t, s, "      ports?: core.v1.ContainerPort[];\n")
+	assert.Contains(t, s, "      args?: string[];\n")
+	assert.Contains(t, s, "      labels?: { [key: string]: string };\n")
+
+	// objects without a version are in the namespace of their group.
+	assert.Contains(t, s, "  export interface Info {\n")
+}
+
+func TestGenerateTypeScript_generic(t *testing.T) {
+	c := initCatalog(t, "generic.json", CatalogOptGeneric(genericRoots...))
+
+	ts, err := GenerateTypeScript(c)
+	require.NoError(t, err)
+
+	expected := `export namespace compute {
+  export namespace v1 {
+    /**
+     * A disk attached to an instance.
+     */
+    export interface AttachedDisk {
+      /**
+       * Whether the instance boots from the disk.
+       */
+      boot?: boolean;
+      /**
+       * Size of the disk in GB.
+       */
+      sizeGb?: number;
+    }
+    /**
+     * A virtual machine instance.
+     */
+    export interface Instance {
+      /**
+       * Disks attached to the instance.
+       */
+      disks?: compute.v1.AttachedDisk[];
+      /**
+       * Machine type of the instance.
+       */
+      machineType?: string;
+      /**
+       * Name of the instance.
+       */
+      name?: string;
+    }
+  }
+}
+export namespace core {
+  export namespace v2 {
+`
+	require.True(t, len(ts) > len(expected))
+	assert.Equal(t, expected, string(ts[:len(expected)]))
+}
+
+func Test_tsNames(t *testing.T) {
+	objects := []Object{
+		NewField("a.v1.Thing", "", "api", "a", "v1", "Thing", nil),
+		NewField("b.v1.Thing", "", "api", "a", "v1", "Thing", nil),
+		NewField("a.v1.lower-case", "", "api", "a", "v1", "lower-case", nil),
+	}
+
+	assert.Equal(t, map[string]string{
+		"a.v1.Thing":      "a.v1.Thing",
+		"b.v1.Thing":      "a.v1.Thing2",
+		"a.v1.lower-case": "a.v1.Lowercase",
+	}, tsNames(objects))
+}
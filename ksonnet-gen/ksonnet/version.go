@@ -27,7 +27,8 @@ func NewVersion(name, group string) *Version {
 	return v
 }
 
-// APIObjects returns a slice of APIObjects sorted by name.
+// APIObjects returns a slice of APIObjects sorted by name. APIObjects with
+// the same name are sorted by their definition.
 func (v *Version) APIObjects() []APIObject {
 	var objects []APIObject
 	for _, resource := range v.resources {
@@ -35,6 +36,9 @@ func (v *Version) APIObjects() []APIObject {
 	}
 
 	sort.Slice(objects, func(i, j int) bool {
+		if objects[i].Kind() == objects[j].Kind() {
+			return objects[i].resource.Identifier() < objects[j].resource.Identifier()
+		}
 		return objects[i].Kind() < objects[j].Kind()
 	})
 
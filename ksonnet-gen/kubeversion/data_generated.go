@@ -0,0 +1,2516 @@
+// Code generated by mkdata.go. DO NOT EDIT.
+
+package kubeversion
+
+// embeddedFiles are the version data files in the data directory.
+var embeddedFiles = map[string]string{
+	"k.libsonnet": `local k8s = import "k8s.libsonnet";
+
+local apps = k8s.apps;
+local core = k8s.core;
+local extensions = k8s.extensions;
+
+local hidden = {
+  mapContainers(f):: {
+    local podContainers = super.spec.template.spec.containers,
+    spec+: {
+      template+: {
+        spec+: {
+          // IMPORTANT: This overwrites the 'containers' field
+          // for this deployment.
+          containers: std.map(f, podContainers),
+        },
+      },
+    },
+  },
+
+  mapContainersWithName(names, f) ::
+    local nameSet =
+      if std.type(names) == "array"
+      then std.set(names)
+      else std.set([names]);
+    local inNameSet(name) = std.length(std.setInter(nameSet, std.set([name]))) > 0;
+    self.mapContainers(
+      function(c)
+        if std.objectHas(c, "name") && inNameSet(c.name)
+        then f(c)
+        else c
+    ),
+};
+
+k8s + {
+  apps:: apps + {
+    v1beta1:: apps.v1beta1 + {
+      local v1beta1 = apps.v1beta1,
+
+      daemonSet:: v1beta1.daemonSet + {
+        mapContainers(f):: hidden.mapContainers(f),
+        mapContainersWithName(names, f):: hidden.mapContainersWithName(names, f),
+      },
+
+      deployment:: v1beta1.deployment + {
+        mapContainers(f):: hidden.mapContainers(f),
+        mapContainersWithName(names, f):: hidden.mapContainersWithName(names, f),
+      },
+    },
+  },
+
+  core:: core + {
+    v1:: core.v1 + {
+      list:: {
+        new(items)::
+          {apiVersion: "v1"} +
+          {kind: "List"} +
+          self.items(items),
+
+        items(items):: if std.type(items) == "array" then {items+: items} else {items+: [items]},
+      },
+    },
+  },
+
+  extensions:: extensions + {
+    v1beta1:: extensions.v1beta1 + {
+      local v1beta1 = extensions.v1beta1,
+
+      daemonSet:: v1beta1.daemonSet + {
+        mapContainers(f):: hidden.mapContainers(f),
+        mapContainersWithName(names, f):: hidden.mapContainersWithName(names, f),
+      },
+
+      deployment:: v1beta1.deployment + {
+        mapContainers(f):: hidden.mapContainers(f),
+        mapContainersWithName(names, f):: hidden.mapContainersWithName(names, f),
+      },
+    },
+  },
+}
+`,
+	"v1.7.0.json": `{
+  "version": "v1.7.0",
+  "range": ">=1.7.0 <1.8.0",
+  "beta": false,
+  "kSource": "k.libsonnet",
+  "idAliases": {
+    "APIGroup": "apiGroup",
+    "APIGroupList": "apiGroupList",
+    "APIResource": "apiResource",
+    "APIResourceList": "apiResourceList",
+    "APIVersion": "apiVersion",
+    "APIVersions": "apiVersions",
+    "AWSElasticBlockStoreVolumeSource": "awsElasticBlockStoreVolumeSource",
+    "CephFSVolumeSource": "cephFsVolumeSource",
+    "DownwardAPIProjection": "downwardApiProjection",
+    "DownwardAPIVolumeFile": "downwardApiVolumeFile",
+    "DownwardAPIVolumeSource": "downwardApiVolumeSource",
+    "FCVolumeSource": "fcVolumeSource",
+    "FSGroupStrategyOptions": "fsGroupStrategyOptions",
+    "GCEPersistentDiskVolumeSource": "gcePersistentDiskVolumeSource",
+    "HTTPGetAction": "httpGetAction",
+    "HTTPHeader": "httpHeader",
+    "HTTPIngressPath": "httpIngressPath",
+    "HTTPIngressRuleValue": "httpIngressRuleValue",
+    "IDRange": "idRange",
+    "ISCSIVolumeSource": "iscsiVolumeSource",
+    "IngressTLS": "ingressTls",
+    "NFSVolumeSource": "nfsVolumeSource",
+    "RBDVolumeSource": "rbdVolumeSource",
+    "SELinuxOptions": "seLinuxOptions",
+    "SELinuxStrategyOptions": "seLinuxStrategyOptions",
+    "ScaleIOVolumeSource": "scaleIoVolumeSource",
+    "ServerAddressByClientCIDR": "serverAddressByClientCidr",
+    "TCPSocketAction": "tcpSocketAction",
+    "bootID": "bootId",
+    "clientCIDR": "clientCidr",
+    "clusterIP": "clusterIp",
+    "containerID": "containerId",
+    "currentCPUUtilizationPercentage": "currentCpuUtilizationPercentage",
+    "datasetUUID": "datasetUuid",
+    "diskURI": "diskUri",
+    "downwardAPI": "downwardApi",
+    "externalID": "externalId",
+    "externalIPs": "externalIps",
+    "hostIP": "hostIp",
+    "hostIPC": "hostIpc",
+    "hostPID": "hostPid",
+    "imageID": "imageId",
+    "loadBalancerIP": "loadBalancerIp",
+    "local": "localStorage",
+    "machineID": "machineId",
+    "nonResourceURLs": "nonResourceUrls",
+    "pdID": "pdId",
+    "podCIDR": "podCidr",
+    "podIP": "podIp",
+    "providerID": "providerId",
+    "scaleIO": "scaleIo",
+    "serverAddressByClientCIDRs": "serverAddressByClientCidrs",
+    "systemUUID": "systemUuid",
+    "targetCPUUtilizationPercentage": "targetCpuUtilizationPercentage",
+    "targetWWNs": "targetWwns",
+    "volumeID": "volumeId"
+  },
+  "constructorSpecs": {
+    "io.k8s.kubernetes.pkg.api.v1.ConfigMap": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "name",
+            "relativePath": "mixin.metadata.withName"
+          },
+          {
+            "id": "data"
+          }
+        ]
+      }
+    ],
+    "io.k8s.kubernetes.pkg.api.v1.ConfigMapList": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "items"
+          }
+        ]
+      }
+    ],
+    "io.k8s.kubernetes.pkg.api.v1.Container": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "name"
+          },
+          {
+            "id": "image"
+          }
+        ]
+      }
+    ],
+    "io.k8s.kubernetes.pkg.api.v1.ContainerPort": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "containerPort"
+          }
+        ]
+      },
+      {
+        "id": "newNamed",
+        "params": [
+          {
+            "id": "name"
+          },
+          {
+            "id": "containerPort"
+          }
+        ]
+      }
+    ],
+    "io.k8s.kubernetes.pkg.api.v1.EndpointsList": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "items"
+          }
+        ]
+      }
+    ],
+    "io.k8s.kubernetes.pkg.api.v1.EnvVar": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "name"
+          },
+          {
+            "id": "value"
+          }
+        ]
+      },
+      {
+        "id": "fromSecretRef",
+        "params": [
+          {
+            "id": "name"
+          },
+          {
+            "id": "secretRefName",
+            "relativePath": "mixin.valueFrom.secretKeyRef.withName"
+          },
+          {
+            "id": "secretRefKey",
+            "relativePath": "mixin.valueFrom.secretKeyRef.withKey"
+          }
+        ]
+      },
+      {
+        "id": "fromFieldPath",
+        "params": [
+          {
+            "id": "name"
+          },
+          {
+            "id": "fieldPath",
+            "relativePath": "mixin.valueFrom.fieldRef.withFieldPath"
+          }
+        ]
+      }
+    ],
+    "io.k8s.kubernetes.pkg.api.v1.EventList": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "items"
+          }
+        ]
+      }
+    ],
+    "io.k8s.kubernetes.pkg.api.v1.KeyToPath": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "key"
+          },
+          {
+            "id": "path"
+          }
+        ]
+      }
+    ],
+    "io.k8s.kubernetes.pkg.api.v1.LimitRangeList": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "items"
+          }
+        ]
+      }
+    ],
+    "io.k8s.kubernetes.pkg.api.v1.Namespace": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "name",
+            "relativePath": "mixin.metadata.withName"
+          }
+        ]
+      }
+    ],
+    "io.k8s.kubernetes.pkg.api.v1.NamespaceList": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "items"
+          }
+        ]
+      }
+    ],
+    "io.k8s.kubernetes.pkg.api.v1.NodeList": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "items"
+          }
+        ]
+      }
+    ],
+    "io.k8s.kubernetes.pkg.api.v1.PersistentVolumeClaimList": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "items"
+          }
+        ]
+      }
+    ],
+    "io.k8s.kubernetes.pkg.api.v1.PersistentVolumeList": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "items"
+          }
+        ]
+      }
+    ],
+    "io.k8s.kubernetes.pkg.api.v1.PodList": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "items"
+          }
+        ]
+      }
+    ],
+    "io.k8s.kubernetes.pkg.api.v1.PodTemplateList": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "items"
+          }
+        ]
+      }
+    ],
+    "io.k8s.kubernetes.pkg.api.v1.ReplicationControllerList": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "items"
+          }
+        ]
+      }
+    ],
+    "io.k8s.kubernetes.pkg.api.v1.ResourceQuotaList": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "items"
+          }
+        ]
+      }
+    ],
+    "io.k8s.kubernetes.pkg.api.v1.Secret": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "name",
+            "relativePath": "mixin.metadata.withName"
+          },
+          {
+            "id": "data"
+          },
+          {
+            "id": "type",
+            "defaultValue": "\"Opaque\""
+          }
+        ]
+      },
+      {
+        "id": "fromString",
+        "params": [
+          {
+            "id": "name",
+            "relativePath": "mixin.metadata.withName"
+          },
+          {
+            "id": "stringData"
+          },
+          {
+            "id": "type",
+            "defaultValue": "\"Opaque\""
+          }
+        ]
+      }
+    ],
+    "io.k8s.kubernetes.pkg.api.v1.SecretList": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "items"
+          }
+        ]
+      }
+    ],
+    "io.k8s.kubernetes.pkg.api.v1.Service": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "name",
+            "relativePath": "mixin.metadata.withName"
+          },
+          {
+            "id": "selector",
+            "relativePath": "mixin.spec.withSelector"
+          },
+          {
+            "id": "ports",
+            "relativePath": "mixin.spec.withPorts"
+          }
+        ]
+      }
+    ],
+    "io.k8s.kubernetes.pkg.api.v1.ServiceAccount": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "name",
+            "relativePath": "mixin.metadata.withName"
+          }
+        ]
+      }
+    ],
+    "io.k8s.kubernetes.pkg.api.v1.ServiceAccountList": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "items"
+          }
+        ]
+      }
+    ],
+    "io.k8s.kubernetes.pkg.api.v1.ServiceList": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "items"
+          }
+        ]
+      }
+    ],
+    "io.k8s.kubernetes.pkg.api.v1.ServicePort": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "port"
+          },
+          {
+            "id": "targetPort"
+          }
+        ]
+      },
+      {
+        "id": "newNamed",
+        "params": [
+          {
+            "id": "name"
+          },
+          {
+            "id": "port"
+          },
+          {
+            "id": "targetPort"
+          }
+        ]
+      }
+    ],
+    "io.k8s.kubernetes.pkg.api.v1.Volume": [
+      {
+        "id": "fromConfigMap",
+        "params": [
+          {
+            "id": "name"
+          },
+          {
+            "id": "configMapName",
+            "relativePath": "mixin.configMap.withName"
+          },
+          {
+            "id": "configMapItems",
+            "relativePath": "mixin.configMap.withItems"
+          }
+        ]
+      },
+      {
+        "id": "fromEmptyDir",
+        "params": [
+          {
+            "id": "name"
+          },
+          {
+            "id": "emptyDir",
+            "relativePath": "mixin.emptyDir.mixinInstance",
+            "defaultValue": "{}"
+          }
+        ]
+      },
+      {
+        "id": "fromPersistentVolumeClaim",
+        "params": [
+          {
+            "id": "name"
+          },
+          {
+            "id": "claimName",
+            "relativePath": "mixin.persistentVolumeClaim.withClaimName"
+          }
+        ]
+      },
+      {
+        "id": "fromHostPath",
+        "params": [
+          {
+            "id": "name"
+          },
+          {
+            "id": "hostPath",
+            "relativePath": "mixin.hostPath.withPath"
+          }
+        ]
+      },
+      {
+        "id": "fromSecret",
+        "params": [
+          {
+            "id": "name"
+          },
+          {
+            "id": "secretName",
+            "relativePath": "mixin.secret.withSecretName"
+          }
+        ]
+      }
+    ],
+    "io.k8s.kubernetes.pkg.api.v1.VolumeMount": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "name"
+          },
+          {
+            "id": "mountPath"
+          },
+          {
+            "id": "readOnly",
+            "defaultValue": "false"
+          }
+        ]
+      }
+    ],
+    "io.k8s.kubernetes.pkg.apis.apps.v1beta1.Deployment": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "name",
+            "relativePath": "mixin.metadata.withName"
+          },
+          {
+            "id": "replicas",
+            "relativePath": "mixin.spec.withReplicas"
+          },
+          {
+            "id": "containers",
+            "relativePath": "mixin.spec.template.spec.withContainers"
+          },
+          {
+            "id": "podLabels",
+            "relativePath": "mixin.spec.template.metadata.withLabels",
+            "defaultValue": "{app: name}"
+          }
+        ]
+      }
+    ],
+    "io.k8s.kubernetes.pkg.apis.apps.v1beta1.DeploymentList": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "items"
+          }
+        ]
+      }
+    ],
+    "io.k8s.kubernetes.pkg.apis.apps.v1beta1.DeploymentRollback": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "name"
+          }
+        ]
+      }
+    ],
+    "io.k8s.kubernetes.pkg.apis.apps.v1beta1.Scale": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "replicas",
+            "relativePath": "mixin.spec.withReplicas"
+          }
+        ]
+      }
+    ],
+    "io.k8s.kubernetes.pkg.apis.apps.v1beta1.StatefulSet": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "name",
+            "relativePath": "mixin.metadata.withName"
+          },
+          {
+            "id": "replicas",
+            "relativePath": "mixin.spec.withReplicas"
+          },
+          {
+            "id": "containers",
+            "relativePath": "mixin.spec.template.spec.withContainers"
+          },
+          {
+            "id": "volumeClaims",
+            "relativePath": "mixin.spec.withVolumeClaimTemplates"
+          },
+          {
+            "id": "podLabels",
+            "relativePath": "mixin.spec.template.metadata.withLabels",
+            "defaultValue": "{app: name}"
+          }
+        ]
+      }
+    ],
+    "io.k8s.kubernetes.pkg.apis.apps.v1beta1.StatefulSetList": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "items"
+          }
+        ]
+      }
+    ],
+    "io.k8s.kubernetes.pkg.apis.authentication.v1.TokenReview": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "token",
+            "relativePath": "mixin.spec.withToken"
+          }
+        ]
+      }
+    ],
+    "io.k8s.kubernetes.pkg.apis.authentication.v1beta1.TokenReview": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "token",
+            "relativePath": "mixin.spec.withToken"
+          }
+        ]
+      }
+    ],
+    "io.k8s.kubernetes.pkg.apis.autoscaling.v1.HorizontalPodAutoscalerList": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "items"
+          }
+        ]
+      }
+    ],
+    "io.k8s.kubernetes.pkg.apis.autoscaling.v1.Scale": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "replicas",
+            "relativePath": "mixin.spec.withReplicas"
+          }
+        ]
+      }
+    ],
+    "io.k8s.kubernetes.pkg.apis.autoscaling.v2alpha1.HorizontalPodAutoscalerList": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "items"
+          }
+        ]
+      }
+    ],
+    "io.k8s.kubernetes.pkg.apis.batch.v1.JobList": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "items"
+          }
+        ]
+      }
+    ],
+    "io.k8s.kubernetes.pkg.apis.batch.v2alpha1.CronJobList": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "items"
+          }
+        ]
+      }
+    ],
+    "io.k8s.kubernetes.pkg.apis.certificates.v1beta1.CertificateSigningRequestList": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "items"
+          }
+        ]
+      }
+    ],
+    "io.k8s.kubernetes.pkg.apis.extensions.v1beta1.Deployment": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "name",
+            "relativePath": "mixin.metadata.withName"
+          },
+          {
+            "id": "replicas",
+            "relativePath": "mixin.spec.withReplicas"
+          },
+          {
+            "id": "containers",
+            "relativePath": "mixin.spec.template.spec.withContainers"
+          },
+          {
+            "id": "podLabels",
+            "relativePath": "mixin.spec.template.metadata.withLabels",
+            "defaultValue": "{app: name}"
+          }
+        ]
+      }
+    ],
+    "io.k8s.kubernetes.pkg.apis.extensions.v1beta1.DeploymentList": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "items"
+          }
+        ]
+      }
+    ],
+    "io.k8s.kubernetes.pkg.apis.extensions.v1beta1.DeploymentRollback": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "name"
+          }
+        ]
+      }
+    ],
+    "io.k8s.kubernetes.pkg.apis.extensions.v1beta1.Scale": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "replicas",
+            "relativePath": "mixin.spec.withReplicas"
+          }
+        ]
+      }
+    ],
+    "io.k8s.kubernetes.pkg.apis.extensions.v1beta1.StatefulSet": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "name",
+            "relativePath": "mixin.metadata.withName"
+          },
+          {
+            "id": "replicas",
+            "relativePath": "mixin.spec.withReplicas"
+          },
+          {
+            "id": "containers",
+            "relativePath": "mixin.spec.template.spec.withContainers"
+          },
+          {
+            "id": "volumeClaims",
+            "relativePath": "mixin.spec.withVolumeClaimTemplates"
+          },
+          {
+            "id": "podLabels",
+            "relativePath": "mixin.spec.template.metadata.withLabels",
+            "defaultValue": "{app: name}"
+          }
+        ]
+      }
+    ],
+    "io.k8s.kubernetes.pkg.apis.extensions.v1beta1.StatefulSetList": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "items"
+          }
+        ]
+      }
+    ]
+  },
+  "propertyBlacklist": {
+    "io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta": [
+      "creationTimestamp",
+      "deletionTimestamp",
+      "generation",
+      "ownerReferences",
+      "resourceVersion",
+      "selfLink",
+      "uid"
+    ],
+    "io.k8s.apimachinery.pkg.apis.meta.v1.Status": [
+      "metadata",
+      "status"
+    ],
+    "io.k8s.kubernetes.pkg.api.v1.ComponentCondition": [
+      "error",
+      "status"
+    ],
+    "io.k8s.kubernetes.pkg.api.v1.ComponentStatusList": [
+      "metadata"
+    ],
+    "io.k8s.kubernetes.pkg.api.v1.ConfigMapList": [
+      "metadata"
+    ],
+    "io.k8s.kubernetes.pkg.api.v1.EndpointsList": [
+      "metadata"
+    ],
+    "io.k8s.kubernetes.pkg.api.v1.EventList": [
+      "metadata"
+    ],
+    "io.k8s.kubernetes.pkg.api.v1.LimitRangeList": [
+      "metadata"
+    ],
+    "io.k8s.kubernetes.pkg.api.v1.Namespace": [
+      "status"
+    ],
+    "io.k8s.kubernetes.pkg.api.v1.NamespaceList": [
+      "metadata"
+    ],
+    "io.k8s.kubernetes.pkg.api.v1.Node": [
+      "status"
+    ],
+    "io.k8s.kubernetes.pkg.api.v1.NodeCondition": [
+      "status"
+    ],
+    "io.k8s.kubernetes.pkg.api.v1.NodeList": [
+      "metadata"
+    ],
+    "io.k8s.kubernetes.pkg.api.v1.PersistentVolume": [
+      "status"
+    ],
+    "io.k8s.kubernetes.pkg.api.v1.PersistentVolumeClaim": [
+      "status"
+    ],
+    "io.k8s.kubernetes.pkg.api.v1.PersistentVolumeClaimList": [
+      "metadata"
+    ],
+    "io.k8s.kubernetes.pkg.api.v1.PersistentVolumeList": [
+      "metadata"
+    ],
+    "io.k8s.kubernetes.pkg.api.v1.Pod": [
+      "status"
+    ],
+    "io.k8s.kubernetes.pkg.api.v1.PodCondition": [
+      "status"
+    ],
+    "io.k8s.kubernetes.pkg.api.v1.PodList": [
+      "metadata"
+    ],
+    "io.k8s.kubernetes.pkg.api.v1.PodTemplateList": [
+      "metadata"
+    ],
+    "io.k8s.kubernetes.pkg.api.v1.ReplicationController": [
+      "status"
+    ],
+    "io.k8s.kubernetes.pkg.api.v1.ReplicationControllerCondition": [
+      "status"
+    ],
+    "io.k8s.kubernetes.pkg.api.v1.ReplicationControllerList": [
+      "metadata"
+    ],
+    "io.k8s.kubernetes.pkg.api.v1.ResourceQuota": [
+      "status"
+    ],
+    "io.k8s.kubernetes.pkg.api.v1.ResourceQuotaList": [
+      "metadata"
+    ],
+    "io.k8s.kubernetes.pkg.api.v1.SecretList": [
+      "metadata"
+    ],
+    "io.k8s.kubernetes.pkg.api.v1.Service": [
+      "status"
+    ],
+    "io.k8s.kubernetes.pkg.api.v1.ServiceAccountList": [
+      "metadata"
+    ],
+    "io.k8s.kubernetes.pkg.api.v1.ServiceList": [
+      "metadata"
+    ],
+    "io.k8s.kubernetes.pkg.apis.apps.v1beta1.Deployment": [
+      "status"
+    ],
+    "io.k8s.kubernetes.pkg.apis.apps.v1beta1.DeploymentCondition": [
+      "status"
+    ],
+    "io.k8s.kubernetes.pkg.apis.apps.v1beta1.DeploymentList": [
+      "metadata"
+    ],
+    "io.k8s.kubernetes.pkg.apis.apps.v1beta1.Scale": [
+      "status"
+    ],
+    "io.k8s.kubernetes.pkg.apis.apps.v1beta1.StatefulSet": [
+      "status"
+    ],
+    "io.k8s.kubernetes.pkg.apis.apps.v1beta1.StatefulSetList": [
+      "metadata"
+    ],
+    "io.k8s.kubernetes.pkg.apis.authentication.v1.TokenReview": [
+      "status"
+    ],
+    "io.k8s.kubernetes.pkg.apis.authentication.v1.TokenReviewStatus": [
+      "error"
+    ],
+    "io.k8s.kubernetes.pkg.apis.authentication.v1beta1.TokenReview": [
+      "status"
+    ],
+    "io.k8s.kubernetes.pkg.apis.authentication.v1beta1.TokenReviewStatus": [
+      "error"
+    ],
+    "io.k8s.kubernetes.pkg.apis.authorization.v1.LocalSubjectAccessReview": [
+      "status"
+    ],
+    "io.k8s.kubernetes.pkg.apis.authorization.v1.SelfSubjectAccessReview": [
+      "status"
+    ],
+    "io.k8s.kubernetes.pkg.apis.authorization.v1.SubjectAccessReview": [
+      "status"
+    ],
+    "io.k8s.kubernetes.pkg.apis.authorization.v1beta1.LocalSubjectAccessReview": [
+      "status"
+    ],
+    "io.k8s.kubernetes.pkg.apis.authorization.v1beta1.SelfSubjectAccessReview": [
+      "status"
+    ],
+    "io.k8s.kubernetes.pkg.apis.authorization.v1beta1.SubjectAccessReview": [
+      "status"
+    ],
+    "io.k8s.kubernetes.pkg.apis.autoscaling.v1.HorizontalPodAutoscaler": [
+      "status"
+    ],
+    "io.k8s.kubernetes.pkg.apis.autoscaling.v1.HorizontalPodAutoscalerList": [
+      "metadata"
+    ],
+    "io.k8s.kubernetes.pkg.apis.autoscaling.v1.Scale": [
+      "status"
+    ],
+    "io.k8s.kubernetes.pkg.apis.autoscaling.v2alpha1.HorizontalPodAutoscaler": [
+      "status"
+    ],
+    "io.k8s.kubernetes.pkg.apis.autoscaling.v2alpha1.HorizontalPodAutoscalerList": [
+      "metadata"
+    ],
+    "io.k8s.kubernetes.pkg.apis.batch.v1.Job": [
+      "status"
+    ],
+    "io.k8s.kubernetes.pkg.apis.batch.v1.JobCondition": [
+      "status"
+    ],
+    "io.k8s.kubernetes.pkg.apis.batch.v1.JobList": [
+      "metadata"
+    ],
+    "io.k8s.kubernetes.pkg.apis.batch.v2alpha1.CronJob": [
+      "status"
+    ],
+    "io.k8s.kubernetes.pkg.apis.batch.v2alpha1.CronJobList": [
+      "metadata"
+    ],
+    "io.k8s.kubernetes.pkg.apis.certificates.v1beta1.CertificateSigningRequest": [
+      "status"
+    ],
+    "io.k8s.kubernetes.pkg.apis.certificates.v1beta1.CertificateSigningRequestList": [
+      "metadata"
+    ],
+    "io.k8s.kubernetes.pkg.apis.extensions.v1beta1.DaemonSet": [
+      "status"
+    ],
+    "io.k8s.kubernetes.pkg.apis.extensions.v1beta1.DaemonSetList": [
+      "metadata"
+    ],
+    "io.k8s.kubernetes.pkg.apis.extensions.v1beta1.DaemonSetSpec": [
+      "templateGeneration"
+    ],
+    "io.k8s.kubernetes.pkg.apis.extensions.v1beta1.Deployment": [
+      "status"
+    ],
+    "io.k8s.kubernetes.pkg.apis.extensions.v1beta1.DeploymentCondition": [
+      "status"
+    ],
+    "io.k8s.kubernetes.pkg.apis.extensions.v1beta1.DeploymentList": [
+      "metadata"
+    ],
+    "io.k8s.kubernetes.pkg.apis.extensions.v1beta1.Ingress": [
+      "status"
+    ],
+    "io.k8s.kubernetes.pkg.apis.extensions.v1beta1.IngressList": [
+      "metadata"
+    ],
+    "io.k8s.kubernetes.pkg.apis.extensions.v1beta1.NetworkPolicyList": [
+      "metadata"
+    ],
+    "io.k8s.kubernetes.pkg.apis.extensions.v1beta1.PodSecurityPolicyList": [
+      "metadata"
+    ],
+    "io.k8s.kubernetes.pkg.apis.extensions.v1beta1.ReplicaSet": [
+      "status"
+    ],
+    "io.k8s.kubernetes.pkg.apis.extensions.v1beta1.ReplicaSetCondition": [
+      "status"
+    ],
+    "io.k8s.kubernetes.pkg.apis.extensions.v1beta1.ReplicaSetList": [
+      "metadata"
+    ],
+    "io.k8s.kubernetes.pkg.apis.extensions.v1beta1.Scale": [
+      "status"
+    ],
+    "io.k8s.kubernetes.pkg.apis.extensions.v1beta1.ThirdPartyResourceList": [
+      "metadata"
+    ],
+    "io.k8s.kubernetes.pkg.apis.policy.v1beta1.PodDisruptionBudget": [
+      "status"
+    ],
+    "io.k8s.kubernetes.pkg.apis.policy.v1beta1.PodDisruptionBudgetList": [
+      "metadata"
+    ],
+    "io.k8s.kubernetes.pkg.apis.rbac.v1alpha1.ClusterRoleBindingList": [
+      "metadata"
+    ],
+    "io.k8s.kubernetes.pkg.apis.rbac.v1alpha1.ClusterRoleList": [
+      "metadata"
+    ],
+    "io.k8s.kubernetes.pkg.apis.rbac.v1alpha1.RoleBindingList": [
+      "metadata"
+    ],
+    "io.k8s.kubernetes.pkg.apis.rbac.v1alpha1.RoleList": [
+      "metadata"
+    ],
+    "io.k8s.kubernetes.pkg.apis.rbac.v1beta1.ClusterRoleBindingList": [
+      "metadata"
+    ],
+    "io.k8s.kubernetes.pkg.apis.rbac.v1beta1.ClusterRoleList": [
+      "metadata"
+    ],
+    "io.k8s.kubernetes.pkg.apis.rbac.v1beta1.RoleBindingList": [
+      "metadata"
+    ],
+    "io.k8s.kubernetes.pkg.apis.rbac.v1beta1.RoleList": [
+      "metadata"
+    ],
+    "io.k8s.kubernetes.pkg.apis.settings.v1alpha1.PodPresetList": [
+      "metadata"
+    ],
+    "io.k8s.kubernetes.pkg.apis.storage.v1.StorageClassList": [
+      "metadata"
+    ],
+    "io.k8s.kubernetes.pkg.apis.storage.v1beta1.StorageClassList": [
+      "metadata"
+    ]
+  }
+}
+`,
+	"v1.8.0.json": `{
+  "version": "v1.8.0",
+  "range": ">=1.8.0 <1.9.0",
+  "inherits": "v1.7.0",
+  "beta": true,
+  "idAliases": {
+    "APIService": "apiService",
+    "APIServiceCondition": "apiServiceCondition",
+    "APIServiceList": "apiServiceList",
+    "APIServiceSpec": "apiServiceSpec",
+    "APIServiceStatus": "apiServiceStatus",
+    "IPBlock": "ipBlock",
+    "JSON": "json",
+    "cephFSPersistentVolumeSource": "cephFsPersistentVolumeSource",
+    "clientIP": "clientIp",
+    "clientIPConfig": "clientIpConfig",
+    "insecureSkipTLSVerify": "insecureSkipTlsVerify",
+    "storageOSPersistentVolumeSource": "storageOsPersistentVolumeSource",
+    "storageOSVolumeSource": "storageOsVolumeSource",
+    "storagePolicyID": "storagePolicyId"
+  },
+  "constructorSpecs": {
+    "io.k8s.api.admissionregistration.v1alpha1.ExternalAdmissionHookConfigurationList": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "items"
+          }
+        ]
+      }
+    ],
+    "io.k8s.api.admissionregistration.v1alpha1.InitializerConfigurationList": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "items"
+          }
+        ]
+      }
+    ],
+    "io.k8s.api.apps.v1beta1.ControllerRevisionList": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "items"
+          }
+        ]
+      }
+    ],
+    "io.k8s.api.apps.v1beta1.Deployment": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "name",
+            "relativePath": "mixin.metadata.withName"
+          },
+          {
+            "id": "replicas",
+            "relativePath": "mixin.spec.withReplicas"
+          },
+          {
+            "id": "containers",
+            "relativePath": "mixin.spec.template.spec.withContainers"
+          },
+          {
+            "id": "podLabels",
+            "relativePath": "mixin.spec.template.metadata.withLabels",
+            "defaultValue": "{app: name}"
+          }
+        ]
+      }
+    ],
+    "io.k8s.api.apps.v1beta1.DeploymentList": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "items"
+          }
+        ]
+      }
+    ],
+    "io.k8s.api.apps.v1beta1.DeploymentRollback": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "name"
+          }
+        ]
+      }
+    ],
+    "io.k8s.api.apps.v1beta1.Scale": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "replicas",
+            "relativePath": "mixin.spec.withReplicas"
+          }
+        ]
+      }
+    ],
+    "io.k8s.api.apps.v1beta1.StatefulSet": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "name",
+            "relativePath": "mixin.metadata.withName"
+          },
+          {
+            "id": "replicas",
+            "relativePath": "mixin.spec.withReplicas"
+          },
+          {
+            "id": "containers",
+            "relativePath": "mixin.spec.template.spec.withContainers"
+          },
+          {
+            "id": "volumeClaims",
+            "relativePath": "mixin.spec.withVolumeClaimTemplates"
+          },
+          {
+            "id": "podLabels",
+            "relativePath": "mixin.spec.template.metadata.withLabels",
+            "defaultValue": "{app: name}"
+          }
+        ]
+      }
+    ],
+    "io.k8s.api.apps.v1beta1.StatefulSetList": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "items"
+          }
+        ]
+      }
+    ],
+    "io.k8s.api.apps.v1beta2.ControllerRevisionList": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "items"
+          }
+        ]
+      }
+    ],
+    "io.k8s.api.apps.v1beta2.DaemonSetList": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "items"
+          }
+        ]
+      }
+    ],
+    "io.k8s.api.apps.v1beta2.Deployment": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "name",
+            "relativePath": "mixin.metadata.withName"
+          },
+          {
+            "id": "replicas",
+            "relativePath": "mixin.spec.withReplicas"
+          },
+          {
+            "id": "containers",
+            "relativePath": "mixin.spec.template.spec.withContainers"
+          },
+          {
+            "id": "podLabels",
+            "relativePath": "mixin.spec.template.metadata.withLabels",
+            "defaultValue": "{app: name}"
+          }
+        ]
+      }
+    ],
+    "io.k8s.api.apps.v1beta2.DeploymentList": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "items"
+          }
+        ]
+      }
+    ],
+    "io.k8s.api.apps.v1beta2.ReplicaSetList": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "items"
+          }
+        ]
+      }
+    ],
+    "io.k8s.api.apps.v1beta2.Scale": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "replicas",
+            "relativePath": "mixin.spec.withReplicas"
+          }
+        ]
+      }
+    ],
+    "io.k8s.api.apps.v1beta2.StatefulSet": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "name",
+            "relativePath": "mixin.metadata.withName"
+          },
+          {
+            "id": "replicas",
+            "relativePath": "mixin.spec.withReplicas"
+          },
+          {
+            "id": "containers",
+            "relativePath": "mixin.spec.template.spec.withContainers"
+          },
+          {
+            "id": "volumeClaims",
+            "relativePath": "mixin.spec.withVolumeClaimTemplates"
+          },
+          {
+            "id": "podLabels",
+            "relativePath": "mixin.spec.template.metadata.withLabels",
+            "defaultValue": "{app: name}"
+          }
+        ]
+      }
+    ],
+    "io.k8s.api.apps.v1beta2.StatefulSetList": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "items"
+          }
+        ]
+      }
+    ],
+    "io.k8s.api.authentication.v1.TokenReview": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "token",
+            "relativePath": "mixin.spec.withToken"
+          }
+        ]
+      }
+    ],
+    "io.k8s.api.authentication.v1beta1.TokenReview": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "token",
+            "relativePath": "mixin.spec.withToken"
+          }
+        ]
+      }
+    ],
+    "io.k8s.api.autoscaling.v1.HorizontalPodAutoscalerList": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "items"
+          }
+        ]
+      }
+    ],
+    "io.k8s.api.autoscaling.v1.Scale": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "replicas",
+            "relativePath": "mixin.spec.withReplicas"
+          }
+        ]
+      }
+    ],
+    "io.k8s.api.autoscaling.v2beta1.HorizontalPodAutoscalerList": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "items"
+          }
+        ]
+      }
+    ],
+    "io.k8s.api.batch.v1.JobList": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "items"
+          }
+        ]
+      }
+    ],
+    "io.k8s.api.batch.v1beta1.CronJobList": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "items"
+          }
+        ]
+      }
+    ],
+    "io.k8s.api.batch.v2alpha1.CronJobList": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "items"
+          }
+        ]
+      }
+    ],
+    "io.k8s.api.certificates.v1beta1.CertificateSigningRequestList": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "items"
+          }
+        ]
+      }
+    ],
+    "io.k8s.api.core.v1.ConfigMap": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "name",
+            "relativePath": "mixin.metadata.withName"
+          },
+          {
+            "id": "data"
+          }
+        ]
+      }
+    ],
+    "io.k8s.api.core.v1.ConfigMapList": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "items"
+          }
+        ]
+      }
+    ],
+    "io.k8s.api.core.v1.Container": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "name"
+          },
+          {
+            "id": "image"
+          }
+        ]
+      }
+    ],
+    "io.k8s.api.core.v1.ContainerPort": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "containerPort"
+          }
+        ]
+      },
+      {
+        "id": "newNamed",
+        "params": [
+          {
+            "id": "name"
+          },
+          {
+            "id": "containerPort"
+          }
+        ]
+      }
+    ],
+    "io.k8s.api.core.v1.EndpointsList": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "items"
+          }
+        ]
+      }
+    ],
+    "io.k8s.api.core.v1.EnvVar": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "name"
+          },
+          {
+            "id": "value"
+          }
+        ]
+      },
+      {
+        "id": "fromSecretRef",
+        "params": [
+          {
+            "id": "name"
+          },
+          {
+            "id": "secretRefName",
+            "relativePath": "mixin.valueFrom.secretKeyRef.withName"
+          },
+          {
+            "id": "secretRefKey",
+            "relativePath": "mixin.valueFrom.secretKeyRef.withKey"
+          }
+        ]
+      },
+      {
+        "id": "fromFieldPath",
+        "params": [
+          {
+            "id": "name"
+          },
+          {
+            "id": "fieldPath",
+            "relativePath": "mixin.valueFrom.fieldRef.withFieldPath"
+          }
+        ]
+      }
+    ],
+    "io.k8s.api.core.v1.EventList": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "items"
+          }
+        ]
+      }
+    ],
+    "io.k8s.api.core.v1.KeyToPath": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "key"
+          },
+          {
+            "id": "path"
+          }
+        ]
+      }
+    ],
+    "io.k8s.api.core.v1.LimitRangeList": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "items"
+          }
+        ]
+      }
+    ],
+    "io.k8s.api.core.v1.Namespace": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "name",
+            "relativePath": "mixin.metadata.withName"
+          }
+        ]
+      }
+    ],
+    "io.k8s.api.core.v1.NamespaceList": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "items"
+          }
+        ]
+      }
+    ],
+    "io.k8s.api.core.v1.NodeList": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "items"
+          }
+        ]
+      }
+    ],
+    "io.k8s.api.core.v1.PersistentVolumeClaimList": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "items"
+          }
+        ]
+      }
+    ],
+    "io.k8s.api.core.v1.PersistentVolumeList": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "items"
+          }
+        ]
+      }
+    ],
+    "io.k8s.api.core.v1.PodList": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "items"
+          }
+        ]
+      }
+    ],
+    "io.k8s.api.core.v1.PodTemplateList": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "items"
+          }
+        ]
+      }
+    ],
+    "io.k8s.api.core.v1.ReplicationControllerList": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "items"
+          }
+        ]
+      }
+    ],
+    "io.k8s.api.core.v1.ResourceQuotaList": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "items"
+          }
+        ]
+      }
+    ],
+    "io.k8s.api.core.v1.Secret": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "name",
+            "relativePath": "mixin.metadata.withName"
+          },
+          {
+            "id": "data"
+          },
+          {
+            "id": "type",
+            "defaultValue": "\"Opaque\""
+          }
+        ]
+      },
+      {
+        "id": "fromString",
+        "params": [
+          {
+            "id": "name",
+            "relativePath": "mixin.metadata.withName"
+          },
+          {
+            "id": "stringData"
+          },
+          {
+            "id": "type",
+            "defaultValue": "\"Opaque\""
+          }
+        ]
+      }
+    ],
+    "io.k8s.api.core.v1.SecretList": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "items"
+          }
+        ]
+      }
+    ],
+    "io.k8s.api.core.v1.Service": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "name",
+            "relativePath": "mixin.metadata.withName"
+          },
+          {
+            "id": "selector",
+            "relativePath": "mixin.spec.withSelector"
+          },
+          {
+            "id": "ports",
+            "relativePath": "mixin.spec.withPorts"
+          }
+        ]
+      }
+    ],
+    "io.k8s.api.core.v1.ServiceAccount": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "name",
+            "relativePath": "mixin.metadata.withName"
+          }
+        ]
+      }
+    ],
+    "io.k8s.api.core.v1.ServiceAccountList": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "items"
+          }
+        ]
+      }
+    ],
+    "io.k8s.api.core.v1.ServiceList": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "items"
+          }
+        ]
+      }
+    ],
+    "io.k8s.api.core.v1.ServicePort": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "port"
+          },
+          {
+            "id": "targetPort"
+          }
+        ]
+      },
+      {
+        "id": "newNamed",
+        "params": [
+          {
+            "id": "name"
+          },
+          {
+            "id": "port"
+          },
+          {
+            "id": "targetPort"
+          }
+        ]
+      }
+    ],
+    "io.k8s.api.core.v1.Volume": [
+      {
+        "id": "fromConfigMap",
+        "params": [
+          {
+            "id": "name"
+          },
+          {
+            "id": "configMapName",
+            "relativePath": "mixin.configMap.withName"
+          },
+          {
+            "id": "configMapItems",
+            "relativePath": "mixin.configMap.withItems"
+          }
+        ]
+      },
+      {
+        "id": "fromEmptyDir",
+        "params": [
+          {
+            "id": "name"
+          },
+          {
+            "id": "emptyDir",
+            "relativePath": "mixin.emptyDir.mixinInstance",
+            "defaultValue": "{}"
+          }
+        ]
+      },
+      {
+        "id": "fromPersistentVolumeClaim",
+        "params": [
+          {
+            "id": "name"
+          },
+          {
+            "id": "claimName",
+            "relativePath": "mixin.persistentVolumeClaim.withClaimName"
+          }
+        ]
+      },
+      {
+        "id": "fromHostPath",
+        "params": [
+          {
+            "id": "name"
+          },
+          {
+            "id": "hostPath",
+            "relativePath": "mixin.hostPath.withPath"
+          }
+        ]
+      },
+      {
+        "id": "fromSecret",
+        "params": [
+          {
+            "id": "name"
+          },
+          {
+            "id": "secretName",
+            "relativePath": "mixin.secret.withSecretName"
+          }
+        ]
+      }
+    ],
+    "io.k8s.api.core.v1.VolumeMount": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "name"
+          },
+          {
+            "id": "mountPath"
+          },
+          {
+            "id": "readOnly",
+            "defaultValue": "false"
+          }
+        ]
+      }
+    ],
+    "io.k8s.api.extensions.v1beta1.DaemonSetList": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "items"
+          }
+        ]
+      }
+    ],
+    "io.k8s.api.extensions.v1beta1.Deployment": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "name",
+            "relativePath": "mixin.metadata.withName"
+          },
+          {
+            "id": "replicas",
+            "relativePath": "mixin.spec.withReplicas"
+          },
+          {
+            "id": "containers",
+            "relativePath": "mixin.spec.template.spec.withContainers"
+          },
+          {
+            "id": "podLabels",
+            "relativePath": "mixin.spec.template.metadata.withLabels",
+            "defaultValue": "{app: name}"
+          }
+        ]
+      }
+    ],
+    "io.k8s.api.extensions.v1beta1.DeploymentList": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "items"
+          }
+        ]
+      }
+    ],
+    "io.k8s.api.extensions.v1beta1.DeploymentRollback": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "name"
+          }
+        ]
+      }
+    ],
+    "io.k8s.api.extensions.v1beta1.IngressList": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "items"
+          }
+        ]
+      }
+    ],
+    "io.k8s.api.extensions.v1beta1.NetworkPolicyList": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "items"
+          }
+        ]
+      }
+    ],
+    "io.k8s.api.extensions.v1beta1.PodSecurityPolicyList": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "items"
+          }
+        ]
+      }
+    ],
+    "io.k8s.api.extensions.v1beta1.ReplicaSetList": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "items"
+          }
+        ]
+      }
+    ],
+    "io.k8s.api.extensions.v1beta1.Scale": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "replicas",
+            "relativePath": "mixin.spec.withReplicas"
+          }
+        ]
+      }
+    ],
+    "io.k8s.api.networking.v1.NetworkPolicyList": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "items"
+          }
+        ]
+      }
+    ],
+    "io.k8s.api.policy.v1beta1.PodDisruptionBudgetList": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "items"
+          }
+        ]
+      }
+    ],
+    "io.k8s.api.rbac.v1.ClusterRoleBindingList": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "items"
+          }
+        ]
+      }
+    ],
+    "io.k8s.api.rbac.v1.ClusterRoleList": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "items"
+          }
+        ]
+      }
+    ],
+    "io.k8s.api.rbac.v1.RoleBindingList": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "items"
+          }
+        ]
+      }
+    ],
+    "io.k8s.api.rbac.v1.RoleList": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "items"
+          }
+        ]
+      }
+    ],
+    "io.k8s.api.rbac.v1beta1.ClusterRoleBindingList": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "items"
+          }
+        ]
+      }
+    ],
+    "io.k8s.api.rbac.v1beta1.ClusterRoleList": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "items"
+          }
+        ]
+      }
+    ],
+    "io.k8s.api.rbac.v1beta1.RoleBindingList": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "items"
+          }
+        ]
+      }
+    ],
+    "io.k8s.api.rbac.v1beta1.RoleList": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "items"
+          }
+        ]
+      }
+    ],
+    "io.k8s.api.scheduling.v1alpha1.PriorityClassList": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "items"
+          }
+        ]
+      }
+    ],
+    "io.k8s.api.settings.v1alpha1.PodPresetList": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "items"
+          }
+        ]
+      }
+    ],
+    "io.k8s.api.storage.v1.StorageClassList": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "items"
+          }
+        ]
+      }
+    ],
+    "io.k8s.api.storage.v1beta1.StorageClassList": [
+      {
+        "id": "new",
+        "params": [
+          {
+            "id": "items"
+          }
+        ]
+      }
+    ]
+  },
+  "idBlacklist": [
+    "io.k8s.apiextensions-apiserver.pkg.apis.apiextensions.v1beta1.CustomResourceDefinition",
+    "io.k8s.apiextensions-apiserver.pkg.apis.apiextensions.v1beta1.CustomResourceDefinitionSpec",
+    "io.k8s.apiextensions-apiserver.pkg.apis.apiextensions.v1beta1.CustomResourceValidation"
+  ],
+  "propertyBlacklist": {
+    "io.k8s.api.admissionregistration.v1alpha1.ExternalAdmissionHookConfigurationList": [
+      "metadata"
+    ],
+    "io.k8s.api.admissionregistration.v1alpha1.InitializerConfigurationList": [
+      "metadata"
+    ],
+    "io.k8s.api.apps.v1beta1.ControllerRevisionList": [
+      "metadata"
+    ],
+    "io.k8s.api.apps.v1beta1.Deployment": [
+      "status"
+    ],
+    "io.k8s.api.apps.v1beta1.DeploymentCondition": [
+      "status"
+    ],
+    "io.k8s.api.apps.v1beta1.DeploymentList": [
+      "metadata"
+    ],
+    "io.k8s.api.apps.v1beta1.Scale": [
+      "status"
+    ],
+    "io.k8s.api.apps.v1beta1.StatefulSet": [
+      "status"
+    ],
+    "io.k8s.api.apps.v1beta1.StatefulSetList": [
+      "metadata"
+    ],
+    "io.k8s.api.apps.v1beta2.ControllerRevisionList": [
+      "metadata"
+    ],
+    "io.k8s.api.apps.v1beta2.DaemonSet": [
+      "status"
+    ],
+    "io.k8s.api.apps.v1beta2.DaemonSetList": [
+      "metadata"
+    ],
+    "io.k8s.api.apps.v1beta2.Deployment": [
+      "status"
+    ],
+    "io.k8s.api.apps.v1beta2.DeploymentCondition": [
+      "status"
+    ],
+    "io.k8s.api.apps.v1beta2.DeploymentList": [
+      "metadata"
+    ],
+    "io.k8s.api.apps.v1beta2.ReplicaSet": [
+      "status"
+    ],
+    "io.k8s.api.apps.v1beta2.ReplicaSetCondition": [
+      "status"
+    ],
+    "io.k8s.api.apps.v1beta2.ReplicaSetList": [
+      "metadata"
+    ],
+    "io.k8s.api.apps.v1beta2.Scale": [
+      "status"
+    ],
+    "io.k8s.api.apps.v1beta2.StatefulSet": [
+      "status"
+    ],
+    "io.k8s.api.apps.v1beta2.StatefulSetList": [
+      "metadata"
+    ],
+    "io.k8s.api.authentication.v1.TokenReview": [
+      "status"
+    ],
+    "io.k8s.api.authentication.v1beta1.TokenReview": [
+      "status"
+    ],
+    "io.k8s.api.authorization.v1.LocalSubjectAccessReview": [
+      "status"
+    ],
+    "io.k8s.api.authorization.v1.SelfSubjectAccessReview": [
+      "status"
+    ],
+    "io.k8s.api.authorization.v1.SelfSubjectRulesReview": [
+      "status"
+    ],
+    "io.k8s.api.authorization.v1.SubjectAccessReview": [
+      "status"
+    ],
+    "io.k8s.api.authorization.v1beta1.LocalSubjectAccessReview": [
+      "status"
+    ],
+    "io.k8s.api.authorization.v1beta1.SelfSubjectAccessReview": [
+      "status"
+    ],
+    "io.k8s.api.authorization.v1beta1.SelfSubjectRulesReview": [
+      "status"
+    ],
+    "io.k8s.api.authorization.v1beta1.SubjectAccessReview": [
+      "status"
+    ],
+    "io.k8s.api.autoscaling.v1.HorizontalPodAutoscaler": [
+      "status"
+    ],
+    "io.k8s.api.autoscaling.v1.HorizontalPodAutoscalerList": [
+      "metadata"
+    ],
+    "io.k8s.api.autoscaling.v1.Scale": [
+      "status"
+    ],
+    "io.k8s.api.autoscaling.v2beta1.HorizontalPodAutoscaler": [
+      "status"
+    ],
+    "io.k8s.api.autoscaling.v2beta1.HorizontalPodAutoscalerCondition": [
+      "status"
+    ],
+    "io.k8s.api.autoscaling.v2beta1.HorizontalPodAutoscalerList": [
+      "metadata"
+    ],
+    "io.k8s.api.batch.v1.Job": [
+      "status"
+    ],
+    "io.k8s.api.batch.v1.JobCondition": [
+      "status"
+    ],
+    "io.k8s.api.batch.v1.JobList": [
+      "metadata"
+    ],
+    "io.k8s.api.batch.v1beta1.CronJob": [
+      "status"
+    ],
+    "io.k8s.api.batch.v1beta1.CronJobList": [
+      "metadata"
+    ],
+    "io.k8s.api.batch.v2alpha1.CronJob": [
+      "status"
+    ],
+    "io.k8s.api.batch.v2alpha1.CronJobList": [
+      "metadata"
+    ],
+    "io.k8s.api.certificates.v1beta1.CertificateSigningRequest": [
+      "status"
+    ],
+    "io.k8s.api.certificates.v1beta1.CertificateSigningRequestList": [
+      "metadata"
+    ],
+    "io.k8s.api.core.v1.ComponentCondition": [
+      "status"
+    ],
+    "io.k8s.api.core.v1.ComponentStatusList": [
+      "metadata"
+    ],
+    "io.k8s.api.core.v1.ConfigMapList": [
+      "metadata"
+    ],
+    "io.k8s.api.core.v1.EndpointsList": [
+      "metadata"
+    ],
+    "io.k8s.api.core.v1.EventList": [
+      "metadata"
+    ],
+    "io.k8s.api.core.v1.LimitRangeList": [
+      "metadata"
+    ],
+    "io.k8s.api.core.v1.Namespace": [
+      "status"
+    ],
+    "io.k8s.api.core.v1.NamespaceList": [
+      "metadata"
+    ],
+    "io.k8s.api.core.v1.Node": [
+      "status"
+    ],
+    "io.k8s.api.core.v1.NodeCondition": [
+      "status"
+    ],
+    "io.k8s.api.core.v1.NodeList": [
+      "metadata"
+    ],
+    "io.k8s.api.core.v1.PersistentVolume": [
+      "status"
+    ],
+    "io.k8s.api.core.v1.PersistentVolumeClaim": [
+      "status"
+    ],
+    "io.k8s.api.core.v1.PersistentVolumeClaimCondition": [
+      "status"
+    ],
+    "io.k8s.api.core.v1.PersistentVolumeClaimList": [
+      "metadata"
+    ],
+    "io.k8s.api.core.v1.PersistentVolumeList": [
+      "metadata"
+    ],
+    "io.k8s.api.core.v1.Pod": [
+      "status"
+    ],
+    "io.k8s.api.core.v1.PodCondition": [
+      "status"
+    ],
+    "io.k8s.api.core.v1.PodList": [
+      "metadata"
+    ],
+    "io.k8s.api.core.v1.PodTemplateList": [
+      "metadata"
+    ],
+    "io.k8s.api.core.v1.ReplicationController": [
+      "status"
+    ],
+    "io.k8s.api.core.v1.ReplicationControllerCondition": [
+      "status"
+    ],
+    "io.k8s.api.core.v1.ReplicationControllerList": [
+      "metadata"
+    ],
+    "io.k8s.api.core.v1.ResourceQuota": [
+      "status"
+    ],
+    "io.k8s.api.core.v1.ResourceQuotaList": [
+      "metadata"
+    ],
+    "io.k8s.api.core.v1.SecretList": [
+      "metadata"
+    ],
+    "io.k8s.api.core.v1.Service": [
+      "status"
+    ],
+    "io.k8s.api.core.v1.ServiceAccountList": [
+      "metadata"
+    ],
+    "io.k8s.api.core.v1.ServiceList": [
+      "metadata"
+    ],
+    "io.k8s.api.extensions.v1beta1.DaemonSet": [
+      "status"
+    ],
+    "io.k8s.api.extensions.v1beta1.DaemonSetList": [
+      "metadata"
+    ],
+    "io.k8s.api.extensions.v1beta1.DaemonSetSpec": [
+      "templateGeneration"
+    ],
+    "io.k8s.api.extensions.v1beta1.Deployment": [
+      "status"
+    ],
+    "io.k8s.api.extensions.v1beta1.DeploymentCondition": [
+      "status"
+    ],
+    "io.k8s.api.extensions.v1beta1.DeploymentList": [
+      "metadata"
+    ],
+    "io.k8s.api.extensions.v1beta1.Ingress": [
+      "status"
+    ],
+    "io.k8s.api.extensions.v1beta1.IngressList": [
+      "metadata"
+    ],
+    "io.k8s.api.extensions.v1beta1.NetworkPolicyList": [
+      "metadata"
+    ],
+    "io.k8s.api.extensions.v1beta1.PodSecurityPolicyList": [
+      "metadata"
+    ],
+    "io.k8s.api.extensions.v1beta1.ReplicaSet": [
+      "status"
+    ],
+    "io.k8s.api.extensions.v1beta1.ReplicaSetCondition": [
+      "status"
+    ],
+    "io.k8s.api.extensions.v1beta1.ReplicaSetList": [
+      "metadata"
+    ],
+    "io.k8s.api.extensions.v1beta1.Scale": [
+      "status"
+    ],
+    "io.k8s.api.networking.v1.NetworkPolicyList": [
+      "metadata"
+    ],
+    "io.k8s.api.policy.v1beta1.PodDisruptionBudget": [
+      "status"
+    ],
+    "io.k8s.api.policy.v1beta1.PodDisruptionBudgetList": [
+      "metadata"
+    ],
+    "io.k8s.api.rbac.v1.ClusterRoleBindingList": [
+      "metadata"
+    ],
+    "io.k8s.api.rbac.v1.ClusterRoleList": [
+      "metadata"
+    ],
+    "io.k8s.api.rbac.v1.RoleBindingList": [
+      "metadata"
+    ],
+    "io.k8s.api.rbac.v1.RoleList": [
+      "metadata"
+    ],
+    "io.k8s.api.rbac.v1alpha1.ClusterRoleBindingList": [
+      "metadata"
+    ],
+    "io.k8s.api.rbac.v1alpha1.ClusterRoleList": [
+      "metadata"
+    ],
+    "io.k8s.api.rbac.v1alpha1.RoleBindingList": [
+      "metadata"
+    ],
+    "io.k8s.api.rbac.v1alpha1.RoleList": [
+      "metadata"
+    ],
+    "io.k8s.api.rbac.v1beta1.ClusterRoleBindingList": [
+      "metadata"
+    ],
+    "io.k8s.api.rbac.v1beta1.ClusterRoleList": [
+      "metadata"
+    ],
+    "io.k8s.api.rbac.v1beta1.RoleBindingList": [
+      "metadata"
+    ],
+    "io.k8s.api.rbac.v1beta1.RoleList": [
+      "metadata"
+    ],
+    "io.k8s.api.scheduling.v1alpha1.PriorityClassList": [
+      "metadata"
+    ],
+    "io.k8s.api.settings.v1alpha1.PodPresetList": [
+      "metadata"
+    ],
+    "io.k8s.api.storage.v1.StorageClassList": [
+      "metadata"
+    ],
+    "io.k8s.api.storage.v1beta1.StorageClassList": [
+      "metadata"
+    ],
+    "io.k8s.apiextensions-apiserver.pkg.apis.apiextensions.v1beta1.CustomResourceDefinition": [
+      "status"
+    ],
+    "io.k8s.apiextensions-apiserver.pkg.apis.apiextensions.v1beta1.CustomResourceDefinitionCondition": [
+      "status"
+    ],
+    "io.k8s.apiextensions-apiserver.pkg.apis.apiextensions.v1beta1.CustomResourceDefinitionList": [
+      "metadata"
+    ],
+    "io.k8s.kube-aggregator.pkg.apis.apiregistration.v1beta1.APIService": [
+      "status"
+    ],
+    "io.k8s.kube-aggregator.pkg.apis.apiregistration.v1beta1.APIServiceCondition": [
+      "status"
+    ],
+    "io.k8s.kube-aggregator.pkg.apis.apiregistration.v1beta1.APIServiceList": [
+      "metadata"
+    ]
+  }
+}
+`,
+}
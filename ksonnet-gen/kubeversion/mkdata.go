@@ -0,0 +1,55 @@
+//go:build ignore
+// +build ignore
+
+// mkdata embeds the version data files in the kubeversion package.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"log"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+func main() {
+	paths, err := filepath.Glob(filepath.Join("data", "*"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	sort.Strings(paths)
+
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by mkdata.go. DO NOT EDIT.\n\n")
+	buf.WriteString("package kubeversion\n\n")
+	buf.WriteString("// embeddedFiles are the version data files in the data directory.\n")
+	buf.WriteString("var embeddedFiles = map[string]string{\n")
+
+	for _, path := range paths {
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		content := string(b)
+		if strings.Contains(content, "`") {
+			log.Fatalf("%s contains a backquote", path)
+		}
+
+		fmt.Fprintf(&buf, "%q: `%s`,\n", filepath.Base(path), content)
+	}
+
+	buf.WriteString("}\n")
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := ioutil.WriteFile("data_generated.go", src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -10,45 +10,63 @@
 package kubeversion
 
 import (
-	"log"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
 	"strings"
 
 	"github.com/ksonnet/ksonnet-lib/ksonnet-gen/kubespec"
+	"github.com/pkg/errors"
 )
 
-// KSource returns the source of `k.libsonnet` for a specific version
+// UnknownVersionError is returned when there is no data for a version
 // of Kubernetes.
-func KSource(k8sVersion string) string {
-	var verStrs []string
-	for k := range versions {
-		verStrs = append(verStrs, k)
+type UnknownVersionError struct {
+	Version string
+	Known   []string
+}
+
+func (e *UnknownVersionError) Error() string {
+	return "unrecognized Kubernetes version " + e.Version +
+		". Currently accepts " + strings.Join(e.Known, ", ")
+}
+
+// lookup returns the data for a version of Kubernetes. The data is
+// resolved from the most recent version whose range contains it, so a
+// patch release (e.g. v1.8.4) uses the data of its minor release.
+func (d *Data) lookup(k8sVersion string) (versionData, error) {
+	v, err := parseVersion(k8sVersion)
+	if err != nil {
+		return versionData{}, errors.Wrapf(err, "parse Kubernetes version %q", k8sVersion)
 	}
 
-	verData, ok := versions[k8sVersion]
-	if !ok {
-		log.Fatalf("Unrecognized Kubernetes version %q. Currently accepts %q",
-			k8sVersion, strings.Join(verStrs, ", "))
+	var known []string
+	for _, entry := range d.versions {
+		if entry.rng(v) {
+			return entry.data, nil
+		}
+
+		known = append(known, fmt.Sprintf("%s (%s)", entry.name, entry.rangeText))
 	}
 
-	return verData.kSource
+	return versionData{}, &UnknownVersionError{Version: k8sVersion, Known: known}
 }
 
-// Beta returns the beta status of the version.
-func Beta(k8sVersion string) bool {
-	k8sVersion = strings.TrimLeft(k8sVersion, "v")
-	ver := strings.Split(k8sVersion, ".")
-	if len(ver) >= 2 {
-		if ver[0] == "1" {
-			if ver[1] == "8" {
-				k8sVersion = "v1.8.0"
-			} else if ver[1] == "7" {
-				k8sVersion = "v1.7.0"
-			}
-		}
+// KSource returns the source of `k.libsonnet` for a specific version
+// of Kubernetes.
+func (d *Data) KSource(k8sVersion string) (string, error) {
+	verData, err := d.lookup(k8sVersion)
+	if err != nil {
+		return "", err
 	}
 
-	verData, ok := versions[k8sVersion]
-	if !ok {
+	return verData.kSource, nil
+}
+
+// Beta returns the beta status of the version.
+func (d *Data) Beta(k8sVersion string) bool {
+	verData, err := d.lookup(k8sVersion)
+	if err != nil {
 		return false
 	}
 
@@ -59,31 +77,45 @@ func Beta(k8sVersion string) bool {
 // Jsonnet-appropriate identifier, for some version of Kubernetes. For
 // example, in Kubernetes v1.7.0, we might map `clusterIP` ->
 // `clusterIp`.
-func MapIdentifier(k8sVersion, id string) string {
-	verData, ok := versions[k8sVersion]
-	if !ok {
-		log.Fatalf("Unrecognized Kubernetes version '%s'", k8sVersion)
+func (d *Data) MapIdentifier(k8sVersion, id string) (string, error) {
+	verData, err := d.lookup(k8sVersion)
+	if err != nil {
+		return "", err
 	}
 
 	if alias, ok := verData.idAliases[id]; ok {
-		return alias
+		return alias, nil
 	}
-	return id
+	return id, nil
 }
 
-// IsBlacklistedProperty taks a definition name (e.g.,
-// `io.k8s.kubernetes.pkg.apis.apps.v1beta1.Deployment`), a property
-// name (e.g., `status`), and reports whether it is blacklisted for
-// some Kubernetes version. This is particularly useful when deciding
-// whether or not to generate mixins and property methods for a given
-// property (as we likely wouldn't in the case of, say, `status`).
-func IsBlacklistedID(k8sVersion string, path kubespec.DefinitionName) bool {
-	verData, ok := versions[k8sVersion]
-	if !ok {
+// IdentifierAliases returns all the identifier aliases for some
+// version of Kubernetes, keyed by the identifier they map. (e.g.,
+// `clusterIP` -> `clusterIp`)
+func (d *Data) IdentifierAliases(k8sVersion string) (map[string]string, error) {
+	verData, err := d.lookup(k8sVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	aliases := make(map[string]string)
+	for id, alias := range verData.idAliases {
+		aliases[id] = alias
+	}
+
+	return aliases, nil
+}
+
+// IsBlacklistedID takes a definition name (e.g.,
+// `io.k8s.kubernetes.pkg.apis.apps.v1beta1.Deployment`) and reports
+// whether it is blacklisted for some Kubernetes version.
+func (d *Data) IsBlacklistedID(k8sVersion string, path kubespec.DefinitionName) bool {
+	verData, err := d.lookup(k8sVersion)
+	if err != nil {
 		return false
 	}
 
-	_, ok = verData.idBlacklist[string(path)]
+	_, ok := verData.idBlacklist[string(path)]
 	return ok
 }
 
@@ -93,12 +125,12 @@ func IsBlacklistedID(k8sVersion string, path kubespec.DefinitionName) bool {
 // some Kubernetes version. This is particularly useful when deciding
 // whether or not to generate mixins and property methods for a given
 // property (as we likely wouldn't in the case of, say, `status`).
-func IsBlacklistedProperty(
+func (d *Data) IsBlacklistedProperty(
 	k8sVersion string, path kubespec.DefinitionName,
 	propertyName kubespec.PropertyName,
 ) bool {
-	verData, ok := versions[k8sVersion]
-	if !ok {
+	verData, err := d.lookup(k8sVersion)
+	if err != nil {
 		return false
 	}
 
@@ -111,16 +143,146 @@ func IsBlacklistedProperty(
 	return ok
 }
 
-func ConstructorSpec(
+// ConstructorSpec returns the custom constructors of a definition for
+// some Kubernetes version. It returns false if the definition doesn't
+// have custom constructors.
+func (d *Data) ConstructorSpec(
 	k8sVersion string, path kubespec.DefinitionName,
-) ([]CustomConstructorSpec, bool) {
-	verData, ok := versions[k8sVersion]
-	if !ok {
-		log.Fatalf("Unrecognized Kubernetes version '%s'", k8sVersion)
+) ([]CustomConstructorSpec, bool, error) {
+	verData, err := d.lookup(k8sVersion)
+	if err != nil {
+		return nil, false, err
 	}
 
 	spec, ok := verData.constructorSpecs[string(path)]
-	return spec, ok
+	return spec, ok, nil
+}
+
+// Checksum returns a checksum of the data for a specific version of
+// Kubernetes, so generated code can record the data it was generated with.
+func (d *Data) Checksum(k8sVersion string) (string, error) {
+	verData, err := d.lookup(k8sVersion)
+	if err != nil {
+		return "", err
+	}
+
+	b, err := json.Marshal(struct {
+		IDAliases         map[string]string
+		ConstructorSpecs  map[string][]CustomConstructorSpec
+		IDBlacklist       map[string]interface{}
+		PropertyBlacklist map[string]propertySet
+		KSource           string
+		Beta              bool
+	}{
+		IDAliases:         verData.idAliases,
+		ConstructorSpecs:  verData.constructorSpecs,
+		IDBlacklist:       verData.idBlacklist,
+		PropertyBlacklist: verData.propertyBlacklist,
+		KSource:           verData.kSource,
+		Beta:              verData.beta,
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "marshal data of version %s", k8sVersion)
+	}
+
+	return fmt.Sprintf("%x", sha256.Sum256(b)), nil
+}
+
+//-----------------------------------------------------------------------------
+// Functions which use the version data loaded with LoadDir, or the embedded
+// version data if LoadDir wasn't called.
+//-----------------------------------------------------------------------------
+
+// KSource returns the source of `k.libsonnet` for a specific version
+// of Kubernetes.
+func KSource(k8sVersion string) (string, error) {
+	d, err := Current()
+	if err != nil {
+		return "", err
+	}
+
+	return d.KSource(k8sVersion)
+}
+
+// Beta returns the beta status of the version.
+func Beta(k8sVersion string) bool {
+	d, err := Current()
+	if err != nil {
+		return false
+	}
+
+	return d.Beta(k8sVersion)
+}
+
+// MapIdentifier maps an identifier to a Jsonnet-appropriate identifier,
+// for some version of Kubernetes. See Data.MapIdentifier.
+func MapIdentifier(k8sVersion, id string) (string, error) {
+	d, err := Current()
+	if err != nil {
+		return "", err
+	}
+
+	return d.MapIdentifier(k8sVersion, id)
+}
+
+// IdentifierAliases returns all the identifier aliases for some
+// version of Kubernetes. See Data.IdentifierAliases.
+func IdentifierAliases(k8sVersion string) (map[string]string, error) {
+	d, err := Current()
+	if err != nil {
+		return nil, err
+	}
+
+	return d.IdentifierAliases(k8sVersion)
+}
+
+// IsBlacklistedID reports whether a definition name is blacklisted for
+// some Kubernetes version. See Data.IsBlacklistedID.
+func IsBlacklistedID(k8sVersion string, path kubespec.DefinitionName) bool {
+	d, err := Current()
+	if err != nil {
+		return false
+	}
+
+	return d.IsBlacklistedID(k8sVersion, path)
+}
+
+// IsBlacklistedProperty reports whether a property of a definition is
+// blacklisted for some Kubernetes version. See Data.IsBlacklistedProperty.
+func IsBlacklistedProperty(
+	k8sVersion string, path kubespec.DefinitionName,
+	propertyName kubespec.PropertyName,
+) bool {
+	d, err := Current()
+	if err != nil {
+		return false
+	}
+
+	return d.IsBlacklistedProperty(k8sVersion, path, propertyName)
+}
+
+// ConstructorSpec returns the custom constructors of a definition for
+// some Kubernetes version. See Data.ConstructorSpec.
+func ConstructorSpec(
+	k8sVersion string, path kubespec.DefinitionName,
+) ([]CustomConstructorSpec, bool, error) {
+	d, err := Current()
+	if err != nil {
+		return nil, false, err
+	}
+
+	return d.ConstructorSpec(k8sVersion, path)
+}
+
+// Checksum returns a checksum of the data for a specific version of
+// Kubernetes. See Data.Checksum.
+func Checksum(k8sVersion string) (string, error) {
+	d, err := Current()
+	if err != nil {
+		return "", err
+	}
+
+	return d.Checksum(k8sVersion)
 }
 
 //-----------------------------------------------------------------------------
@@ -174,17 +336,8 @@ func newPropertySet(strings ...string) propertySet {
 //   CI, and it is hence not important for this case to be covered by
 //   this code.
 type CustomConstructorSpec struct {
-	ID     string
-	Params []CustomConstructorParam
-}
-
-func newConstructor(
-	id string, params ...CustomConstructorParam,
-) CustomConstructorSpec {
-	return CustomConstructorSpec{
-		ID:     id,
-		Params: params,
-	}
+	ID     string                   `json:"id"`
+	Params []CustomConstructorParam `json:"params"`
 }
 
 // CustomConstructorParam specifies a parameter for a
@@ -205,38 +358,7 @@ func newConstructor(
 //   decision because it complicates the code, and it doesn't seem
 //   worth it since this feature is used relatively rarely.
 type CustomConstructorParam struct {
-	ID           string
-	DefaultValue *string
-	RelativePath *string
-}
-
-func newParam(name string) CustomConstructorParam {
-	return CustomConstructorParam{
-		ID:           name,
-		DefaultValue: nil,
-	}
-}
-
-func newParamWithDefault(name, def string) CustomConstructorParam {
-	return CustomConstructorParam{
-		ID:           name,
-		DefaultValue: &def,
-	}
-}
-
-func newParamNestedRef(name, relativePath string) CustomConstructorParam {
-	return CustomConstructorParam{
-		ID:           name,
-		RelativePath: &relativePath,
-	}
-}
-
-func newParamNestedRefDefault(
-	name, relativePath, def string,
-) CustomConstructorParam {
-	return CustomConstructorParam{
-		ID:           name,
-		RelativePath: &relativePath,
-		DefaultValue: &def,
-	}
+	ID           string  `json:"id"`
+	DefaultValue *string `json:"defaultValue,omitempty"`
+	RelativePath *string `json:"relativePath,omitempty"`
 }
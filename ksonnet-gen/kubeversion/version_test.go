@@ -1,6 +1,12 @@
 package kubeversion
 
-import "testing"
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
 
 func TestBeta(t *testing.T) {
 	cases := []struct {
@@ -27,3 +33,316 @@ func TestBeta(t *testing.T) {
 	}
 
 }
+
+func TestMapIdentifier(t *testing.T) {
+	cases := []struct {
+		name     string
+		version  string
+		id       string
+		expected string
+		isErr    bool
+	}{
+		{name: "alias", version: "v1.7.0", id: "hostIPC", expected: "hostIpc"},
+		{name: "inherited alias", version: "v1.8.0", id: "hostIPC", expected: "hostIpc"},
+		{name: "new alias", version: "v1.8.0", id: "clientIP", expected: "clientIp"},
+		{name: "alias from a later version", version: "v1.7.0", id: "clientIP", expected: "clientIP"},
+		{name: "patch release", version: "v1.8.4", id: "clientIP", expected: "clientIp"},
+		{name: "minor release", version: "1.8", id: "clientIP", expected: "clientIp"},
+		{name: "pre-release", version: "v1.8.0-beta.1", id: "clientIP", expected: "clientIp"},
+		{name: "unknown version", version: "v1.6.0", id: "hostIPC", isErr: true},
+		{name: "version outside of the ranges", version: "v1.9.0", id: "hostIPC", isErr: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := MapIdentifier(tc.version, tc.id)
+			if tc.isErr {
+				if _, ok := err.(*UnknownVersionError); !ok {
+					t.Fatalf("MapIdentifier() error = %v; expected an UnknownVersionError", err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("MapIdentifier() unexpected error: %v", err)
+			}
+
+			if got != tc.expected {
+				t.Errorf("MapIdentifier() got %q; expected %q", got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestIdentifierAliases(t *testing.T) {
+	aliases, err := IdentifierAliases("v1.8.0")
+	if err != nil {
+		t.Fatalf("IdentifierAliases() unexpected error: %v", err)
+	}
+
+	for id, expected := range map[string]string{"hostIPC": "hostIpc", "clientIP": "clientIp"} {
+		if got := aliases[id]; got != expected {
+			t.Errorf("IdentifierAliases()[%q] = %q; expected %q", id, got, expected)
+		}
+	}
+
+	aliases["hostIPC"] = "changed"
+	if got, _ := MapIdentifier("v1.8.0", "hostIPC"); got != "hostIpc" {
+		t.Errorf("changing the aliases changed the version data: got %q", got)
+	}
+
+	if _, err := IdentifierAliases("v1.6.0"); err == nil {
+		t.Error("IdentifierAliases() expected an error for an unknown version")
+	}
+}
+
+func TestKSource(t *testing.T) {
+	src, err := KSource("v1.8.0")
+	if err != nil {
+		t.Fatalf("KSource() unexpected error: %v", err)
+	}
+
+	if !strings.HasPrefix(src, `local k8s = import "k8s.libsonnet";`) {
+		t.Errorf("KSource() got unexpected source %q", src)
+	}
+
+	if _, err := KSource("v1.6.0"); err == nil {
+		t.Error("KSource() expected an error for an unknown version")
+	}
+}
+
+func TestConstructorSpec(t *testing.T) {
+	specs, ok, err := ConstructorSpec("v1.8.0", "io.k8s.api.apps.v1beta2.Deployment")
+	if err != nil || !ok {
+		t.Fatalf("ConstructorSpec() = %v, %v; expected a spec", ok, err)
+	}
+
+	if len(specs) != 1 || specs[0].ID != "new" || len(specs[0].Params) != 4 {
+		t.Fatalf("ConstructorSpec() got unexpected specs %#v", specs)
+	}
+
+	if p := specs[0].Params[3]; *p.DefaultValue != "{app: name}" || *p.RelativePath != "mixin.spec.template.metadata.withLabels" {
+		t.Errorf("ConstructorSpec() got unexpected param %#v", p)
+	}
+
+	if _, _, err := ConstructorSpec("v1.6.0", "io.k8s.api.apps.v1beta2.Deployment"); err == nil {
+		t.Error("ConstructorSpec() expected an error for an unknown version")
+	}
+}
+
+func TestChecksum(t *testing.T) {
+	checksum, err := Checksum("v1.8.0")
+	if err != nil {
+		t.Fatalf("Checksum() unexpected error: %v", err)
+	}
+
+	if len(checksum) != 64 {
+		t.Errorf("Checksum() got unexpected checksum %q", checksum)
+	}
+
+	if patch, err := Checksum("v1.8.4"); err != nil || patch != checksum {
+		t.Errorf("Checksum(v1.8.4) = %q, %v; expected the checksum of v1.8.0", patch, err)
+	}
+
+	if other, err := Checksum("v1.7.0"); err != nil || other == checksum {
+		t.Errorf("Checksum(v1.7.0) = %q, %v; expected a different checksum", other, err)
+	}
+
+	if _, err := Checksum("v1.6.0"); err == nil {
+		t.Error("Checksum() expected an error for an unknown version")
+	}
+}
+
+func TestEmbeddedFiles(t *testing.T) {
+	paths, err := filepath.Glob(filepath.Join("data", "*"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(paths) != len(embeddedFiles) {
+		t.Fatalf("embedded %d files; data has %d. Run go generate.", len(embeddedFiles), len(paths))
+	}
+
+	for _, path := range paths {
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if embeddedFiles[filepath.Base(path)] != string(b) {
+			t.Errorf("embedded %s is out of date. Run go generate.", path)
+		}
+	}
+}
+
+func TestLoadDir(t *testing.T) {
+	defer LoadEmbedded()
+
+	dir, err := ioutil.TempDir("", "kubeversion")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	data := `{"version": "v1.9.0", "range": ">=1.9.0 <1.10.0", "inherits": "v1.8.0", "idAliases": {"fooID": "fooId"}}`
+	if err := ioutil.WriteFile(filepath.Join(dir, "v1.9.0.json"), []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := LoadDir(dir); err != nil {
+		t.Fatalf("LoadDir() unexpected error: %v", err)
+	}
+
+	for id, expected := range map[string]string{"fooID": "fooId", "clientIP": "clientIp", "hostIPC": "hostIpc"} {
+		got, err := MapIdentifier("v1.9.3", id)
+		if err != nil || got != expected {
+			t.Errorf("MapIdentifier(%q) = %q, %v; expected %q", id, got, err, expected)
+		}
+	}
+
+	if !Beta("v1.9.0") {
+		t.Error("Beta() expected v1.9.0 to inherit the beta status of v1.8.0")
+	}
+
+	if err := LoadDir(filepath.Join(dir, "missing")); err == nil {
+		t.Error("LoadDir() expected an error for a missing directory")
+	}
+
+	if err := LoadEmbedded(); err != nil {
+		t.Fatalf("LoadEmbedded() unexpected error: %v", err)
+	}
+
+	if _, err := MapIdentifier("v1.9.0", "fooID"); err == nil {
+		t.Error("LoadEmbedded() expected the data of v1.9.0 to be discarded")
+	}
+}
+
+func TestReadDir(t *testing.T) {
+	defer LoadEmbedded()
+
+	dir, err := ioutil.TempDir("", "kubeversion")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	data := `{"version": "v1.9.0", "range": ">=1.9.0 <1.10.0", "inherits": "v1.8.0", "idAliases": {"fooID": "fooId"}}`
+	if err := ioutil.WriteFile(filepath.Join(dir, "v1.9.0.json"), []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	embedded, err := Current()
+	if err != nil {
+		t.Fatalf("Current() unexpected error: %v", err)
+	}
+
+	d, err := ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir() unexpected error: %v", err)
+	}
+
+	if got, err := d.MapIdentifier("v1.9.0", "fooID"); err != nil || got != "fooId" {
+		t.Errorf("MapIdentifier() = %q, %v; expected %q", got, err, "fooId")
+	}
+
+	if _, err := MapIdentifier("v1.9.0", "fooID"); err == nil {
+		t.Error("ReadDir() expected the package data to be unchanged")
+	}
+
+	if err := LoadDir(dir); err != nil {
+		t.Fatalf("LoadDir() unexpected error: %v", err)
+	}
+
+	if _, err := embedded.MapIdentifier("v1.9.0", "fooID"); err == nil {
+		t.Error("LoadDir() expected data returned by Current() to be unchanged")
+	}
+
+	if _, err := ReadDir(filepath.Join(dir, "missing")); err == nil {
+		t.Error("ReadDir() expected an error for a missing directory")
+	}
+}
+
+func Test_lookup_overlapping_ranges(t *testing.T) {
+	entries, err := loadVersions(map[string]string{
+		"a.json": `{"version": "v1.0.0", "range": ">=1.0.0", "idAliases": {"fooID": "fooIdA"}}`,
+		"b.json": `{"version": "v1.2.0", "range": ">=1.2.0", "idAliases": {"fooID": "fooIdB"}}`,
+		"c.json": `{"version": "v1.1.0", "idAliases": {"fooID": "fooIdC"}}`,
+	})
+	if err != nil {
+		t.Fatalf("loadVersions() unexpected error: %v", err)
+	}
+
+	d := &Data{versions: entries}
+
+	cases := map[string]string{
+		"v1.0.5": "fooIdA",
+		"v1.1.0": "fooIdC",
+		"v1.1.1": "fooIdA",
+		"v1.3.0": "fooIdB",
+	}
+
+	for version, expected := range cases {
+		got, err := d.MapIdentifier(version, "fooID")
+		if err != nil || got != expected {
+			t.Errorf("MapIdentifier(%q) = %q, %v; expected %q", version, got, err, expected)
+		}
+	}
+
+	if _, err := d.MapIdentifier("latest", "fooID"); err == nil {
+		t.Error("MapIdentifier() expected an error for an invalid version")
+	}
+}
+
+func Test_loadVersions_errors(t *testing.T) {
+	cases := []struct {
+		name  string
+		files map[string]string
+	}{
+		{
+			name:  "invalid json",
+			files: map[string]string{"a.json": `{`},
+		},
+		{
+			name:  "missing version",
+			files: map[string]string{"a.json": `{}`},
+		},
+		{
+			name: "duplicate version",
+			files: map[string]string{
+				"a.json": `{"version": "v1.0.0"}`,
+				"b.json": `{"version": "v1.0.0"}`,
+			},
+		},
+		{
+			name:  "missing parent",
+			files: map[string]string{"a.json": `{"version": "v1.0.0", "inherits": "v0.9.0"}`},
+		},
+		{
+			name: "inheritance cycle",
+			files: map[string]string{
+				"a.json": `{"version": "v1.0.0", "inherits": "v1.1.0"}`,
+				"b.json": `{"version": "v1.1.0", "inherits": "v1.0.0"}`,
+			},
+		},
+		{
+			name:  "invalid version",
+			files: map[string]string{"a.json": `{"version": "latest"}`},
+		},
+		{
+			name:  "invalid range",
+			files: map[string]string{"a.json": `{"version": "v1.0.0", "range": "1.x.y"}`},
+		},
+		{
+			name:  "missing k source",
+			files: map[string]string{"a.json": `{"version": "v1.0.0", "kSource": "k.libsonnet"}`},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := loadVersions(tc.files); err == nil {
+				t.Error("loadVersions() expected an error")
+			}
+		})
+	}
+}
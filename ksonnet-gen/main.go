@@ -1,85 +1,388 @@
 package main
 
 import (
-	"encoding/json"
+	"flag"
 	"fmt"
 	"io/ioutil"
 	"log"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 
+	"github.com/ksonnet/ksonnet-lib/ksonnet-gen/batch"
+	"github.com/ksonnet/ksonnet-lib/ksonnet-gen/detect"
+	"github.com/ksonnet/ksonnet-lib/ksonnet-gen/edit"
 	"github.com/ksonnet/ksonnet-lib/ksonnet-gen/ksonnet"
 	"github.com/ksonnet/ksonnet-lib/ksonnet-gen/kubespec"
+	"github.com/ksonnet/ksonnet-lib/ksonnet-gen/kubeversion"
 )
 
-var usage = "Usage: ksonnet-gen [path to k8s OpenAPI swagger.json] [output dir]"
+var usage = "Usage: ksonnet-gen [-version-data dir] [-deprecated-identifiers=false] [-fail-on-collisions] [-name-pattern regexp]... [-root definition]... [-json-schema] [-typescript] [path to k8s OpenAPI swagger.json] [output dir]"
+
+var editUsage = "Usage: ksonnet-gen edit [path to jsonnet file] [--set path=value]... [--delete path]..."
+
+var verifyUsage = "Usage: ksonnet-gen verify [-version-data dir] [path to k8s OpenAPI swagger.json] [lib dir]"
+
+var batchUsage = "Usage: ksonnet-gen batch [path to manifest.json]"
+
+var explainUsage = "Usage: ksonnet-gen explain [-recursive] [-version-data dir] [path to k8s OpenAPI swagger.json] [group.version.kind[.field]...]"
+
+var detectUsage = "Usage: ksonnet-gen detect [-version-data dir] [manifest dir] [path to k8s OpenAPI swagger.json]..."
+
+var (
+	versionData = flag.String("version-data", "",
+		"directory of version data files which override the embedded ones")
+	deprecatedIdentifiers = flag.Bool("deprecated-identifiers", true,
+		"also generate the identifiers of earlier ksonnet-lib releases as deprecated aliases")
+	failOnCollisions = flag.Bool("fail-on-collisions", false,
+		"fail instead of renaming identifiers which are generated for more than one property or kind")
+	jsonSchema = flag.Bool("json-schema", false,
+		"also write a JSON Schema of each type and field to the schemas directory of the output dir")
+	typeScript = flag.Bool("typescript", false,
+		"also write TypeScript declarations of the types and fields to k8s.d.ts (lib.d.ts for a generic API)")
+)
 
 func main() {
-	if len(os.Args) != 3 {
+	if len(os.Args) > 1 && os.Args[1] == "edit" {
+		runEdit(os.Args[2:])
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "verify" {
+		runVerify(os.Args[2:])
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "batch" {
+		runBatch(os.Args[2:])
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "explain" {
+		runExplain(os.Args[2:])
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "detect" {
+		runDetect(os.Args[2:])
+		return
+	}
+
+	var namePatterns, roots stringsFlag
+	flag.Var(&namePatterns, "name-pattern",
+		"regular expression with version, kind and optional group groups which describes definition names outside the Kubernetes naming scheme")
+	flag.Var(&roots, "root",
+		"root definition of a swagger API which isn't Kubernetes; the lib is generated for the roots and the definitions they refer to")
+	flag.Parse()
+
+	args := flag.Args()
+	if len(args) != 2 {
 		log.Fatal(usage)
 	}
 
-	swaggerPath := os.Args[1]
-	text, err := ioutil.ReadFile(swaggerPath)
-	if err != nil {
-		log.Fatalf("Could not read file at '%s':\n%v", swaggerPath, err)
+	if *versionData != "" {
+		if err := kubeversion.LoadDir(*versionData); err != nil {
+			log.Fatalf("Could not load version data:\n%v", err)
+		}
 	}
 
-	// Deserialize the API object.
-	s := kubespec.APISpec{}
-	err = json.Unmarshal(text, &s)
-	if err != nil {
-		log.Fatalf("Could not deserialize schema:\n%v", err)
+	var patterns []*ksonnet.NamePattern
+	for _, pattern := range namePatterns {
+		p, err := ksonnet.ParseNamePattern(pattern)
+		if err != nil {
+			log.Fatalf("Invalid -name-pattern:\n%v", err)
+		}
+		patterns = append(patterns, p)
 	}
-	s.Text = text
-	s.FilePath = filepath.Dir(swaggerPath)
 
-	// Emit Jsonnet code.
-	ksonnetLibSHA := getSHARevision(".")
-	k8sSHA := getSHARevision(s.FilePath)
-	kBytes, k8sBytes, err := ksonnet.Emit(&s, &ksonnetLibSHA, &k8sSHA)
+	opts := []ksonnet.CatalogOpt{
+		ksonnet.CatalogOptDeprecatedIdentifiers(*deprecatedIdentifiers),
+		ksonnet.CatalogOptFailOnCollisions(*failOnCollisions),
+		ksonnet.CatalogOptNamePatterns(patterns...),
+		ksonnet.CatalogOptJSONSchemas(*jsonSchema),
+		ksonnet.CatalogOptTypeScript(*typeScript),
+	}
+	if len(roots) > 0 {
+		opts = append(opts, ksonnet.CatalogOptGeneric(roots...))
+	}
+
+	lib, err := ksonnet.GenerateLib(args[0], opts...)
 	if err != nil {
-		log.Fatalf("Could not write ksonnet library:\n%v", err)
+		log.Fatalf("Could not generate ksonnet library:\n%v", err)
+	}
+
+	for _, warning := range lib.Warnings {
+		log.Printf("warning: %s", warning)
+	}
+
+	if err := writeTypeDefinitions(lib, args[1]); err != nil {
+		log.Fatalf("Could not write type definitions:\n%v", err)
+	}
+
+	// the lib of a generic API is one file.
+	if lib.Extensions == nil {
+		libOutfile := filepath.Join(args[1], "lib.libsonnet")
+		if err := ioutil.WriteFile(libOutfile, lib.K8s, 0644); err != nil {
+			log.Fatalf("Could not write `lib.libsonnet`:\n%v", err)
+		}
+		return
 	}
 
 	// Write out.
-	k8sOutfile := fmt.Sprintf("%s/%s", os.Args[2], "k8s.libsonnet")
-	err = ioutil.WriteFile(k8sOutfile, k8sBytes, 0644)
+	k8sOutfile := filepath.Join(args[1], "k8s.libsonnet")
+	err = ioutil.WriteFile(k8sOutfile, lib.K8s, 0644)
 	if err != nil {
 		log.Fatalf("Could not write `k8s.libsonnet`:\n%v", err)
 	}
 
-	kOutfile := fmt.Sprintf("%s/%s", os.Args[2], "k.libsonnet")
-	err = ioutil.WriteFile(kOutfile, kBytes, 0644)
+	kOutfile := filepath.Join(args[1], "k.libsonnet")
+	err = ioutil.WriteFile(kOutfile, lib.Extensions, 0644)
 	if err != nil {
 		log.Fatalf("Could not write `k.libsonnet`:\n%v", err)
 	}
 }
 
-func getSHARevision(dir string) string {
-	cwd, err := os.Getwd()
+// writeTypeDefinitions writes the JSON Schemas and TypeScript declarations of
+// a lib to a directory, if they were generated.
+func writeTypeDefinitions(lib *ksonnet.Lib, dir string) error {
+	if lib.JSONSchemas != nil {
+		schemaDir := filepath.Join(dir, "schemas")
+		if err := os.MkdirAll(schemaDir, 0755); err != nil {
+			return err
+		}
+
+		for name, schema := range lib.JSONSchemas {
+			if err := ioutil.WriteFile(filepath.Join(schemaDir, name), schema, 0644); err != nil {
+				return err
+			}
+		}
+	}
+
+	if lib.TypeScript != nil {
+		return ioutil.WriteFile(filepath.Join(dir, lib.TypeScriptFile()), lib.TypeScript, 0644)
+	}
+
+	return nil
+}
+
+// stringsFlag is a flag which can be set multiple times.
+type stringsFlag []string
+
+func (f *stringsFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *stringsFlag) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
+// runEdit sets and deletes the values of fields in a jsonnet file.
+func runEdit(args []string) {
+	var sets, deletes stringsFlag
+
+	fs := flag.NewFlagSet("edit", flag.ExitOnError)
+	fs.Var(&sets, "set", "set the field at a dotted path to a value, e.g. components.web.replicas=3")
+	fs.Var(&deletes, "delete", "delete the field at a dotted path")
+
+	// flags can be given before and after the file.
+	fs.Parse(args)
+	if fs.NArg() == 0 {
+		log.Fatal(editUsage)
+	}
+	filename := fs.Arg(0)
+	fs.Parse(fs.Args()[1:])
+	if fs.NArg() != 0 {
+		log.Fatal(editUsage)
+	}
+
+	src, err := ioutil.ReadFile(filename)
+	if err != nil {
+		log.Fatalf("Could not read %s:\n%v", filename, err)
+	}
+
+	for _, set := range sets {
+		parts := strings.SplitN(set, "=", 2)
+		if len(parts) != 2 {
+			log.Fatalf("Invalid --set %q, expected path=value", set)
+		}
+
+		src, err = edit.Set(filename, src, edit.ParsePath(parts[0]), edit.ParseValue(parts[1]))
+		if err != nil {
+			log.Fatalf("Could not set %s:\n%v", parts[0], err)
+		}
+	}
+
+	for _, path := range deletes {
+		src, err = edit.Delete(filename, src, edit.ParsePath(path))
+		if err != nil {
+			log.Fatalf("Could not delete %s:\n%v", path, err)
+		}
+	}
+
+	if err := ioutil.WriteFile(filename, src, 0644); err != nil {
+		log.Fatalf("Could not write %s:\n%v", filename, err)
+	}
+}
+
+// runVerify regenerates a lib from the inputs recorded in its provenance and
+// checks it matches the lib in a directory.
+func runVerify(args []string) {
+	fs := flag.NewFlagSet("verify", flag.ExitOnError)
+	dataDir := fs.String("version-data", "",
+		"directory of version data files which override the embedded ones")
+	fs.Parse(args)
+
+	if fs.NArg() != 2 {
+		log.Fatal(verifyUsage)
+	}
+
+	if *dataDir != "" {
+		if err := kubeversion.LoadDir(*dataDir); err != nil {
+			log.Fatalf("Could not load version data:\n%v", err)
+		}
+	}
+
+	files, err := readLibFiles(fs.Arg(1))
+	if err != nil {
+		log.Fatalf("Could not read ksonnet library:\n%v", err)
+	}
+
+	if err := ksonnet.Verify(fs.Arg(0), files); err != nil {
+		log.Fatalf("Could not verify ksonnet library:\n%v", err)
+	}
+}
+
+// readLibFiles reads the files a lib can have in a directory, keyed by their
+// path in it.
+func readLibFiles(dir string) (map[string][]byte, error) {
+	schemas, err := filepath.Glob(filepath.Join(dir, "schemas", "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{"k8s.libsonnet", "k.libsonnet", "lib.libsonnet", "k8s.d.ts", "lib.d.ts"}
+	for _, schema := range schemas {
+		names = append(names, filepath.Join("schemas", filepath.Base(schema)))
+	}
+
+	files := make(map[string][]byte)
+	for _, name := range names {
+		b, err := ioutil.ReadFile(filepath.Join(dir, name))
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		files[name] = b
+	}
+
+	return files, nil
+}
+
+// runBatch generates the libs listed in a manifest.
+func runBatch(args []string) {
+	if len(args) != 1 {
+		log.Fatal(batchUsage)
+	}
+
+	m, err := batch.LoadManifest(args[0])
+	if err != nil {
+		log.Fatalf("Could not load manifest:\n%v", err)
+	}
+
+	r, err := batch.Run(m)
+	if err != nil {
+		log.Fatalf("Could not generate ksonnet libraries:\n%v", err)
+	}
+
+	fmt.Print(r)
+
+	if len(r.Failed()) > 0 {
+		os.Exit(1)
+	}
+}
+
+// runExplain explains a type or field of the lib generated from a spec.
+func runExplain(args []string) {
+	fs := flag.NewFlagSet("explain", flag.ExitOnError)
+	recursive := fs.Bool("recursive", false,
+		"explain the fields of the fields as a tree")
+	dataDir := fs.String("version-data", "",
+		"directory of version data files which override the embedded ones")
+	fs.Parse(args)
+
+	if fs.NArg() != 2 {
+		log.Fatal(explainUsage)
+	}
+
+	if *dataDir != "" {
+		if err := kubeversion.LoadDir(*dataDir); err != nil {
+			log.Fatalf("Could not load version data:\n%v", err)
+		}
+	}
+
+	apiSpec, checksum, err := kubespec.Import(fs.Arg(0))
 	if err != nil {
-		log.Fatalf("Could get working directory:\n%v", err)
+		log.Fatalf("Could not import Kubernetes spec:\n%v", err)
 	}
 
-	err = os.Chdir(dir)
+	c, err := ksonnet.NewCatalog(apiSpec, ksonnet.CatalogOptChecksum(checksum))
 	if err != nil {
-		log.Fatalf("Could cd to directory of repository at '%s':\n%v", dir, err)
+		log.Fatalf("Could not create ksonnet catalog:\n%v", err)
 	}
 
-	sha, err := exec.Command("sh", "-c", "git rev-parse HEAD").Output()
+	e, err := ksonnet.Explain(c, fs.Arg(1), *recursive)
 	if err != nil {
-		log.Fatalf("Could not find SHA of HEAD:\n%v", err)
+		log.Fatalf("Could not explain %s:\n%v", fs.Arg(1), err)
 	}
 
-	err = os.Chdir(cwd)
+	if err := ksonnet.WriteExplanation(os.Stdout, e); err != nil {
+		log.Fatalf("Could not write explanation:\n%v", err)
+	}
+}
+
+// runDetect checks the manifests in a directory against several specs, and
+// fails if none of them is compatible.
+func runDetect(args []string) {
+	fs := flag.NewFlagSet("detect", flag.ExitOnError)
+	dataDir := fs.String("version-data", "",
+		"directory of version data files which override the embedded ones")
+	fs.Parse(args)
+
+	if fs.NArg() < 2 {
+		log.Fatal(detectUsage)
+	}
+
+	if *dataDir != "" {
+		if err := kubeversion.LoadDir(*dataDir); err != nil {
+			log.Fatalf("Could not load version data:\n%v", err)
+		}
+	}
+
+	manifests, err := detect.LoadDir(fs.Arg(0))
 	if err != nil {
-		log.Fatalf("Could cd back to current directory '%s':\n%v", cwd, err)
+		log.Fatalf("Could not load manifests:\n%v", err)
+	}
+
+	var catalogs []*detect.Catalog
+	for _, path := range fs.Args()[1:] {
+		c, err := detect.LoadCatalog(path)
+		if err != nil {
+			log.Fatalf("Could not load catalog:\n%v", err)
+		}
+		catalogs = append(catalogs, c)
 	}
 
-	return strings.TrimSpace(string(sha))
+	m := detect.Detect(catalogs, manifests)
+	if err := detect.WriteMatrix(os.Stdout, m); err != nil {
+		log.Fatalf("Could not write compatibility matrix:\n%v", err)
+	}
+
+	if m.Min == "" {
+		os.Exit(1)
+	}
 }
 
 func init() {
@@ -32,12 +32,15 @@ func ObjectOptOneline(oneline bool) ObjectOpt {
 // ObjectOpt is a functional option for Object.
 type ObjectOpt func(*Object)
 
-// Object is an item that can have multiple keys with values.
+// Object is an item that can have multiple keys with values. Fields and
+// locals have separate namespaces, e.g. `{ local a = 1, a: a }`. Computed
+// fields and asserts are kept by position, so their names don't have to be
+// unique.
 type Object struct {
 	Oneline bool
-	fields  map[string]Noder
-	keys    map[string]Key
-	keyList []string
+	fields  []field
+	names   map[string]int
+	locals  map[string]int
 }
 
 var _ Noder = (*Object)(nil)
@@ -70,12 +73,8 @@ func KVFromMap(m map[string]interface{}) (*Object, error) {
 
 // ValueToNoder converts a value to a Noder.
 func ValueToNoder(v interface{}) (Noder, error) {
-	if v == nil {
-		return nil, errors.New("value is nil")
-	}
-
 	switch t := v.(type) {
-	case string, float64, int, bool:
+	case nil, string, float64, int, bool:
 		return convertValueToNoder(t)
 	case []interface{}:
 		var elements []Noder
@@ -119,6 +118,8 @@ func convertMapToStringKey(m map[interface{}]interface{}) (map[string]interface{
 
 func convertValueToNoder(val interface{}) (Noder, error) {
 	switch t := val.(type) {
+	case nil:
+		return &Null{}, nil
 	case string:
 		return NewStringDouble(t), nil
 	case float64:
@@ -138,9 +139,8 @@ func convertValueToNoder(val interface{}) (Noder, error) {
 // newly generated key.
 func NewObject(opts ...ObjectOpt) *Object {
 	o := &Object{
-		fields:  make(map[string]Noder),
-		keys:    make(map[string]Key),
-		keyList: make([]string, 0),
+		names:  make(map[string]int),
+		locals: make(map[string]int),
 	}
 
 	for _, opt := range opts {
@@ -160,29 +160,54 @@ func OnelineObject(opts ...ObjectOpt) *Object {
 func (o *Object) Set(key Key, value Noder) error {
 	name := key.name
 
-	if _, ok := o.keys[name]; ok {
-		return errors.Errorf("field %q already exists in the object", name)
+	if index, kind := o.index(key); index != nil {
+		if _, ok := index[name]; ok {
+			return errors.Errorf("%s %q already exists in the object", kind, name)
+		}
+
+		index[name] = len(o.fields)
 	}
 
-	o.keys[name] = key
-	o.fields[name] = value
-	o.keyList = append(o.keyList, name)
+	o.fields = append(o.fields, field{key: key, value: value})
 
 	return nil
 
 }
 
-// Get retrieves a field by name.
+// index returns the index of the fields with the key's name, and what the key
+// is. Computed fields and asserts don't have names, so they aren't indexed.
+func (o *Object) index(key Key) (map[string]int, string) {
+	switch {
+	case key.category == ast.ObjectLocal:
+		return o.locals, "local"
+	case key.category == ast.ObjectAssert,
+		key.category == ast.ObjectFieldExpr && key.expr != nil:
+		return nil, ""
+	default:
+		return o.names, "field"
+	}
+}
+
+// Get retrieves a field by name. Fields are preferred to locals with the
+// same name.
 func (o *Object) Get(keyName string) Noder {
-	return o.fields[keyName]
+	if i, ok := o.names[keyName]; ok {
+		return o.fields[i].value
+	}
+
+	if i, ok := o.locals[keyName]; ok {
+		return o.fields[i].value
+	}
+
+	return nil
 }
 
 // Keys returns a slice of keys in the object.
 func (o *Object) Keys() []Key {
 	var keys []Key
 
-	for _, name := range o.keyList {
-		keys = append(keys, o.keys[name])
+	for _, f := range o.fields {
+		keys = append(keys, f.key)
 	}
 
 	return keys
@@ -198,26 +223,43 @@ func (o *Object) Node() ast.Node {
 		Oneline: o.Oneline,
 	}
 
-	for _, name := range o.keyList {
-		k := o.keys[name]
-		v := o.fields[name]
+	for _, f := range o.fields {
+		k, v, name := f.key, f.value, f.key.name
 
 		of := astext.ObjectField{
 			Comment: o.generateComment(k.comment),
 		}
 
-		if k.category == ast.ObjectLocal {
+		if k.category == ast.ObjectAssert {
+			of.Kind = k.category
+			if a, ok := v.(*Assert); ok {
+				of.Expr2 = a.Cond.Node()
+				if a.Message != nil {
+					of.Expr3 = a.Message.Node()
+				}
+			} else {
+				of.Expr2 = v.Node()
+			}
+
+			ao.Fields = append(ao.Fields, of)
+			continue
+		}
+
+		if k.category == ast.ObjectFieldExpr && k.expr != nil {
+			of.Expr1 = k.expr.Node()
+			of.Kind = k.category
+		} else if k.category == ast.ObjectLocal {
 			of.Id = newIdentifier(name)
 			of.Kind = k.category
 		} else if stringInSlice(name, jsonnetReservedWords) {
-			of.Expr1 = NewStringDouble(name).Node()
+			of.Expr1 = k.stringNode()
 			of.Kind = ast.ObjectFieldStr
 		} else if reField.MatchString(name) {
 			id := ast.Identifier(name)
 			of.Kind = ast.ObjectFieldID
 			of.Id = &id
 		} else {
-			of.Expr1 = NewStringDouble(name).Node()
+			of.Expr1 = k.stringNode()
 			of.Kind = ast.ObjectFieldStr
 		}
 
@@ -317,7 +359,9 @@ func (t *Number) Node() ast.Node {
 
 // Array is an an array.
 type Array struct {
-	elements []Noder
+	// Multiline prints each element of the array on its own line.
+	Multiline bool
+	elements  []Noder
 }
 
 var _ Noder = (*Array)(nil)
@@ -336,9 +380,19 @@ func (t *Array) Node() ast.Node {
 		nodes = append(nodes, element.Node())
 	}
 
-	return &ast.Array{
+	array := &ast.Array{
 		Elements: nodes,
 	}
+
+	if t.Multiline {
+		// the printer prints arrays which span lines one element per line.
+		array.NodeBase = ast.NewNodeBaseLoc(ast.LocationRange{
+			Begin: ast.Location{Line: 1},
+			End:   ast.Location{Line: 2},
+		})
+	}
+
+	return array
 }
 
 // KeyOptCategory is a functional option for setting key category
@@ -384,6 +438,16 @@ func KeyOptNamedParams(params ...OptionalArg) KeyOpt {
 	}
 }
 
+// KeyOptExpr is a functional option for computing the key's name from an expression.
+// e.g. `[expr]: value`. The key's name is only used to describe it, and doesn't
+// have to be unique in its object.
+func KeyOptExpr(expr Noder) KeyOpt {
+	return func(k *Key) {
+		k.expr = expr
+		k.category = ast.ObjectFieldExpr
+	}
+}
+
 // KeyOpt is a functional option for configuring Key.
 type KeyOpt func(k *Key)
 
@@ -401,6 +465,9 @@ type Key struct {
 	params      []string
 	namedParams []OptionalArg
 	mixin       bool
+	expr        Noder
+	// singleQuoted is set for keys parsed from single quoted strings.
+	singleQuoted bool
 }
 
 var (
@@ -445,6 +512,15 @@ func LocalKey(name string, opts ...KeyOpt) Key {
 	return NewKey(name, opts...)
 }
 
+// AssertKey is a convenience method for creating an assert key. The name only
+// describes the assert, and doesn't have to be unique in its object. The value
+// of an assert key is its condition, or an Assert for a condition with a
+// message.
+func AssertKey(name string, opts ...KeyOpt) Key {
+	opts = append(opts, KeyOptCategory(ast.ObjectAssert))
+	return NewKey(name, opts...)
+}
+
 // FunctionKey is a convenience method for creating a function key.
 func FunctionKey(name string, args []string, opts ...KeyOpt) Key {
 	opts = append(opts, KeyOptParams(args), KeyOptCategory(ast.ObjectFieldID))
@@ -474,6 +550,20 @@ func (k *Key) Method() *ast.Function {
 	return f
 }
 
+// stringNode converts the name of the key to a jsonnet string node.
+func (k *Key) stringNode() ast.Node {
+	if k.singleQuoted {
+		return &ast.LiteralString{Kind: ast.StringSingle, Value: k.name}
+	}
+
+	return NewStringDouble(k.name).Node()
+}
+
+// Name returns the name of the key.
+func (k Key) Name() string {
+	return k.name
+}
+
 // Mixin returns true if the jsonnet object should be super sugared.
 func (k Key) Mixin() bool {
 	return k.mixin
@@ -491,6 +581,12 @@ const (
 	BopGreater = ">"
 	// BopAnd is &&
 	BopAnd = "&&"
+	// BopOr is ||
+	BopOr = "||"
+	// BopMinus is -
+	BopMinus = "-"
+	// BopNotEqual is !=
+	BopNotEqual = "!="
 )
 
 // Binary represents a binary operation
@@ -550,7 +646,9 @@ func (v *Var) Node() ast.Node {
 }
 
 // Self represents self.
-type Self struct{}
+type Self struct {
+	Chainer
+}
 
 var _ Noder = (*Self)(nil)
 
@@ -559,6 +657,54 @@ func (s *Self) Node() ast.Node {
 	return &ast.Self{}
 }
 
+// Null represents null.
+type Null struct{}
+
+var _ Noder = (*Null)(nil)
+
+// Node converts null to a jsonnet null node.
+func (n *Null) Node() ast.Node {
+	return &ast.LiteralNull{}
+}
+
+// Error represents an error.
+type Error struct {
+	Expr Noder
+}
+
+var _ Noder = (*Error)(nil)
+
+// NewError creates an instance of Error.
+func NewError(expr Noder) *Error {
+	return &Error{Expr: expr}
+}
+
+// Node converts the Error to a jsonnet ast node.
+func (e *Error) Node() ast.Node {
+	return &ast.Error{
+		Expr: e.Expr.Node(),
+	}
+}
+
+// InSuper represents an `index in super` expression.
+type InSuper struct {
+	Index Noder
+}
+
+var _ Noder = (*InSuper)(nil)
+
+// NewInSuper creates an instance of InSuper.
+func NewInSuper(index Noder) *InSuper {
+	return &InSuper{Index: index}
+}
+
+// Node converts the InSuper to a jsonnet ast node.
+func (is *InSuper) Node() ast.Node {
+	return &ast.InSuper{
+		Index: is.Index.Node(),
+	}
+}
+
 // Conditional represents a conditional
 type Conditional struct {
 	Cond        Noder
@@ -650,6 +796,31 @@ func ApplyCall(method string, args ...Noder) *Apply {
 	return NewApply(NewCall(method), args, nil)
 }
 
+// Target returns the function this Apply applies.
+func (a *Apply) Target() Chainable {
+	return a.target
+}
+
+// PositionalArgs returns the positional arguments of this Apply.
+func (a *Apply) PositionalArgs() []Noder {
+	return a.positionalArgs
+}
+
+// OptionalArgs returns the named arguments of this Apply.
+func (a *Apply) OptionalArgs() []OptionalArg {
+	return a.optionalArgs
+}
+
+// SetPositionalArg replaces a positional argument of this Apply.
+func (a *Apply) SetPositionalArg(i int, arg Noder) error {
+	if i < 0 || i >= len(a.positionalArgs) {
+		return errors.Errorf("apply has no positional argument %d", i)
+	}
+
+	a.positionalArgs[i] = arg
+	return nil
+}
+
 // SetTarget sets the target of this Apply.
 func (a *Apply) SetTarget(c Chainable) {
 	a.target = c
@@ -738,9 +909,11 @@ func (c *Call) Node() ast.Node {
 	return cur.Node()
 }
 
-// Index is an index type.
+// Index is an index type. It indexes its target with ID, e.g. `target.id`,
+// or with Expr if it is set, e.g. `target[expr]`.
 type Index struct {
 	ID     string
+	Expr   Noder
 	Target Chainable
 	Chainer
 }
@@ -754,6 +927,13 @@ func NewIndex(id string) *Index {
 	}
 }
 
+// NewIndexExpr creates an instance of Index which is indexed with an expression.
+func NewIndexExpr(expr Noder) *Index {
+	return &Index{
+		Expr: expr,
+	}
+}
+
 // SetTarget sets the target for this Index.
 func (i *Index) SetTarget(c Chainable) {
 	i.Target = c
@@ -761,7 +941,12 @@ func (i *Index) SetTarget(c Chainable) {
 
 // Node converts the Index to a Jsonnet AST node.
 func (i *Index) Node() ast.Node {
-	astIndex := &ast.Index{Id: newIdentifier(i.ID)}
+	astIndex := &ast.Index{}
+	if i.Expr != nil {
+		astIndex.Index = i.Expr.Node()
+	} else {
+		astIndex.Id = newIdentifier(i.ID)
+	}
 
 	if i.Target != nil {
 		astIndex.Target = i.Target.Node()
@@ -820,7 +1005,7 @@ func (cc *CallChain) Node() ast.Node {
 		switch t := cc.links[i].(type) {
 		default:
 			panic(fmt.Sprintf("unhandled node type %T", t))
-		case *Var:
+		case *Var, *Self, *Dollar, *SuperIndex, *Parens:
 			previous = t
 		case *Index:
 			if previous != nil {
@@ -848,10 +1033,15 @@ func (cc *CallChain) Node() ast.Node {
 	return previous.Node()
 }
 
+// LocalBind binds a value to a name in a Local.
+type LocalBind struct {
+	Name  string
+	Value Noder
+}
+
 // Local is a local declaration.
 type Local struct {
-	name  string
-	value Noder
+	binds []LocalBind
 	Body  Noder
 }
 
@@ -859,20 +1049,44 @@ var _ Noder = (*Local)(nil)
 
 // NewLocal creates an instance of Local.
 func NewLocal(name string, value, body Noder) *Local {
-	return &Local{name: name, value: value, Body: body}
+	return NewLocalBinds([]LocalBind{{Name: name, Value: value}}, body)
+}
+
+// NewLocalBinds creates an instance of Local which binds multiple values,
+// e.g. `local a = 1, b = 2;`.
+func NewLocalBinds(binds []LocalBind, body Noder) *Local {
+	return &Local{binds: binds, Body: body}
+}
+
+// Binds returns the binds of the local.
+func (l *Local) Binds() []LocalBind {
+	return l.binds
+}
+
+// Name returns the name of the first bind of the local.
+func (l *Local) Name() string {
+	return l.binds[0].Name
+}
+
+// Value returns the value of the first bind of the local.
+func (l *Local) Value() Noder {
+	return l.binds[0].Value
+}
+
+// SetValue sets the value of the first bind of the local.
+func (l *Local) SetValue(value Noder) {
+	l.binds[0].Value = value
 }
 
 // Node converts the Local to a jsonnet ast node.
 func (l *Local) Node() ast.Node {
-	id := *newIdentifier(l.name)
+	local := &ast.Local{}
 
-	local := &ast.Local{
-		Binds: ast.LocalBinds{
-			{
-				Variable: id,
-				Body:     l.value.Node(),
-			},
-		},
+	for _, bind := range l.binds {
+		local.Binds = append(local.Binds, ast.LocalBind{
+			Variable: *newIdentifier(bind.Name),
+			Body:     bind.Value.Node(),
+		})
 	}
 
 	if l.Body != nil {
@@ -903,20 +1117,39 @@ func (i *Import) Node() ast.Node {
 	}
 }
 
+// FunctionOptNamedParams is a functional option for setting the optional
+// parameters of a function.
+func FunctionOptNamedParams(params ...OptionalArg) FunctionOpt {
+	return func(f *Function) {
+		f.namedParams = params
+	}
+}
+
+// FunctionOpt is a functional option for Function.
+type FunctionOpt func(*Function)
+
 // Function is a function.
 type Function struct {
-	req  []string
-	body Noder
+	req         []string
+	namedParams []OptionalArg
+	body        Noder
 }
 
 var _ Noder = (*Function)(nil)
 
-// NewFunction creates an instance of Function.
-func NewFunction(req []string, body Noder) *Function {
-	return &Function{
+// NewFunction creates an instance of Function. FunctionOpt functional options
+// can be used to configure the function.
+func NewFunction(req []string, body Noder, opts ...FunctionOpt) *Function {
+	f := &Function{
 		req:  req,
 		body: body,
 	}
+
+	for _, opt := range opts {
+		opt(f)
+	}
+
+	return f
 }
 
 // Node converts the Function to a jsonnet ast node.
@@ -932,9 +1165,338 @@ func (f *Function) Node() ast.Node {
 	}
 	fun.Parameters.Required = ids
 
+	for _, param := range f.namedParams {
+		fun.Parameters.Optional = append(fun.Parameters.Optional, param.NamedParameter())
+	}
+
 	return fun
 }
 
+// ImportStr is an importstr declaration.
+type ImportStr struct {
+	name string
+}
+
+var _ Noder = (*ImportStr)(nil)
+
+// NewImportStr creates an instance of ImportStr.
+func NewImportStr(name string) *ImportStr {
+	return &ImportStr{name: name}
+}
+
+// Node converts the ImportStr to a jsonnet ast node.
+func (i *ImportStr) Node() ast.Node {
+	file := NewStringDouble(i.name)
+
+	return &ast.ImportStr{
+		File: file.node(),
+	}
+}
+
+// UnaryOp is a unary operation.
+type UnaryOp string
+
+const (
+	// UopNot is !
+	UopNot UnaryOp = "!"
+	// UopBitwiseNot is ~
+	UopBitwiseNot UnaryOp = "~"
+	// UopPlus is +
+	UopPlus UnaryOp = "+"
+	// UopMinus is -
+	UopMinus UnaryOp = "-"
+)
+
+// Unary represents a unary operation.
+type Unary struct {
+	Op   UnaryOp
+	Expr Noder
+}
+
+var _ Noder = (*Unary)(nil)
+
+// NewUnary creates an instance of Unary.
+func NewUnary(op UnaryOp, expr Noder) *Unary {
+	return &Unary{
+		Op:   op,
+		Expr: expr,
+	}
+}
+
+// Node converts the Unary to a jsonnet ast node. This will panic if the unary
+// operator is unknown.
+func (u *Unary) Node() ast.Node {
+	op, ok := ast.UopMap[string(u.Op)]
+	if !ok {
+		panic(fmt.Sprintf("%q is an invalid unary operation", u.Op))
+	}
+
+	return &ast.Unary{
+		Op:   op,
+		Expr: u.Expr.Node(),
+	}
+}
+
+// Parens represents an expression in parentheses.
+type Parens struct {
+	Inner Noder
+	Chainer
+}
+
+var _ Chainable = (*Parens)(nil)
+
+// NewParens creates an instance of Parens.
+func NewParens(inner Noder) *Parens {
+	return &Parens{Inner: inner}
+}
+
+// Node converts the Parens to a jsonnet ast node.
+func (p *Parens) Node() ast.Node {
+	return &ast.Parens{
+		Inner: p.Inner.Node(),
+	}
+}
+
+// Dollar represents $, the outermost object.
+type Dollar struct {
+	Chainer
+}
+
+var _ Chainable = (*Dollar)(nil)
+
+// Node converts the Dollar to a jsonnet ast node.
+func (d *Dollar) Node() ast.Node {
+	return &ast.Dollar{}
+}
+
+// SuperIndex represents an index of super, e.g. `super.id`, or `super[expr]`
+// if Expr is set.
+type SuperIndex struct {
+	ID   string
+	Expr Noder
+	Chainer
+}
+
+var _ Chainable = (*SuperIndex)(nil)
+
+// NewSuperIndex creates an instance of SuperIndex.
+func NewSuperIndex(id string) *SuperIndex {
+	return &SuperIndex{ID: id}
+}
+
+// NewSuperIndexExpr creates an instance of SuperIndex which is indexed with an
+// expression.
+func NewSuperIndexExpr(expr Noder) *SuperIndex {
+	return &SuperIndex{Expr: expr}
+}
+
+// Node converts the SuperIndex to a jsonnet ast node.
+func (si *SuperIndex) Node() ast.Node {
+	if si.Expr != nil {
+		return &ast.SuperIndex{Index: si.Expr.Node()}
+	}
+
+	return &ast.SuperIndex{
+		Id: newIdentifier(si.ID),
+	}
+}
+
+// Slice represents a slice, e.g. `target[begin:end:step]`. The indexes are
+// optional.
+type Slice struct {
+	Target     Noder
+	BeginIndex Noder
+	EndIndex   Noder
+	Step       Noder
+	Chainer
+}
+
+var _ Noder = (*Slice)(nil)
+
+// NewSlice creates an instance of Slice.
+func NewSlice(target, beginIndex, endIndex, step Noder) *Slice {
+	return &Slice{
+		Target:     target,
+		BeginIndex: beginIndex,
+		EndIndex:   endIndex,
+		Step:       step,
+	}
+}
+
+// Node converts the Slice to a jsonnet ast node.
+func (s *Slice) Node() ast.Node {
+	slice := &ast.Slice{
+		Target: s.Target.Node(),
+	}
+
+	if s.BeginIndex != nil {
+		slice.BeginIndex = s.BeginIndex.Node()
+	}
+
+	if s.EndIndex != nil {
+		slice.EndIndex = s.EndIndex.Node()
+	}
+
+	if s.Step != nil {
+		slice.Step = s.Step.Node()
+	}
+
+	return slice
+}
+
+// Assert represents an assertion, e.g. `assert cond : message; rest`. The
+// message is optional.
+type Assert struct {
+	Cond    Noder
+	Message Noder
+	Rest    Noder
+}
+
+var _ Noder = (*Assert)(nil)
+
+// NewAssert creates an instance of Assert.
+func NewAssert(cond, message, rest Noder) *Assert {
+	return &Assert{
+		Cond:    cond,
+		Message: message,
+		Rest:    rest,
+	}
+}
+
+// Node converts the Assert to a jsonnet ast node.
+func (a *Assert) Node() ast.Node {
+	assert := &ast.Assert{
+		Cond: a.Cond.Node(),
+	}
+
+	if a.Message != nil {
+		assert.Message = a.Message.Node()
+	}
+
+	if a.Rest != nil {
+		assert.Rest = a.Rest.Node()
+	}
+
+	return assert
+}
+
+// ApplyBrace represents an object applied to an expression, e.g. `left { ... }`.
+type ApplyBrace struct {
+	Left  Noder
+	Right Noder
+}
+
+var _ Noder = (*ApplyBrace)(nil)
+
+// NewApplyBrace creates an instance of ApplyBrace.
+func NewApplyBrace(left, right Noder) *ApplyBrace {
+	return &ApplyBrace{
+		Left:  left,
+		Right: right,
+	}
+}
+
+// Node converts the ApplyBrace to a jsonnet ast node.
+func (ab *ApplyBrace) Node() ast.Node {
+	return &ast.ApplyBrace{
+		Left:  ab.Left.Node(),
+		Right: ab.Right.Node(),
+	}
+}
+
+// ForSpec is a `for x in expr` clause of a comprehension, with its `if cond`
+// conditions. Outer is the clause it is nested in.
+type ForSpec struct {
+	VarName    string
+	Expr       Noder
+	Conditions []Noder
+	Outer      *ForSpec
+}
+
+// NewForSpec creates an instance of ForSpec.
+func NewForSpec(varName string, expr Noder, conditions ...Noder) *ForSpec {
+	return &ForSpec{
+		VarName:    varName,
+		Expr:       expr,
+		Conditions: conditions,
+	}
+}
+
+func (fs *ForSpec) node() ast.ForSpec {
+	spec := ast.ForSpec{
+		VarName: *newIdentifier(fs.VarName),
+		Expr:    fs.Expr.Node(),
+	}
+
+	for _, cond := range fs.Conditions {
+		spec.Conditions = append(spec.Conditions, ast.IfSpec{Expr: cond.Node()})
+	}
+
+	if fs.Outer != nil {
+		outer := fs.Outer.node()
+		spec.Outer = &outer
+	}
+
+	return spec
+}
+
+// ArrayComp is an array comprehension, e.g. `[body for x in expr]`.
+type ArrayComp struct {
+	Body Noder
+	Spec *ForSpec
+}
+
+var _ Noder = (*ArrayComp)(nil)
+
+// NewArrayComp creates an instance of ArrayComp.
+func NewArrayComp(body Noder, spec *ForSpec) *ArrayComp {
+	return &ArrayComp{
+		Body: body,
+		Spec: spec,
+	}
+}
+
+// Node converts the ArrayComp to a jsonnet ast node.
+func (ac *ArrayComp) Node() ast.Node {
+	return &ast.ArrayComp{
+		Body: ac.Body.Node(),
+		Spec: ac.Spec.node(),
+	}
+}
+
+// ObjectComp is an object comprehension, e.g. `{ [key]: value for x in expr }`.
+type ObjectComp struct {
+	Key   Noder
+	Value Noder
+	Spec  *ForSpec
+}
+
+var _ Noder = (*ObjectComp)(nil)
+
+// NewObjectComp creates an instance of ObjectComp.
+func NewObjectComp(key, value Noder, spec *ForSpec) *ObjectComp {
+	return &ObjectComp{
+		Key:   key,
+		Value: value,
+		Spec:  spec,
+	}
+}
+
+// Node converts the ObjectComp to a jsonnet ast node.
+func (oc *ObjectComp) Node() ast.Node {
+	return &ast.ObjectComp{
+		Fields: ast.ObjectFields{
+			{
+				Kind:  ast.ObjectFieldExpr,
+				Hide:  ast.ObjectFieldInherit,
+				Expr1: oc.Key.Node(),
+				Expr2: oc.Value.Node(),
+			},
+		},
+		Spec: oc.Spec.node(),
+	}
+}
+
 // Combine combines multiple nodes into a single node. If one argument is passed,
 // it is returned. If two or more arguments are passed, they are combined using a
 // Binary.
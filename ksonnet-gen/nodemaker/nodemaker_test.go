@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/google/go-jsonnet/ast"
@@ -162,6 +163,54 @@ func ExampleConditional() {
 	// }
 }
 
+func ExampleError() {
+	o := NewObject()
+	k := NewKey("foo")
+
+	cond := NewBinary(NewVar("alpha"), NewVar("beta"), BopOr)
+	msg := NewBinary(NewStringDouble("invalid: "), NewVar("alpha"), BopPlus)
+	c := NewConditional(cond, NewVar("alpha"), NewError(msg))
+
+	if err := o.Set(k, c); err != nil {
+		fmt.Printf("error: %#v\n", err)
+	}
+
+	if err := printer.Fprint(os.Stdout, o.Node()); err != nil {
+		fmt.Printf("error: %#v\n", err)
+	}
+
+	// Output:
+	// {
+	//   foo:: if alpha || beta then alpha else error 'invalid: ' + alpha,
+	// }
+}
+
+func ExampleInSuper() {
+	o := NewObject()
+
+	inner := OnelineObject()
+	inner.Set(InheritedKey("[name]", KeyOptExpr(NewVar("name"))), NewVar("value"))
+
+	c := NewConditional(
+		NewInSuper(NewStringDouble("foo")),
+		NewBinary(NewCall("super.foo"), inner, BopPlus),
+		inner,
+	)
+
+	if err := o.Set(InheritedKey("foo"), c); err != nil {
+		fmt.Printf("error: %#v\n", err)
+	}
+
+	if err := printer.Fprint(os.Stdout, o.Node()); err != nil {
+		fmt.Printf("error: %#v\n", err)
+	}
+
+	// Output:
+	// {
+	//   foo: if 'foo' in super then super.foo + { [name]: value } else { [name]: value },
+	// }
+}
+
 func TestObject(t *testing.T) {
 	cases := []struct {
 		name   string
@@ -994,6 +1043,7 @@ func kvFromMap1(t *testing.T) (Noder, ast.Node) {
 		"float64": 1.0,
 		"int":     1,
 		"bool":    true,
+		"nothing": nil,
 		"obj": map[interface{}]interface{}{
 			"a": "a",
 			"b": 2,
@@ -1063,6 +1113,14 @@ func kvFromMap1(t *testing.T) (Noder, ast.Node) {
 					Expr2: &ast.LiteralNumber{Value: 1, OriginalString: "1"},
 				},
 			},
+			{
+				ObjectField: ast.ObjectField{
+					Kind:  ast.ObjectFieldID,
+					Hide:  ast.ObjectFieldInherit,
+					Id:    newIdentifier("nothing"),
+					Expr2: &ast.LiteralNull{},
+				},
+			},
 			{
 				ObjectField: ast.ObjectField{
 					Kind: ast.ObjectFieldID,
@@ -1133,7 +1191,7 @@ func kvFromMap1(t *testing.T) (Noder, ast.Node) {
 
 func kvFromMap2(t *testing.T) (Noder, ast.Node) {
 	m := map[string]interface{}{
-		"invalid": nil,
+		"invalid": struct{}{},
 	}
 
 	_, err := KVFromMap(m)
@@ -1212,6 +1270,36 @@ func TestObject_HasUniqueKeys(t *testing.T) {
 	}
 }
 
+func TestObject_namespaces(t *testing.T) {
+	o := NewObject()
+
+	require.NoError(t, o.Set(LocalKey("foo"), NewInt(1)))
+	require.NoError(t, o.Set(NewKey("foo"), NewVar("foo")))
+	require.Error(t, o.Set(LocalKey("foo"), NewInt(2)))
+
+	// computed fields and asserts don't have to have unique names.
+	for i := 0; i < 2; i++ {
+		require.NoError(t, o.Set(NewKey("[key]", KeyOptExpr(NewVar("key"))), NewInt(i)))
+		require.NoError(t, o.Set(AssertKey("assert"), NewBoolean(true)))
+	}
+
+	require.Equal(t, NewVar("foo"), o.Get("foo"))
+	require.Nil(t, o.Get("[key]"))
+	require.Len(t, o.Keys(), 6)
+
+	expected := &astext.Object{
+		Fields: []astext.ObjectField{
+			{ObjectField: ast.ObjectField{Kind: ast.ObjectLocal, Id: newIdentifier("foo"), Expr2: NewInt(1).Node()}},
+			{ObjectField: ast.ObjectField{Kind: ast.ObjectFieldID, Id: newIdentifier("foo"), Expr2: NewVar("foo").Node()}},
+			{ObjectField: ast.ObjectField{Kind: ast.ObjectFieldExpr, Expr1: NewVar("key").Node(), Expr2: NewInt(0).Node()}},
+			{ObjectField: ast.ObjectField{Kind: ast.ObjectAssert, Expr2: NewBoolean(true).Node()}},
+			{ObjectField: ast.ObjectField{Kind: ast.ObjectFieldExpr, Expr1: NewVar("key").Node(), Expr2: NewInt(1).Node()}},
+			{ObjectField: ast.ObjectField{Kind: ast.ObjectAssert, Expr2: NewBoolean(true).Node()}},
+		},
+	}
+	require.Equal(t, expected, o.Node())
+}
+
 func TestObject_RetrieveKeys(t *testing.T) {
 	o := NewObject()
 
@@ -1343,3 +1431,80 @@ func TestCallChain(t *testing.T) {
 		})
 	}
 }
+
+func TestNoder_print(t *testing.T) {
+	innerFor := NewForSpec("x", NewArray([]Noder{NewInt(1)}))
+	outerFor := NewForSpec("y", NewArray([]Noder{NewInt(2)}), NewBinary(NewVar("y"), NewInt(1), BopGreater))
+	outerFor.Outer = innerFor
+
+	assertObject := NewObject()
+	require.NoError(t, assertObject.Set(AssertKey("cond"), NewBoolean(true)))
+	require.NoError(t, assertObject.Set(AssertKey("message"),
+		NewAssert(NewCall("self.a"), NewStringDouble("a is required"), nil)))
+
+	index := NewIndexExpr(NewInt(0))
+	index.SetTarget(NewVar("items"))
+
+	superIndex := NewIndex("b")
+	superIndex.SetTarget(NewSuperIndex("a"))
+
+	multiline := NewArray([]Noder{NewInt(1), NewInt(2)})
+	multiline.Multiline = true
+
+	cases := []struct {
+		name     string
+		noder    Noder
+		expected string
+	}{
+		{name: "array comprehension", noder: NewArrayComp(NewVar("x"), NewForSpec("x", NewVar("xs"))),
+			expected: "[\n  x\n  for x in xs\n]"},
+		{name: "nested array comprehension", noder: NewArrayComp(NewBinary(NewVar("x"), NewVar("y"), BopPlus), outerFor),
+			expected: "[\n  x + y\n  for x in [1]\n  for y in [2]\n  if y > 1\n]"},
+		{name: "object comprehension", noder: NewObjectComp(NewVar("k"), NewInt(1), NewForSpec("k", NewVar("ks"))),
+			expected: "{\n  [k]: 1\n  for k in ks\n}"},
+		{name: "assert", noder: NewAssert(NewBoolean(true), NewStringDouble("message"), &Null{}),
+			expected: "assert true : 'message'; null"},
+		{name: "object asserts", noder: assertObject,
+			expected: "{\n  assert true,\n  assert self.a: 'a is required',\n}"},
+		{name: "error", noder: NewError(NewStringDouble("message")), expected: "error 'message'"},
+		{name: "unary", noder: NewUnary(UopMinus, NewParens(NewBinary(NewInt(1), NewInt(2), BopPlus))),
+			expected: "-(1 + 2)"},
+		{name: "not", noder: NewUnary(UopNot, NewBoolean(true)), expected: "!true"},
+		{name: "slice", noder: NewSlice(NewVar("items"), NewInt(1), nil, NewInt(2)), expected: "items[1::2]"},
+		{name: "slice without indexes", noder: NewSlice(NewVar("items"), nil, nil, nil), expected: "items[:]"},
+		{name: "index expression", noder: index, expected: "items[0]"},
+		{name: "importstr", noder: NewImportStr("file.txt"), expected: "importstr 'file.txt'"},
+		{name: "super index", noder: superIndex, expected: "super.a.b"},
+		{name: "super index expression", noder: NewSuperIndexExpr(NewStringDouble("$ref")), expected: "super['$ref']"},
+		{name: "in super", noder: NewInSuper(NewStringDouble("a")), expected: "'a' in super"},
+		{name: "dollar", noder: NewCallChain(&Dollar{}, NewIndex("a")), expected: "$.a"},
+		{name: "null", noder: &Null{}, expected: "null"},
+		{name: "apply brace", noder: NewApplyBrace(NewVar("base"), OnelineObject()), expected: "base {}"},
+		{name: "multiline array", noder: multiline, expected: "[\n  1,\n  2,\n]"},
+		{name: "multiple binds", noder: NewLocalBinds([]LocalBind{
+			{Name: "a", Value: NewInt(1)},
+			{Name: "f", Value: NewFunction([]string{"x"}, NewVar("x"),
+				FunctionOptNamedParams(OptionalArg{Name: "y", Default: NewInt(2)}))},
+		}, NewVar("a")), expected: "local a = 1, f(x, y=2) = x;\n\na"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, printer.Fprint(&buf, tc.noder.Node()))
+			assert.Equal(t, tc.expected, strings.TrimSuffix(buf.String(), "\n"))
+		})
+	}
+}
+
+func TestUnary_UnknownOperator(t *testing.T) {
+	u := NewUnary(UnaryOp("☃"), NewInt(1))
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("expected unknown unary operator to panic")
+		}
+	}()
+
+	u.Node()
+}
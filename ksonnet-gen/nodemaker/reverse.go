@@ -0,0 +1,724 @@
+package nodemaker
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/go-jsonnet/ast"
+	"github.com/google/go-jsonnet/parser"
+	"github.com/ksonnet/ksonnet-lib/ksonnet-gen/astext"
+	"github.com/pkg/errors"
+)
+
+// Raw is a jsonnet node which doesn't have a nodemaker equivalent. It is
+// kept as it was parsed, so it can't be edited.
+type Raw struct {
+	node ast.Node
+	Chainer
+}
+
+var _ Chainable = (*Raw)(nil)
+
+// NewRaw creates an instance of Raw.
+func NewRaw(node ast.Node) *Raw {
+	return &Raw{node: node}
+}
+
+// Node returns the jsonnet node.
+func (r *Raw) Node() ast.Node {
+	return r.node
+}
+
+// Parse parses jsonnet source and converts it to a Noder tree. Printing the
+// tree gives the same jsonnet as the source, but reformatted, e.g. `a['b']`
+// is printed as `a.b`. Source in the printer's format, like the generated
+// libs, is printed as it is. Comments on lines of their own before an object
+// field are kept as the comment of its key, and are printed as `//` comments.
+// Other comments, e.g. comments at the end of a line or before the first
+// local, are dropped. Use the edit package to change a file without
+// reprinting it.
+func Parse(filename, src string) (Noder, error) {
+	tokens, err := parser.Lex(filename, src)
+	if err != nil {
+		return nil, errors.Wrapf(err, "lex %s", filename)
+	}
+
+	node, err := parser.Parse(tokens)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parse %s", filename)
+	}
+
+	cv := &converter{comments: scanComments(src)}
+	return cv.fromNode(node)
+}
+
+// FromNode converts a jsonnet node to a Noder tree. Nodes are converted to
+// their nodemaker types, except for strings which are printed differently
+// from a StringDouble (e.g. text blocks) and `tailstrict` applies, which are
+// converted to a Raw. Jsonnet nodes don't have comments, so only the
+// comments of astext object fields are kept.
+func FromNode(node ast.Node) (Noder, error) {
+	return (&converter{}).fromNode(node)
+}
+
+// converter converts jsonnet nodes to Noders.
+type converter struct {
+	// comments are the lines of the comments of the source which are on
+	// lines of their own, by line number. A line is removed once it is
+	// the comment of a key.
+	comments map[int]string
+}
+
+// nolint: gocyclo
+func (cv *converter) fromNode(node ast.Node) (Noder, error) {
+	switch t := node.(type) {
+	case *ast.Object:
+		return cv.objectFromFields(t.Fields, nil, t.Loc(), isOneline(t.Loc()))
+	case *astext.Object:
+		var fields ast.ObjectFields
+		var comments []*astext.Comment
+		for _, field := range t.Fields {
+			fields = append(fields, field.ObjectField)
+			comments = append(comments, field.Comment)
+		}
+		return cv.objectFromFields(fields, comments, t.Loc(), t.Oneline || isOneline(t.Loc()))
+	case *ast.ObjectComp:
+		return cv.objectCompFromNode(t)
+	case *ast.Local:
+		return cv.localFromNode(t)
+	case *ast.Apply:
+		if !t.TailStrict {
+			return cv.applyFromNode(t)
+		}
+	case *ast.ApplyBrace:
+		left, err := cv.fromNode(t.Left)
+		if err != nil {
+			return nil, err
+		}
+		right, err := cv.fromNode(t.Right)
+		if err != nil {
+			return nil, err
+		}
+		return NewApplyBrace(left, right), nil
+	case *ast.Index:
+		return cv.indexFromNode(t)
+	case *ast.SuperIndex:
+		if t.Id != nil {
+			return NewSuperIndex(string(*t.Id)), nil
+		}
+
+		index, err := cv.fromNode(t.Index)
+		if err != nil {
+			return nil, err
+		}
+		return NewSuperIndexExpr(index), nil
+	case *ast.Slice:
+		return cv.sliceFromNode(t)
+	case *ast.Var:
+		return NewVar(string(t.Id)), nil
+	case *ast.Self:
+		return &Self{}, nil
+	case *ast.Dollar:
+		return &Dollar{}, nil
+	case *ast.LiteralNull:
+		return &Null{}, nil
+	case *ast.LiteralBoolean:
+		return NewBoolean(t.Value), nil
+	case *ast.LiteralNumber:
+		return &Number{number: t.Value, value: t.OriginalString}, nil
+	case *ast.LiteralString:
+		if isPlainString(t) {
+			return NewStringDouble(t.Value), nil
+		}
+	case *ast.Array:
+		elements, err := cv.fromNodes(t.Elements)
+		if err != nil {
+			return nil, err
+		}
+		array := NewArray(elements)
+		array.Multiline = len(elements) > 0 && !isOneline(t.Loc())
+		return array, nil
+	case *ast.ArrayComp:
+		body, err := cv.fromNode(t.Body)
+		if err != nil {
+			return nil, err
+		}
+		spec, err := cv.forSpecFromNode(t.Spec)
+		if err != nil {
+			return nil, err
+		}
+		return NewArrayComp(body, spec), nil
+	case *ast.Binary:
+		return cv.binaryFromNode(t)
+	case *ast.Unary:
+		expr, err := cv.fromNode(t.Expr)
+		if err != nil {
+			return nil, err
+		}
+		return NewUnary(UnaryOp(t.Op.String()), expr), nil
+	case *ast.Parens:
+		inner, err := cv.fromNode(t.Inner)
+		if err != nil {
+			return nil, err
+		}
+		return NewParens(inner), nil
+	case *ast.Conditional:
+		return cv.conditionalFromNode(t)
+	case *ast.Assert:
+		return cv.assertFromNode(t)
+	case *ast.Error:
+		expr, err := cv.fromNode(t.Expr)
+		if err != nil {
+			return nil, err
+		}
+		return NewError(expr), nil
+	case *ast.InSuper:
+		index, err := cv.fromNode(t.Index)
+		if err != nil {
+			return nil, err
+		}
+		return NewInSuper(index), nil
+	case *ast.Import:
+		return NewImport(t.File.Value), nil
+	case *ast.ImportStr:
+		return NewImportStr(t.File.Value), nil
+	case *ast.Function:
+		return cv.functionFromNode(t)
+	}
+
+	return NewRaw(node), nil
+}
+
+func (cv *converter) fromNodes(nodes ast.Nodes) ([]Noder, error) {
+	var out []Noder
+	for _, node := range nodes {
+		noder, err := cv.fromNode(node)
+		if err != nil {
+			return nil, err
+		}
+
+		out = append(out, noder)
+	}
+
+	return out, nil
+}
+
+// isOneline returns true if a parsed node begins and ends on the same line.
+func isOneline(loc *ast.LocationRange) bool {
+	return loc != nil && loc.Begin.Line != 0 && loc.Begin.Line == loc.End.Line
+}
+
+// isPlainString returns true if a string is printed the same way as a
+// StringDouble with its value.
+func isPlainString(s *ast.LiteralString) bool {
+	switch s.Kind {
+	case ast.StringDouble:
+		return true
+	case ast.StringSingle:
+		// both prefer single quotes, but keep the original quotes
+		// when the string contains both kinds.
+		return !(strings.ContainsRune(s.Value, '\'') && strings.ContainsRune(s.Value, '"'))
+	}
+
+	return false
+}
+
+// objectFromFields converts object fields to an Object. Computed fields and
+// asserts are kept by their position in the object. The comment of a field is its comment in comments, or
+// the comment on the lines between it and the field before it.
+func (cv *converter) objectFromFields(fields ast.ObjectFields, comments []*astext.Comment,
+	loc *ast.LocationRange, oneline bool) (*Object, error) {
+
+	o := NewObject(ObjectOptOneline(oneline))
+
+	var prevLine int
+	if loc != nil {
+		prevLine = loc.Begin.Line
+	}
+
+	for i, field := range fields {
+		var commentOpts []KeyOpt
+		if i < len(comments) && comments[i] != nil {
+			commentOpts = append(commentOpts, KeyOptComment(comments[i].Text))
+		} else if comment, ok := cv.comment(prevLine, fieldLine(field)); ok {
+			commentOpts = append(commentOpts, KeyOptComment(comment))
+		}
+		prevLine = fieldEndLine(field)
+
+		var name string
+		opts := append([]KeyOpt{
+			KeyOptCategory(field.Kind),
+			KeyOptVisibility(field.Hide),
+			KeyOptMixin(field.SuperSugar),
+		}, commentOpts...)
+
+		switch field.Kind {
+		case ast.ObjectFieldID, ast.ObjectLocal:
+			name = string(*field.Id)
+		case ast.ObjectFieldStr:
+			s, ok := field.Expr1.(*ast.LiteralString)
+			if !ok {
+				return nil, errors.Errorf("field name is a %T, not a string", field.Expr1)
+			}
+			name = s.Value
+			if s.Kind == ast.StringSingle {
+				opts = append(opts, func(k *Key) { k.singleQuoted = true })
+			}
+		case ast.ObjectFieldExpr:
+			expr, err := cv.fromNode(field.Expr1)
+			if err != nil {
+				return nil, err
+			}
+			// the name only describes the field in errors.
+			name = fmt.Sprintf("computed field %d", i)
+			opts = append(opts, KeyOptExpr(expr))
+		case ast.ObjectAssert:
+			value, err := cv.assertFromNode(&ast.Assert{Cond: field.Expr2, Message: field.Expr3})
+			if err != nil {
+				return nil, err
+			}
+			if err := o.Set(AssertKey("assert", commentOpts...), value); err != nil {
+				return nil, err
+			}
+			continue
+		default:
+			return nil, errors.Errorf("object field kind %v is not supported", field.Kind)
+		}
+
+		if field.Method != nil {
+			methodOpts, err := cv.methodKeyOpts(field.Method)
+			if err != nil {
+				return nil, errors.Wrapf(err, "convert parameters of %s", name)
+			}
+			opts = append(opts, methodOpts...)
+		}
+
+		value, err := cv.fromNode(field.Expr2)
+		if err != nil {
+			return nil, errors.Wrapf(err, "convert %s", name)
+		}
+
+		if err := o.Set(NewKey(name, opts...), value); err != nil {
+			return nil, err
+		}
+	}
+
+	return o, nil
+}
+
+// comment returns the comment on the lines after the line after and before
+// the line before, and removes it from the comments of the source.
+func (cv *converter) comment(after, before int) (string, bool) {
+	var lines []string
+	for line := after + 1; line < before; line++ {
+		text, ok := cv.comments[line]
+		if !ok {
+			continue
+		}
+
+		lines = append(lines, text)
+		delete(cv.comments, line)
+	}
+
+	if len(lines) == 0 {
+		return "", false
+	}
+
+	return strings.Join(lines, "\n"), true
+}
+
+// fieldLine returns the line an object field begins on, or 0 if the field
+// wasn't parsed.
+func fieldLine(field ast.ObjectField) int {
+	node := field.Expr2
+	if field.Kind == ast.ObjectFieldStr || field.Kind == ast.ObjectFieldExpr {
+		node = field.Expr1
+	}
+
+	if node == nil || node.Loc() == nil {
+		return 0
+	}
+
+	return node.Loc().Begin.Line
+}
+
+// fieldEndLine returns the line an object field ends on, or 0 if the field
+// wasn't parsed.
+func fieldEndLine(field ast.ObjectField) int {
+	var line int
+	for _, node := range []ast.Node{field.Expr2, field.Expr3} {
+		if node != nil && node.Loc() != nil && node.Loc().End.Line > line {
+			line = node.Loc().End.Line
+		}
+	}
+
+	return line
+}
+
+func (cv *converter) methodKeyOpts(fun *ast.Function) ([]KeyOpt, error) {
+	params, namedParams, err := cv.paramsFromNode(fun.Parameters)
+	if err != nil {
+		return nil, err
+	}
+
+	return []KeyOpt{KeyOptParams(params), KeyOptNamedParams(namedParams...)}, nil
+}
+
+func (cv *converter) paramsFromNode(p ast.Parameters) ([]string, []OptionalArg, error) {
+	params := []string{}
+	for _, id := range p.Required {
+		params = append(params, string(id))
+	}
+
+	var namedParams []OptionalArg
+	for _, param := range p.Optional {
+		arg := OptionalArg{Name: string(param.Name)}
+		if param.DefaultArg != nil {
+			value, err := cv.fromNode(param.DefaultArg)
+			if err != nil {
+				return nil, nil, err
+			}
+			arg.Default = value
+		}
+
+		namedParams = append(namedParams, arg)
+	}
+
+	return params, namedParams, nil
+}
+
+func (cv *converter) localFromNode(l *ast.Local) (Noder, error) {
+	var binds []LocalBind
+	for _, bind := range l.Binds {
+		var value Noder
+		var err error
+		if bind.Fun != nil {
+			// `local f(x) = body` is a function bound to f.
+			value, err = cv.functionFromNode(&ast.Function{Parameters: bind.Fun.Parameters, Body: bind.Body})
+		} else {
+			value, err = cv.fromNode(bind.Body)
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "convert local %s", bind.Variable)
+		}
+
+		binds = append(binds, LocalBind{Name: string(bind.Variable), Value: value})
+	}
+
+	body, err := cv.fromNode(l.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewLocalBinds(binds, body), nil
+}
+
+func (cv *converter) functionFromNode(f *ast.Function) (Noder, error) {
+	params, namedParams, err := cv.paramsFromNode(f.Parameters)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := cv.fromNode(f.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewFunction(params, body, FunctionOptNamedParams(namedParams...)), nil
+}
+
+func (cv *converter) objectCompFromNode(oc *ast.ObjectComp) (Noder, error) {
+	// object comprehensions can only have a computed field.
+	if len(oc.Fields) != 1 || oc.Fields[0].Kind != ast.ObjectFieldExpr {
+		return NewRaw(oc), nil
+	}
+
+	key, err := cv.fromNode(oc.Fields[0].Expr1)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := cv.fromNode(oc.Fields[0].Expr2)
+	if err != nil {
+		return nil, err
+	}
+
+	spec, err := cv.forSpecFromNode(oc.Spec)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewObjectComp(key, value, spec), nil
+}
+
+func (cv *converter) forSpecFromNode(fs ast.ForSpec) (*ForSpec, error) {
+	expr, err := cv.fromNode(fs.Expr)
+	if err != nil {
+		return nil, err
+	}
+
+	spec := NewForSpec(string(fs.VarName), expr)
+	for _, cond := range fs.Conditions {
+		noder, err := cv.fromNode(cond.Expr)
+		if err != nil {
+			return nil, err
+		}
+
+		spec.Conditions = append(spec.Conditions, noder)
+	}
+
+	if fs.Outer != nil {
+		spec.Outer, err = cv.forSpecFromNode(*fs.Outer)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return spec, nil
+}
+
+func (cv *converter) assertFromNode(a *ast.Assert) (Noder, error) {
+	var nodes []Noder
+	for _, node := range []ast.Node{a.Cond, a.Message, a.Rest} {
+		if node == nil {
+			nodes = append(nodes, nil)
+			continue
+		}
+
+		noder, err := cv.fromNode(node)
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, noder)
+	}
+
+	return NewAssert(nodes[0], nodes[1], nodes[2]), nil
+}
+
+func (cv *converter) sliceFromNode(s *ast.Slice) (Noder, error) {
+	var nodes []Noder
+	for _, node := range []ast.Node{s.Target, s.BeginIndex, s.EndIndex, s.Step} {
+		if node == nil {
+			nodes = append(nodes, nil)
+			continue
+		}
+
+		noder, err := cv.fromNode(node)
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, noder)
+	}
+
+	return NewSlice(nodes[0], nodes[1], nodes[2], nodes[3]), nil
+}
+
+// chainableFromNode converts a node which is the target of an apply or index.
+// Targets which aren't Chainable are converted to a Raw.
+func (cv *converter) chainableFromNode(node ast.Node) (Chainable, error) {
+	noder, err := cv.fromNode(node)
+	if err != nil {
+		return nil, err
+	}
+
+	if c, ok := noder.(Chainable); ok {
+		return c, nil
+	}
+
+	return NewRaw(node), nil
+}
+
+func (cv *converter) applyFromNode(a *ast.Apply) (Noder, error) {
+	target, err := cv.chainableFromNode(a.Target)
+	if err != nil {
+		return nil, err
+	}
+
+	positional, err := cv.fromNodes(a.Arguments.Positional)
+	if err != nil {
+		return nil, err
+	}
+
+	var named []OptionalArg
+	for _, arg := range a.Arguments.Named {
+		value, err := cv.fromNode(arg.Arg)
+		if err != nil {
+			return nil, err
+		}
+
+		named = append(named, OptionalArg{Name: string(arg.Name), Default: value})
+	}
+
+	return NewApply(target, positional, named), nil
+}
+
+func (cv *converter) indexFromNode(i *ast.Index) (Noder, error) {
+	target, err := cv.chainableFromNode(i.Target)
+	if err != nil {
+		return nil, err
+	}
+
+	var idx *Index
+	if i.Id != nil {
+		idx = NewIndex(string(*i.Id))
+	} else {
+		expr, err := cv.fromNode(i.Index)
+		if err != nil {
+			return nil, err
+		}
+		idx = NewIndexExpr(expr)
+	}
+	idx.SetTarget(target)
+
+	return idx, nil
+}
+
+func (cv *converter) binaryFromNode(b *ast.Binary) (Noder, error) {
+	left, err := cv.fromNode(b.Left)
+	if err != nil {
+		return nil, err
+	}
+
+	right, err := cv.fromNode(b.Right)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewBinary(left, right, BinaryOp(b.Op.String())), nil
+}
+
+func (cv *converter) conditionalFromNode(c *ast.Conditional) (Noder, error) {
+	cond, err := cv.fromNode(c.Cond)
+	if err != nil {
+		return nil, err
+	}
+
+	branchTrue, err := cv.fromNode(c.BranchTrue)
+	if err != nil {
+		return nil, err
+	}
+
+	var branchFalse Noder
+	if c.BranchFalse != nil {
+		branchFalse, err = cv.fromNode(c.BranchFalse)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return NewConditional(cond, branchTrue, branchFalse), nil
+}
+
+// scanComments returns the lines of the comments of jsonnet source which are
+// on lines of their own, by line number, without their comment markers. The
+// source has been lexed, so it doesn't have unterminated strings or comments.
+func scanComments(src string) map[int]string {
+	comments := make(map[int]string)
+	line := 1
+	// lineStart is true until something other than whitespace is on the line.
+	lineStart := true
+
+	for i := 0; i < len(src); {
+		rest := src[i:]
+
+		var n int
+		switch {
+		case rest[0] == '\n':
+			line++
+			lineStart = true
+			i++
+			continue
+		case rest[0] == ' ' || rest[0] == '\t' || rest[0] == '\r':
+			i++
+			continue
+		case rest[0] == '#' || strings.HasPrefix(rest, "//"):
+			n = strings.IndexByte(rest, '\n')
+			if n < 0 {
+				n = len(rest)
+			}
+
+			if lineStart {
+				text := strings.TrimPrefix(strings.TrimPrefix(rest[:n], "#"), "//")
+				comments[line] = strings.TrimSpace(text)
+			}
+		case strings.HasPrefix(rest, "/*"):
+			n = strings.Index(rest[2:], "*/") + 4
+			if lineStart && restOfLineBlank(rest[n:]) {
+				body := strings.TrimSuffix(strings.TrimPrefix(rest[:n], "/*"), "*/")
+				for j, text := range strings.Split(body, "\n") {
+					text = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(text), "*"))
+					if text != "" {
+						comments[line+j] = text
+					}
+				}
+			}
+		case strings.HasPrefix(rest, "|||"):
+			n = textBlockLength(rest)
+		case rest[0] == '"' || rest[0] == '\'':
+			n = quotedLength(rest[1:], rest[0], false) + 1
+		case rest[0] == '@' && len(rest) > 1 && (rest[1] == '"' || rest[1] == '\''):
+			n = quotedLength(rest[2:], rest[1], true) + 2
+		default:
+			n = 1
+		}
+
+		line += strings.Count(rest[:n], "\n")
+		lineStart = false
+		i += n
+	}
+
+	return comments
+}
+
+// restOfLineBlank returns true if s only has whitespace before its first
+// newline.
+func restOfLineBlank(s string) bool {
+	if n := strings.IndexByte(s, '\n'); n >= 0 {
+		s = s[:n]
+	}
+
+	return strings.TrimSpace(s) == ""
+}
+
+// textBlockLength returns the length of the text block s starts with, i.e.
+// up to the first line after the first which starts with `|||`.
+func textBlockLength(s string) int {
+	n := strings.IndexByte(s, '\n')
+	for n >= 0 {
+		line := s[n+1:]
+		if end := strings.IndexByte(line, '\n'); end >= 0 {
+			line = line[:end]
+		}
+
+		trimmed := strings.TrimLeft(line, " \t")
+		if strings.HasPrefix(trimmed, "|||") {
+			return n + 1 + len(line) - len(trimmed) + 3
+		}
+
+		next := strings.IndexByte(s[n+1:], '\n')
+		if next < 0 {
+			break
+		}
+		n += next + 1
+	}
+
+	return len(s)
+}
+
+// quotedLength returns the length of the string s starts with, including
+// the quote which ends it. Quotes are escaped with a backslash, or by
+// doubling them in verbatim strings.
+func quotedLength(s string, quote byte, verbatim bool) int {
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && !verbatim:
+			i++
+		case s[i] == quote && verbatim && i+1 < len(s) && s[i+1] == quote:
+			i++
+		case s[i] == quote:
+			return i + 1
+		}
+	}
+
+	return len(s)
+}
@@ -0,0 +1,328 @@
+package nodemaker
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	jsonnet "github.com/google/go-jsonnet"
+	"github.com/google/go-jsonnet/ast"
+	"github.com/google/go-jsonnet/parser"
+	"github.com/ksonnet/ksonnet-lib/ksonnet-gen/printer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func printNoder(t *testing.T, noder Noder) string {
+	var buf bytes.Buffer
+	require.NoError(t, printer.Fprint(&buf, noder.Node()))
+	return buf.String()
+}
+
+func printSource(t *testing.T, src string) string {
+	tokens, err := parser.Lex("source.jsonnet", src)
+	require.NoError(t, err)
+
+	node, err := parser.Parse(tokens)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, printer.Fprint(&buf, node))
+	return buf.String()
+}
+
+func TestParse(t *testing.T) {
+	cases := []struct {
+		name string
+		src  string
+	}{
+		{name: "object", src: `{ a: 1, 'b-c':: 'text', d::: [true, false, null], e+: { f: 1.5 } }`},
+		{name: "multiline object", src: "{\n  a: {\n    b: 'c',\n  },\n  d: { e: 'f' },\n}"},
+		{name: "methods", src: `{ new(name, labels={ app: name }):: self.withName(name) + self.withLabels(labels) }`},
+		{name: "object locals", src: `{ local a = 'a', local f(x) = x, b: a, c: f(a) }`},
+		{name: "field with the name of a local", src: `{ local a = 1, a: a }`},
+		{name: "computed field", src: `{ ['a' + 'b']: 1 }`},
+		{name: "computed fields with the same expression", src: `{ [k]: 1, [k]: 2 }`},
+		{name: "locals", src: "local k = import 'k.libsonnet';\nlocal deployment = k.apps.v1beta1.deployment;\n{ d: deployment.new('name') }"},
+		{name: "apply with named arguments", src: `f(1, b=2)`},
+		{name: "conditional", src: `if a == 'b' then error 'c' else 'd' in super`},
+		{name: "function", src: `function(a, b) a - b`},
+		{name: "quotes", src: `['single', "double", "it's", 'both \' "']`},
+		{name: "multiple binds", src: "local a = 1, f(x, y=2) = x + y;\n{ b: f(a) }"},
+		{name: "unary and parens", src: `{ a: -(1 + 2), b: !true, c: ~1 }`},
+		{name: "index and slice", src: `{ a: $.b[0], b: [1, 2, 3][1:], c: 'text'[::2], d: self.b[1:2] }`},
+		{name: "super", src: `{ a+: super.a { b: 1 } }`},
+		{name: "super index expression", src: `{ a: super['$ref'] }`},
+		{name: "comprehensions", src: `{ a: [x * 2 for x in [1, 2] if x > 1], b: { [k]: 1 for k in ['c', 'd'] } }`},
+		{name: "nested for", src: `[x + y for x in [1] for y in [2]]`},
+		{name: "asserts", src: `{ assert self.a > 0 : 'positive', assert true, a: assert 1 == 1; 1 }`},
+		{name: "repeated asserts", src: `{ assert true, assert true }`},
+		{name: "importstr", src: `{ a: importstr 'file.txt' }`},
+		{name: "multiline array", src: "{\n  a: [\n    1,\n    2,\n  ],\n  b: [],\n}"},
+		{name: "raw nodes", src: "{ a: |||\n  text\n|||, b: f(1) tailstrict }"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			noder, err := Parse("source.jsonnet", tc.src)
+			require.NoError(t, err)
+
+			assert.Equal(t, printSource(t, tc.src), printNoder(t, noder))
+		})
+	}
+}
+
+func TestParse_round_trip(t *testing.T) {
+	cases := []struct {
+		name string
+		src  string
+		// expected is the source if it is blank.
+		expected string
+	}{
+		{
+			name: "comments",
+			src: `{
+  // the name
+  //
+  // of the app
+  name: 'web',
+  nested: {
+    // a nested comment
+    a: 'text // not a comment',
+    // a comment after a multiline field
+    b: [
+      1,
+    ],
+  },
+  // a computed field
+  ['c' + 'd']: 1,
+  // an assert
+  assert self.name != '',
+}`,
+		},
+		{
+			name: "other comments",
+			src: `{
+  /* a block
+   * comment */
+  a: 1,
+  # a hash comment
+  b: 'c', // a trailing comment
+  /* not on a line of its own */ d: "# not a comment",
+}`,
+			expected: `{
+  // a block
+  // comment
+  a: 1,
+  // a hash comment
+  b: 'c',
+  d: '# not a comment',
+}`,
+		},
+		{
+			name: "text blocks",
+			src: `{
+  a: |||
+    line 1
+
+    line 2
+  |||,
+  // a comment after a text block
+  b: {
+    c: |||
+      // not a comment
+    |||,
+  },
+}`,
+		},
+		{name: "top level text block", src: "|||\n  text\n|||"},
+		{
+			name:     "reformatted",
+			src:      "{\n  a: self.b['c'],\n  b: { [d]: 1 for d in ['c'] },\n}",
+			expected: "{\n  a: self.b.c,\n  b: {\n    [d]: 1\n    for d in ['c']\n  },\n}",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			expected := tc.expected
+			if expected == "" {
+				expected = tc.src
+			}
+
+			noder, err := Parse("source.jsonnet", tc.src)
+			require.NoError(t, err)
+
+			got := printNoder(t, noder)
+			assert.Equal(t, expected, got)
+
+			vm := jsonnet.MakeVM()
+			want, err := vm.EvaluateSnippet("source.jsonnet", tc.src)
+			require.NoError(t, err)
+			value, err := vm.EvaluateSnippet("printed.jsonnet", got)
+			require.NoError(t, err)
+			assert.Equal(t, want, value)
+		})
+	}
+}
+
+// TestParse_libs parses the ksonnet libs. The printed libs are the same
+// jsonnet as the libs, formatting aside. Generated libs are printed as they
+// are.
+func TestParse_libs(t *testing.T) {
+	files, err := filepath.Glob("../../ksonnet.beta.*/*.libsonnet")
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+
+	for _, file := range files {
+		t.Run(file, func(t *testing.T) {
+			src, err := ioutil.ReadFile(file)
+			require.NoError(t, err)
+
+			noder, err := Parse(file, string(src))
+			require.NoError(t, err)
+
+			got := printNoder(t, noder)
+			if filepath.Base(filepath.Dir(file)) == "ksonnet.beta.4" {
+				require.Equal(t, string(src), got)
+			}
+
+			require.Equal(t, printUnformatted(t, string(src)), printUnformatted(t, got))
+		})
+	}
+}
+
+// printUnformatted prints jsonnet source without the line breaks the printer
+// keeps from the source.
+func printUnformatted(t *testing.T, src string) string {
+	tokens, err := parser.Lex("source.jsonnet", src)
+	require.NoError(t, err)
+
+	node, err := parser.Parse(tokens)
+	require.NoError(t, err)
+	clearLocations(reflect.ValueOf(node))
+
+	var buf bytes.Buffer
+	require.NoError(t, printer.Fprint(&buf, node))
+	return buf.String()
+}
+
+// clearLocations clears the locations of the nodes v refers to.
+func clearLocations(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return
+		}
+
+		if node, ok := v.Interface().(ast.Node); ok {
+			*node.Loc() = ast.LocationRange{}
+		}
+		clearLocations(v.Elem())
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			clearLocations(v.Index(i))
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Field(i).CanSet() {
+				clearLocations(v.Field(i))
+			}
+		}
+	}
+}
+
+func TestFromNode_comments(t *testing.T) {
+	o := NewObject()
+	require.NoError(t, o.Set(InheritedKey("a", KeyOptComment("a comment")), NewInt(1)))
+
+	noder, err := FromNode(o.Node())
+	require.NoError(t, err)
+	assert.Equal(t, "{\n  // a comment\n  a: 1,\n}", printNoder(t, noder))
+}
+
+func Test_scanComments(t *testing.T) {
+	src := "// a\nlocal b = 'c // d'; # e\n/* f\n g */\n@'h''// i' + \"\\\" // j\" +\n|||\n  # k\n|||\n# l"
+
+	expected := map[int]string{1: "a", 3: "f", 4: "g", 9: "l"}
+	assert.Equal(t, expected, scanComments(src))
+}
+
+func TestParse_invalid(t *testing.T) {
+	_, err := Parse("invalid.jsonnet", "{")
+	require.Error(t, err)
+
+	_, err = Parse("duplicate.jsonnet", "{ a: 1, a: 2 }")
+	require.Error(t, err)
+}
+
+func TestParse_types(t *testing.T) {
+	noder, err := Parse("source.jsonnet", "local a = 1, b = [a for a in [1]];\n{ [a]: $.b[a:] }")
+	require.NoError(t, err)
+
+	local, ok := noder.(*Local)
+	require.True(t, ok)
+	require.Len(t, local.Binds(), 2)
+	require.IsType(t, &ArrayComp{}, local.Binds()[1].Value)
+
+	o, ok := local.Body.(*Object)
+	require.True(t, ok)
+	require.Len(t, o.fields, 1)
+	require.IsType(t, &Slice{}, o.fields[0].value)
+
+	raw, err := Parse("raw.jsonnet", "|||\n  text\n|||")
+	require.NoError(t, err)
+	require.IsType(t, &Raw{}, raw)
+}
+
+func TestParse_edit(t *testing.T) {
+	src := `local k = import 'k.libsonnet';
+{
+  deployment: k.deployment.new('web', 1),
+  service: {
+    port: 80,
+  },
+}`
+
+	noder, err := Parse("source.jsonnet", src)
+	require.NoError(t, err)
+
+	local, ok := noder.(*Local)
+	require.True(t, ok)
+	require.Equal(t, "k", local.Name())
+
+	o, ok := local.Body.(*Object)
+	require.True(t, ok)
+
+	var names []string
+	for _, key := range o.Keys() {
+		names = append(names, key.Name())
+	}
+	require.Equal(t, []string{"deployment", "service"}, names)
+
+	apply, ok := o.Get("deployment").(*Apply)
+	require.True(t, ok)
+	require.Len(t, apply.PositionalArgs(), 2)
+	require.NoError(t, apply.SetPositionalArg(1, NewInt(3)))
+	require.Error(t, apply.SetPositionalArg(2, NewInt(3)))
+
+	service, ok := o.Get("service").(*Object)
+	require.True(t, ok)
+	require.NoError(t, service.Set(InheritedKey("type"), NewStringDouble("NodePort")))
+
+	require.NoError(t, o.Set(InheritedKey("metadata", KeyOptMixin(true)), OnelineObject()))
+
+	expected := `local k = import 'k.libsonnet';
+
+{
+  deployment: k.deployment.new('web', 3),
+  service: {
+    port: 80,
+    type: 'NodePort',
+  },
+  metadata+: {},
+}`
+	require.Equal(t, expected, printNoder(t, noder))
+}
@@ -0,0 +1,144 @@
+package parity
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	jsonnet "github.com/google/go-jsonnet"
+	"github.com/pkg/errors"
+)
+
+const libFile = "k8s.libsonnet"
+
+// Run compares a legacy lib and a current lib. Besides their API surfaces,
+// it compares the manifests created by the constructors which have compatible
+// parameters in both libs. Both libs are called with the legacy parameters.
+func Run(legacy, current []byte) (*Report, error) {
+	legacySurface, err := ParseSurface("legacy/"+libFile, legacy)
+	if err != nil {
+		return nil, err
+	}
+
+	currentSurface, err := ParseSurface("current/"+libFile, current)
+	if err != nil {
+		return nil, err
+	}
+
+	r := Compare(legacySurface, currentSurface)
+
+	var ctors []string
+	for _, path := range legacySurface.Paths() {
+		params, ok := currentSurface[path]
+		if ok && isConstructor(path) && compatibleParams(legacySurface[path], params) {
+			ctors = append(ctors, path)
+		}
+	}
+
+	legacyManifests := evaluateConstructors(legacy, legacySurface, ctors)
+	currentManifests := evaluateConstructors(current, legacySurface, ctors)
+
+	for _, path := range ctors {
+		if legacyManifests[path] != currentManifests[path] {
+			r.Manifests = append(r.Manifests, ManifestDiff{
+				Path:    path,
+				Legacy:  legacyManifests[path],
+				Current: currentManifests[path],
+			})
+		}
+	}
+
+	return r, nil
+}
+
+// isConstructor returns true if the function at path is a constructor.
+// (e.g. new or newNamed)
+func isConstructor(path string) bool {
+	parts := strings.Split(path, ".")
+	return strings.HasPrefix(parts[len(parts)-1], "new")
+}
+
+// constructorCall creates a call of a constructor. Each required parameter
+// is passed a string named after it.
+func constructorCall(path string, params []string) string {
+	var args []string
+	for _, param := range params {
+		if strings.Contains(param, "=") {
+			continue
+		}
+
+		args = append(args, fmt.Sprintf("'%s-value'", param))
+	}
+
+	return fmt.Sprintf("k8s.%s(%s)", path, strings.Join(args, ", "))
+}
+
+// evaluateConstructors evaluates constructors in a lib and returns their
+// compact JSON manifests. The constructors are evaluated in batches, and a
+// batch which fails is split until the failing constructors are found, so
+// their errors are returned as their manifests.
+func evaluateConstructors(lib []byte, s Surface, ctors []string) map[string]string {
+	vm := jsonnet.MakeVM()
+	vm.Importer(&jsonnet.MemoryImporter{
+		Data: map[string]string{libFile: string(lib)},
+	})
+
+	out := make(map[string]string)
+	evaluateBatch(vm, s, ctors, out)
+
+	return out
+}
+
+func evaluateBatch(vm *jsonnet.VM, s Surface, ctors []string, out map[string]string) {
+	if len(ctors) == 0 {
+		return
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "local k8s = import '%s';\n{\n", libFile)
+	for _, path := range ctors {
+		fmt.Fprintf(&buf, "  '%s': %s,\n", path, constructorCall(path, s[path]))
+	}
+	buf.WriteString("}\n")
+
+	manifests, err := evaluate(vm, buf.String())
+	if err == nil {
+		for path, manifest := range manifests {
+			out[path] = manifest
+		}
+		return
+	}
+
+	if len(ctors) == 1 {
+		out[ctors[0]] = "error: " + strings.SplitN(err.Error(), "\n", 2)[0]
+		return
+	}
+
+	evaluateBatch(vm, s, ctors[:len(ctors)/2], out)
+	evaluateBatch(vm, s, ctors[len(ctors)/2:], out)
+}
+
+func evaluate(vm *jsonnet.VM, snippet string) (map[string]string, error) {
+	src, err := vm.EvaluateSnippet("constructors.jsonnet", snippet)
+	if err != nil {
+		return nil, err
+	}
+
+	var manifests map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(src), &manifests); err != nil {
+		return nil, errors.Wrap(err, "unmarshal manifests")
+	}
+
+	out := make(map[string]string)
+	for path, manifest := range manifests {
+		var buf bytes.Buffer
+		if err := json.Compact(&buf, manifest); err != nil {
+			return nil, errors.Wrapf(err, "compact manifest of %s", path)
+		}
+
+		out[path] = buf.String()
+	}
+
+	return out, nil
+}
@@ -0,0 +1,174 @@
+package parity
+
+import (
+	"io/ioutil"
+	"regexp"
+	"testing"
+
+	"github.com/ksonnet/ksonnet-lib/ksonnet-gen/ksonnet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSurface(t *testing.T) {
+	src := `
+local hidden = {};
+{
+  local apiVersion = { apiVersion: 'v1' },
+  core:: {
+    v1:: {
+      pod:: {
+        new(name, labels={ app: name }):: apiVersion + self.mixin.metadata.withLabels(labels),
+        mixin:: {
+          spec:: {
+            withHostname(hostname):: self + { spec+: { hostname: hostname } },
+          },
+        },
+        podType:: hidden.pod,
+      },
+    },
+  },
+  'quoted':: { f():: {} },
+}`
+
+	s, err := ParseSurface("lib.libsonnet", []byte(src))
+	require.NoError(t, err)
+
+	expected := Surface{
+		"core.v1.pod.new":                     {"name", "labels={ app: name }"},
+		"core.v1.pod.mixin.spec.withHostname": {"hostname"},
+		"quoted.f":                            {},
+	}
+	require.Equal(t, expected, s)
+	require.Equal(t, []string{"core.v1.pod.mixin.spec.withHostname", "core.v1.pod.new", "quoted.f"}, s.Paths())
+
+	_, err = ParseSurface("invalid.libsonnet", []byte("{"))
+	require.Error(t, err)
+}
+
+func Test_compatibleParams(t *testing.T) {
+	cases := []struct {
+		name     string
+		legacy   []string
+		current  []string
+		expected bool
+	}{
+		{name: "same", legacy: []string{"name", "type='Opaque'"}, current: []string{"name", "type='Opaque'"}, expected: true},
+		{name: "required to optional", legacy: []string{"name"}, current: []string{"name=''"}, expected: true},
+		{name: "added optional", legacy: []string{"name"}, current: []string{"name", "data={}"}, expected: true},
+		{name: "added required", legacy: []string{"name"}, current: []string{"name", "data"}},
+		{name: "renamed", legacy: []string{"name"}, current: []string{"id"}},
+		{name: "removed", legacy: []string{"name", "data"}, current: []string{"name"}},
+		{name: "changed default", legacy: []string{"labels={ app: name }"}, current: []string{"labels={ app: 'name' }"}},
+		{name: "optional to required", legacy: []string{"type='Opaque'"}, current: []string{"type"}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, compatibleParams(tc.legacy, tc.current))
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	legacy := `{
+  local kind = { kind: 'Pod' },
+  pod:: {
+    new(name):: kind + { metadata: { name: name } },
+    newNamed(name):: kind + { metadata: { name: name } },
+    withHostname(hostname):: self + { hostname: hostname },
+    withNodeName(nodeName):: self + { nodeName: nodeName },
+  },
+}`
+
+	current := `{
+  local kind = { kind: 'Pod' },
+  pod:: {
+    new(name=''):: kind + { metadata: { name: name } },
+    newNamed(name=''):: kind + { metadata: { name: name, namespace: 'default' } },
+    withHostname(hostname, f):: self + { hostname: f(hostname) },
+    withSubdomain(subdomain):: self + { subdomain: subdomain },
+  },
+}`
+
+	r, err := Run([]byte(legacy), []byte(current))
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"pod.withNodeName"}, r.Missing)
+	require.Equal(t, []string{"pod.withSubdomain"}, r.Added)
+	require.Equal(t, []SignatureDiff{
+		{Path: "pod.withHostname", Legacy: []string{"hostname"}, Current: []string{"hostname", "f"}},
+	}, r.Signatures)
+	require.Equal(t, []ManifestDiff{
+		{
+			Path:    "pod.newNamed",
+			Legacy:  `{"kind":"Pod","metadata":{"name":"name-value"}}`,
+			Current: `{"kind":"Pod","metadata":{"name":"name-value","namespace":"default"}}`,
+		},
+	}, r.Manifests)
+
+	require.False(t, r.IsCompatible())
+	require.Contains(t, r.String(), "missing pod.withNodeName")
+	require.Contains(t, r.String(), "signature of pod.withHostname: legacy (hostname), current (hostname, f)")
+}
+
+func TestRun_constructor_error(t *testing.T) {
+	legacy := `{ pod:: { new(name):: { name: name } } }`
+	current := `{ pod:: { new(name):: error 'not implemented' } }`
+
+	r, err := Run([]byte(legacy), []byte(current))
+	require.NoError(t, err)
+
+	require.Len(t, r.Manifests, 1)
+	require.Contains(t, r.Manifests[0].Current, "error: ")
+	require.Contains(t, r.Manifests[0].Current, "not implemented")
+}
+
+// knownDifferences are the accepted differences between the ksonnet.beta.3
+// lib, which was generated by the legacy emitter, and the current lib.
+var knownDifferences = []*regexp.Regexp{
+	// read-only properties aren't generated.
+	regexp.MustCompile(`\.withDeletionGracePeriodSeconds$`),
+	// meta.v1.Status is a blocked reference, so initializer results aren't generated.
+	regexp.MustCompile(`\.initializers\.result\.`),
+	// date-times are set with a checked setter rather than a mixin.
+	regexp.MustCompile(`^core\.v1\.event\.mixin\.(first|last)Timestamp\.mixinInstance$`),
+	// the legacy emitter set the api version of meta.v1 kinds to meta/v1.
+	regexp.MustCompile(`^meta\.v1\.[a-zA-Z]+\.new$`),
+}
+
+func isKnownDifference(path string) bool {
+	for _, re := range knownDifferences {
+		if re.MatchString(path) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func TestRun_legacy_lib(t *testing.T) {
+	legacy, err := ioutil.ReadFile("../../ksonnet.beta.3/k8s.libsonnet")
+	require.NoError(t, err)
+
+	lib, err := ksonnet.GenerateLib("../ksonnet/testdata/swagger-1.8.json",
+		ksonnet.CatalogOptDeprecatedIdentifiers(true))
+	require.NoError(t, err)
+
+	r, err := Run(legacy, lib.K8s)
+	require.NoError(t, err)
+
+	unknown := &Report{Signatures: r.Signatures}
+	for _, path := range r.Missing {
+		if !isKnownDifference(path) {
+			unknown.Missing = append(unknown.Missing, path)
+		}
+	}
+	for _, d := range r.Manifests {
+		if !isKnownDifference(d.Path) {
+			unknown.Manifests = append(unknown.Manifests, d)
+		}
+	}
+
+	require.True(t, unknown.IsCompatible(), "current lib isn't compatible with the legacy lib:\n%s", unknown)
+}
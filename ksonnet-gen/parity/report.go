@@ -0,0 +1,123 @@
+package parity
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// SignatureDiff is a function whose parameters in the current lib aren't
+// compatible with its parameters in the legacy lib.
+type SignatureDiff struct {
+	Path    string
+	Legacy  []string
+	Current []string
+}
+
+// ManifestDiff is a constructor which creates different manifests in two
+// libs. A constructor which fails to evaluate has the error as its manifest.
+type ManifestDiff struct {
+	Path    string
+	Legacy  string
+	Current string
+}
+
+// Report is the difference between a legacy lib and a current lib.
+type Report struct {
+	// Missing are the functions the current lib doesn't have.
+	Missing []string
+	// Added are the functions only the current lib has.
+	Added []string
+	// Signatures are the functions whose parameters aren't compatible.
+	Signatures []SignatureDiff
+	// Manifests are the constructors whose manifests differ.
+	Manifests []ManifestDiff
+}
+
+// Compare compares the API surfaces of a legacy lib and a current lib.
+func Compare(legacy, current Surface) *Report {
+	r := &Report{}
+
+	for _, path := range legacy.Paths() {
+		params, ok := current[path]
+		if !ok {
+			r.Missing = append(r.Missing, path)
+			continue
+		}
+
+		if !compatibleParams(legacy[path], params) {
+			r.Signatures = append(r.Signatures, SignatureDiff{
+				Path:    path,
+				Legacy:  legacy[path],
+				Current: params,
+			})
+		}
+	}
+
+	for _, path := range current.Paths() {
+		if _, ok := legacy[path]; !ok {
+			r.Added = append(r.Added, path)
+		}
+	}
+
+	return r
+}
+
+// compatibleParams returns true if every call of a function with the legacy
+// parameters is a valid call with the current parameters. Required parameters
+// can become optional, and optional parameters can be added, but the legacy
+// parameters have to keep their order and defaults.
+func compatibleParams(legacy, current []string) bool {
+	if len(current) < len(legacy) {
+		return false
+	}
+
+	for i, param := range current {
+		if i >= len(legacy) {
+			if !strings.Contains(param, "=") {
+				return false
+			}
+			continue
+		}
+
+		if strings.Contains(legacy[i], "=") {
+			if param != legacy[i] {
+				return false
+			}
+			continue
+		}
+
+		if strings.SplitN(param, "=", 2)[0] != legacy[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// IsCompatible returns true if the current lib can replace the legacy lib,
+// i.e. it has all of its functions with compatible parameters and its
+// constructors create the same manifests.
+func (r *Report) IsCompatible() bool {
+	return len(r.Missing) == 0 && len(r.Signatures) == 0 && len(r.Manifests) == 0
+}
+
+// String formats the differences in the report which break compatibility.
+func (r *Report) String() string {
+	var buf bytes.Buffer
+
+	for _, path := range r.Missing {
+		fmt.Fprintf(&buf, "missing %s\n", path)
+	}
+
+	for _, d := range r.Signatures {
+		fmt.Fprintf(&buf, "signature of %s: legacy (%s), current (%s)\n",
+			d.Path, strings.Join(d.Legacy, ", "), strings.Join(d.Current, ", "))
+	}
+
+	for _, d := range r.Manifests {
+		fmt.Fprintf(&buf, "manifest of %s:\n  legacy: %s\n  current: %s\n", d.Path, d.Legacy, d.Current)
+	}
+
+	return buf.String()
+}
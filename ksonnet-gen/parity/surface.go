@@ -0,0 +1,120 @@
+// Package parity compares ksonnet libs generated by different pipelines. It
+// reports the differences between their API surfaces, (i.e. the functions a
+// lib defines and their parameters) and between the manifests their
+// constructors create.
+package parity
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+
+	"github.com/google/go-jsonnet/ast"
+	"github.com/google/go-jsonnet/parser"
+	"github.com/ksonnet/ksonnet-lib/ksonnet-gen/printer"
+	"github.com/pkg/errors"
+)
+
+// Surface is the API surface of a ksonnet lib. It maps the path of each
+// function in the lib (e.g. core.v1.pod.new) to its parameters. Optional
+// parameters are formatted as `name=default`.
+type Surface map[string][]string
+
+// ParseSurface parses the source of a ksonnet lib and returns its API surface.
+func ParseSurface(filename string, src []byte) (Surface, error) {
+	tokens, err := parser.Lex(filename, string(src))
+	if err != nil {
+		return nil, errors.Wrapf(err, "lex %s", filename)
+	}
+
+	node, err := parser.Parse(tokens)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parse %s", filename)
+	}
+
+	s := Surface{}
+	if err := s.add(nil, node); err != nil {
+		return nil, errors.Wrapf(err, "read API surface of %s", filename)
+	}
+
+	return s, nil
+}
+
+// Paths returns the sorted paths of the functions in the surface.
+func (s Surface) Paths() []string {
+	var paths []string
+	for path := range s {
+		paths = append(paths, path)
+	}
+
+	sort.Strings(paths)
+	return paths
+}
+
+// add adds the functions of an object to the surface. Locals surrounding the
+// object are skipped.
+func (s Surface) add(path []string, node ast.Node) error {
+	switch n := node.(type) {
+	case *ast.Local:
+		return s.add(path, n.Body)
+	case *ast.Object:
+		for _, field := range n.Fields {
+			name, ok := fieldName(field)
+			if !ok {
+				continue
+			}
+
+			fieldPath := append(append([]string{}, path...), name)
+
+			if field.Method != nil {
+				params, err := formatParams(field.Method.Parameters)
+				if err != nil {
+					return errors.Wrapf(err, "format parameters of %s", strings.Join(fieldPath, "."))
+				}
+
+				s[strings.Join(fieldPath, ".")] = params
+				continue
+			}
+
+			if err := s.add(fieldPath, field.Expr2); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// fieldName returns the name of an object field. Locals and computed fields
+// don't have names.
+func fieldName(field ast.ObjectField) (string, bool) {
+	switch field.Kind {
+	case ast.ObjectFieldID:
+		return string(*field.Id), true
+	case ast.ObjectFieldStr:
+		if s, ok := field.Expr1.(*ast.LiteralString); ok {
+			return s.Value, true
+		}
+	}
+
+	return "", false
+}
+
+func formatParams(params ast.Parameters) ([]string, error) {
+	out := []string{}
+
+	for _, id := range params.Required {
+		out = append(out, string(id))
+	}
+
+	for _, param := range params.Optional {
+		var buf bytes.Buffer
+		if err := printer.Fprint(&buf, param.DefaultArg); err != nil {
+			return nil, err
+		}
+
+		out = append(out, string(param.Name)+"="+buf.String())
+	}
+
+	return out, nil
+}
@@ -98,20 +98,19 @@ func (p *printer) indent() {
 		return
 	}
 
-	r := p.indentLevel
-	var ch byte
-	if p.cfg.IndentMode == IndentModeTab {
-		ch = tab
-	} else {
-		ch = space
-		r = r * p.cfg.IndentSize
-	}
-
 	last := p.output[len(p.output)-1]
 	if last == newline {
-		pre := bytes.Repeat([]byte{ch}, r)
-		p.output = append(p.output, pre...)
+		p.output = append(p.output, p.indentation(p.indentLevel)...)
+	}
+}
+
+// indentation returns the indentation of a level.
+func (p *printer) indentation(level int) []byte {
+	if p.cfg.IndentMode == IndentModeTab {
+		return bytes.Repeat([]byte{tab}, level)
 	}
+
+	return bytes.Repeat([]byte{space}, level*p.cfg.IndentSize)
 }
 
 func (p *printer) writeByte(ch byte, n int) {
@@ -500,10 +499,18 @@ func (p *printer) print(n interface{}) {
 			quoted := stringQuote(val, useSingle)
 			p.writeString(quoted)
 		case ast.StringBlock:
+			// The lines of the block are indented one level more than the
+			// block, and the value of a block always ends with a newline.
 			p.writeString("|||")
+			padding := string(p.indentation(p.indentLevel + 1))
+			for _, line := range strings.Split(strings.TrimSuffix(t.Value, "\n"), "\n") {
+				p.writeStringNoIndent("\n")
+				if line != "" {
+					p.writeStringNoIndent(padding + line)
+				}
+			}
 			p.writeByte(newline, 1)
-			p.writeString(t.Value)
-			p.writeStringNoIndent("\n|||")
+			p.writeString("|||")
 		case ast.VerbatimStringDouble:
 			p.writeString("@")
 			p.writeByte(doubleQuote, 1)
@@ -548,8 +555,8 @@ func (p *printer) print(n interface{}) {
 	case *ast.LiteralNull:
 		p.writeString("null")
 	case *ast.SuperIndex:
-		p.writeString("super.")
-		p.writeString(string(*t.Id))
+		p.writeString("super")
+		p.indexID(&ast.Index{Index: t.Index, Id: t.Id})
 	}
 }
 
@@ -934,7 +941,12 @@ func (p *printer) handleObjectField(n interface{}) {
 	case ast.ObjectFieldExpr:
 		p.writeString("[")
 		p.fieldID(ofKind, ofExpr1, ofID)
-		p.writeString("]: ")
+		p.writeString("]")
+		if ofSugar {
+			p.writeByte(syntaxSugar, 1)
+		}
+		p.writeString(fieldType)
+		p.writeByte(space, 1)
 		p.print(ofExpr2)
 		if forSpec.VarName != "" {
 			p.writeByte(newline, 1)
@@ -64,6 +64,8 @@ func TestFprintf(t *testing.T) {
 		{name: "function"},
 		{name: "super_index"},
 		{name: "block_string"},
+		{name: "object_with_block_string"},
+		{name: "object_field_expr_visibility"},
 		{name: "dollar"},
 		{name: "nil_node"},
 		{name: "trimmed_whitespace_in_tests"},
@@ -1012,6 +1014,36 @@ var (
 		"super_index": &ast.SuperIndex{
 			Id: newIdentifier("metadata"),
 		},
+		"object_field_expr_visibility": &ast.Object{
+			Fields: ast.ObjectFields{
+				{
+					Kind:  ast.ObjectFieldExpr,
+					Hide:  ast.ObjectFieldVisible,
+					Expr1: &ast.Var{Id: *newIdentifier("a")},
+					Expr2: &ast.LiteralNumber{OriginalString: "1"},
+				},
+				{
+					Kind:       ast.ObjectFieldExpr,
+					Hide:       ast.ObjectFieldInherit,
+					SuperSugar: true,
+					Expr1:      &ast.Var{Id: *newIdentifier("b")},
+					Expr2:      &ast.Object{},
+				},
+			},
+		},
+		"object_with_block_string": &ast.Object{
+			Fields: ast.ObjectFields{
+				{
+					Kind: ast.ObjectFieldID,
+					Hide: ast.ObjectFieldInherit,
+					Id:   newIdentifier("foo"),
+					Expr2: &ast.LiteralString{
+						Kind:  ast.StringBlock,
+						Value: "line 1\n\nline 2\n",
+					},
+				},
+			},
+		},
 		"block_string": &ast.LiteralString{
 			Kind:  ast.StringBlock,
 			Value: "text",
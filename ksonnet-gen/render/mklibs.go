@@ -0,0 +1,108 @@
+//go:build ignore
+// +build ignore
+
+// mklibs embeds the generated libs in the root of the repository in the
+// render package.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// reKubernetesVersion matches the Kubernetes version in the header of a lib,
+// both in the comment of earlier libs and in the __ksonnet field.
+var reKubernetesVersion = regexp.MustCompile(`(?:Kubernetes version: v|kubernetesVersion: ')(\d+\.\d+\.\d+)`)
+
+func main() {
+	dirs, err := filepath.Glob(filepath.Join("..", "..", "ksonnet.beta.*"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	sort.Strings(dirs)
+
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by mklibs.go. DO NOT EDIT.\n\n")
+	buf.WriteString("package render\n\n")
+	buf.WriteString("// embeddedLibs are the generated libs in the root of the repository.\n")
+	buf.WriteString("var embeddedLibs = []embeddedLib{\n")
+
+	for _, dir := range dirs {
+		paths, err := filepath.Glob(filepath.Join(dir, "*.libsonnet"))
+		if err != nil {
+			log.Fatal(err)
+		}
+		sort.Strings(paths)
+
+		kubernetesVersion, err := readKubernetesVersion(dir)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		fmt.Fprintf(&buf, "{\nname: %q,\nkubernetesVersion: %q,\nfiles: map[string]string{\n",
+			filepath.Base(dir), kubernetesVersion)
+
+		for _, path := range paths {
+			b, err := ioutil.ReadFile(path)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			fmt.Fprintf(&buf, "%q: %s,\n", filepath.Base(path), rawString(string(b)))
+		}
+
+		buf.WriteString("},\n},\n")
+	}
+
+	buf.WriteString("}\n")
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := ioutil.WriteFile("libs_generated.go", src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// readKubernetesVersion reads the Kubernetes version from the header of the
+// k8s.libsonnet of a lib. It is empty if the lib doesn't record one.
+func readKubernetesVersion(dir string) (string, error) {
+	b, err := ioutil.ReadFile(filepath.Join(dir, "k8s.libsonnet"))
+	if os.IsNotExist(err) {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+
+	if len(b) > 1024 {
+		b = b[:1024]
+	}
+
+	match := reKubernetesVersion.FindSubmatch(b)
+	if match == nil {
+		return "", nil
+	}
+
+	return string(match[1]), nil
+}
+
+// rawString formats content as raw string literals. Backquotes, which can't
+// be in a raw string, are concatenated as interpreted strings.
+func rawString(content string) string {
+	parts := strings.Split(content, "`")
+	for i := range parts {
+		parts[i] = "`" + parts[i] + "`"
+	}
+
+	return strings.Join(parts, " + \"`\" + ")
+}
@@ -0,0 +1,240 @@
+// Package render evaluates Jsonnet with the generated ksonnet libs, which are
+// embedded in the package, and flattens the result into Kubernetes objects.
+package render
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/blang/semver"
+	jsonnet "github.com/google/go-jsonnet"
+	"github.com/pkg/errors"
+)
+
+//go:generate go run mklibs.go
+
+// embeddedLib is a generated lib in the root of the repository.
+type embeddedLib struct {
+	// name is the directory of the lib. (e.g. ksonnet.beta.3)
+	name string
+	// kubernetesVersion is the Kubernetes version the lib was generated
+	// from. It is empty if the lib doesn't record it.
+	kubernetesVersion string
+	// files are the contents of the lib's files by name.
+	files map[string]string
+}
+
+// Versions returns the names of the embedded libs, sorted.
+func Versions() []string {
+	var names []string
+	for _, lib := range embeddedLibs {
+		names = append(names, lib.name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// lookupLib returns the embedded lib for a version. The version is the name
+// of a lib (e.g. ksonnet.beta.3), or a Kubernetes version (e.g. v1.8.0 or
+// 1.8), which matches the lib generated from the same minor version.
+func lookupLib(version string) (*embeddedLib, error) {
+	for i := range embeddedLibs {
+		if embeddedLibs[i].name == version {
+			return &embeddedLibs[i], nil
+		}
+	}
+
+	v, err := semver.ParseTolerant(version)
+	if err != nil {
+		return nil, errors.Errorf("%s is not a lib or a Kubernetes version", version)
+	}
+
+	for i := range embeddedLibs {
+		lv, err := semver.Parse(embeddedLibs[i].kubernetesVersion)
+		if err != nil {
+			continue
+		}
+
+		if lv.Major == v.Major && lv.Minor == v.Minor {
+			return &embeddedLibs[i], nil
+		}
+	}
+
+	return nil, errors.Errorf("there is no lib for Kubernetes version %s", version)
+}
+
+// Importer is a Jsonnet importer for the files of an embedded lib. They are
+// imported by their name (e.g. `import "k.libsonnet"`) or by their path in
+// the repository (e.g. `import "ksonnet.beta.3/k.libsonnet"`), and files of
+// the lib import each other relatively. Other imports are passed to the
+// Fallback importer. Files which aren't in the lib import a file of the
+// Fallback importer before a file of the lib with the same name, so a file
+// next to them (e.g. /app/k.libsonnet) isn't hidden by the lib.
+type Importer struct {
+	// Fallback imports the files which aren't in the lib. If it is nil,
+	// they can't be imported.
+	Fallback jsonnet.Importer
+
+	lib *embeddedLib
+}
+
+var _ jsonnet.Importer = (*Importer)(nil)
+
+// NewImporter creates an Importer for the lib of a version. See Render for
+// the versions.
+func NewImporter(version string) (*Importer, error) {
+	lib, err := lookupLib(version)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Importer{lib: lib}, nil
+}
+
+// Import imports a file of the lib, or a file of the Fallback importer.
+func (i *Importer) Import(codeDir, importedPath string) (*jsonnet.ImportedData, error) {
+	if data, ok := i.libFile(path.Join(codeDir, importedPath)); ok {
+		return data, nil
+	}
+
+	if i.inLib(codeDir) {
+		if data, ok := i.libFile(path.Clean(importedPath)); ok {
+			return data, nil
+		}
+
+		return i.fallback(codeDir, importedPath)
+	}
+
+	data, err := i.fallback(codeDir, importedPath)
+	if err != nil {
+		if libData, ok := i.libFile(path.Clean(importedPath)); ok {
+			return libData, nil
+		}
+	}
+
+	return data, err
+}
+
+// libFile imports a file of the lib by its path, relative to the lib or to
+// the repository.
+func (i *Importer) libFile(p string) (*jsonnet.ImportedData, bool) {
+	name := strings.TrimPrefix(p, i.lib.name+"/")
+	content, ok := i.lib.files[name]
+	if !ok {
+		return nil, false
+	}
+
+	return &jsonnet.ImportedData{
+		Content:   content,
+		FoundHere: path.Join(i.lib.name, name),
+	}, true
+}
+
+// inLib returns true if code in a directory imports the files of the lib by
+// their name first, i.e. it is a snippet or a file of the lib.
+func (i *Importer) inLib(codeDir string) bool {
+	dir := path.Clean(codeDir)
+	return dir == "." || dir == i.lib.name || strings.HasPrefix(dir, i.lib.name+"/")
+}
+
+func (i *Importer) fallback(codeDir, importedPath string) (*jsonnet.ImportedData, error) {
+	if i.Fallback == nil {
+		return nil, errors.Errorf("%s is not in %s", importedPath, i.lib.name)
+	}
+
+	return i.Fallback.Import(codeDir, importedPath)
+}
+
+// Render evaluates a Jsonnet snippet which can import the lib of a version,
+// and flattens the result into Kubernetes objects with Flatten. The version
+// is the name of an embedded lib (e.g. ksonnet.beta.3), or a Kubernetes
+// version (e.g. v1.8.0 or 1.8), which selects the lib generated from the
+// same minor version.
+func Render(snippet, version string) ([]map[string]interface{}, error) {
+	importer, err := NewImporter(version)
+	if err != nil {
+		return nil, err
+	}
+
+	vm := jsonnet.MakeVM()
+	vm.Importer(importer)
+
+	out, err := vm.EvaluateSnippet("snippet.jsonnet", snippet)
+	if err != nil {
+		return nil, errors.Wrap(err, "evaluate snippet")
+	}
+
+	var v interface{}
+	if err := json.Unmarshal([]byte(out), &v); err != nil {
+		return nil, errors.Wrap(err, "unmarshal snippet output")
+	}
+
+	return Flatten(v)
+}
+
+// Flatten flattens a value into Kubernetes objects, which are objects with
+// an apiVersion and a kind. Lists (objects whose kind ends with List and
+// which have items) and arrays are flattened into their items, and other
+// objects into their values, sorted by key. Nulls are skipped.
+func Flatten(v interface{}) ([]map[string]interface{}, error) {
+	var objects []map[string]interface{}
+	if err := flatten(v, "", &objects); err != nil {
+		return nil, err
+	}
+
+	return objects, nil
+}
+
+func flatten(v interface{}, at string, objects *[]map[string]interface{}) error {
+	switch t := v.(type) {
+	case nil:
+		return nil
+	case []interface{}:
+		for i, item := range t {
+			if err := flatten(item, fmt.Sprintf("%s[%d]", at, i), objects); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	case map[string]interface{}:
+		if !isObject(t) {
+			var keys []string
+			for key := range t {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+
+			for _, key := range keys {
+				if err := flatten(t[key], at+"."+key, objects); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		}
+
+		if items, ok := t["items"].([]interface{}); ok && strings.HasSuffix(t["kind"].(string), "List") {
+			return flatten(items, at+".items", objects)
+		}
+
+		*objects = append(*objects, t)
+		return nil
+	default:
+		if at == "" {
+			at = "output"
+		}
+		return errors.Errorf("%s is a %T, not a Kubernetes object", strings.TrimPrefix(at, "."), v)
+	}
+}
+
+// isObject returns true if a value is a Kubernetes object.
+func isObject(m map[string]interface{}) bool {
+	_, hasAPIVersion := m["apiVersion"].(string)
+	_, hasKind := m["kind"].(string)
+	return hasAPIVersion && hasKind
+}
@@ -0,0 +1,207 @@
+package render
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	jsonnet "github.com/google/go-jsonnet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVersions(t *testing.T) {
+	expected := []string{"ksonnet.beta.1", "ksonnet.beta.2", "ksonnet.beta.3", "ksonnet.beta.4"}
+	require.Equal(t, expected, Versions())
+}
+
+func Test_lookupLib(t *testing.T) {
+	cases := []struct {
+		name     string
+		version  string
+		expected string
+		isErr    bool
+	}{
+		{name: "lib name", version: "ksonnet.beta.1", expected: "ksonnet.beta.1"},
+		{name: "kubernetes version", version: "v1.8.0", expected: "ksonnet.beta.3"},
+		{name: "patch version", version: "1.7.4", expected: "ksonnet.beta.2"},
+		{name: "minor version", version: "1.14", expected: "ksonnet.beta.4"},
+		{name: "unknown version", version: "1.10.0", isErr: true},
+		{name: "invalid version", version: "ksonnet.beta.9", isErr: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			lib, err := lookupLib(tc.version)
+			if tc.isErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, lib.name)
+		})
+	}
+}
+
+func TestImporter(t *testing.T) {
+	importer, err := NewImporter("ksonnet.beta.3")
+	require.NoError(t, err)
+
+	data, err := importer.Import("", "k.libsonnet")
+	require.NoError(t, err)
+	assert.Equal(t, "ksonnet.beta.3/k.libsonnet", data.FoundHere)
+
+	data, err = importer.Import("ksonnet.beta.3", "k8s.libsonnet")
+	require.NoError(t, err)
+	assert.Equal(t, "ksonnet.beta.3/k8s.libsonnet", data.FoundHere)
+
+	data, err = importer.Import("/app", "ksonnet.beta.3/k8s.libsonnet")
+	require.NoError(t, err)
+	assert.Equal(t, "ksonnet.beta.3/k8s.libsonnet", data.FoundHere)
+
+	_, err = importer.Import("", "app.libsonnet")
+	require.Error(t, err)
+
+	importer.Fallback = &jsonnet.MemoryImporter{
+		Data: map[string]string{"app.libsonnet": "{}"},
+	}
+
+	data, err = importer.Import("", "app.libsonnet")
+	require.NoError(t, err)
+	assert.Equal(t, "{}", data.Content)
+}
+
+func TestImporter_user_files(t *testing.T) {
+	importer, err := NewImporter("ksonnet.beta.3")
+	require.NoError(t, err)
+
+	importer.Fallback = &jsonnet.FileImporter{}
+
+	dir, err := ioutil.TempDir("", "render")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	for _, name := range []string{"k.libsonnet", "apps.v1beta1.libsonnet"} {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), []byte("{ user: true }"), 0644))
+	}
+
+	// files next to the file which imports them are imported before the
+	// files of the lib with the same name.
+	for _, name := range []string{"k.libsonnet", "apps.v1beta1.libsonnet"} {
+		data, err := importer.Import(dir+"/", name)
+		require.NoError(t, err)
+		assert.Equal(t, filepath.Join(dir, name), data.FoundHere)
+	}
+
+	// the lib's files are imported if there isn't a user file.
+	data, err := importer.Import(dir+"/", "k8s.libsonnet")
+	require.NoError(t, err)
+	assert.Equal(t, "ksonnet.beta.3/k8s.libsonnet", data.FoundHere)
+
+	// snippets and files of the lib import the lib's files.
+	for _, codeDir := range []string{"", "ksonnet.beta.3/"} {
+		data, err := importer.Import(codeDir, "k.libsonnet")
+		require.NoError(t, err)
+		assert.Equal(t, "ksonnet.beta.3/k.libsonnet", data.FoundHere)
+	}
+
+	_, err = importer.Import(dir+"/", "missing.libsonnet")
+	require.Error(t, err)
+}
+
+func TestRender(t *testing.T) {
+	snippet := `
+local k = import "k.libsonnet";
+local deployment = k.apps.v1beta2.deployment;
+local service = k.core.v1.service;
+
+{
+  web: {
+    deployment: deployment.new("web", 2, [], {app: "web"}),
+    service: service.new("web", {app: "web"}, []),
+  },
+  config: k.core.v1.configMapList.new([
+    k.core.v1.configMap.new("config", {}),
+  ]),
+}
+`
+
+	objects, err := Render(snippet, "1.8")
+	require.NoError(t, err)
+	require.Len(t, objects, 3)
+
+	assert.Equal(t, "ConfigMap", objects[0]["kind"])
+	assert.Equal(t, "Deployment", objects[1]["kind"])
+	assert.Equal(t, "apps/v1beta2", objects[1]["apiVersion"])
+	assert.Equal(t, "Service", objects[2]["kind"])
+
+	_, err = Render(snippet, "1.10")
+	require.Error(t, err)
+
+	_, err = Render(`import "app.libsonnet"`, "1.8")
+	require.Error(t, err)
+}
+
+func TestFlatten(t *testing.T) {
+	pod := map[string]interface{}{"apiVersion": "v1", "kind": "Pod"}
+	service := map[string]interface{}{"apiVersion": "v1", "kind": "Service"}
+
+	cases := []struct {
+		name     string
+		in       interface{}
+		expected []map[string]interface{}
+		isErr    bool
+	}{
+		{
+			name:     "object",
+			in:       pod,
+			expected: []map[string]interface{}{pod},
+		},
+		{
+			name:     "array",
+			in:       []interface{}{service, pod},
+			expected: []map[string]interface{}{service, pod},
+		},
+		{
+			name: "list",
+			in: map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "List",
+				"items":      []interface{}{pod, service},
+			},
+			expected: []map[string]interface{}{pod, service},
+		},
+		{
+			name: "object of objects",
+			in: map[string]interface{}{
+				"b": pod,
+				"a": map[string]interface{}{"service": service, "none": nil},
+			},
+			expected: []map[string]interface{}{service, pod},
+		},
+		{
+			name: "empty object",
+			in:   map[string]interface{}{},
+		},
+		{
+			name:  "string",
+			in:    map[string]interface{}{"a": []interface{}{pod, "pod"}},
+			isErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Flatten(tc.in)
+			if tc.isErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, got)
+		})
+	}
+}